@@ -0,0 +1,221 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/usi"
+)
+
+// Engine is the part of *usi.Engine used for analysis.
+type Engine interface {
+	SetPositionSFEN(sfen string, moves ...string) error
+	Go(ctx context.Context, params *usi.GoParams, onInfo func(*usi.Info)) (*usi.Result, error)
+}
+
+const (
+	scoreNotePrefix = "#評価値="
+	pvNotePrefix    = "読み筋="
+	mateNote        = "詰"
+)
+
+type annotator struct {
+	params   usi.GoParams
+	comments bool
+	progress func(seq int32)
+}
+
+type AnnotateOption func(*annotator)
+
+func AnnotateDepth(depth int) AnnotateOption {
+	return func(a *annotator) {
+		a.params.Depth = depth
+	}
+}
+
+func AnnotateMoveTime(d time.Duration) AnnotateOption {
+	return func(a *annotator) {
+		a.params.MoveTime = d
+	}
+}
+
+func AnnotateNodes(nodes int64) AnnotateOption {
+	return func(a *annotator) {
+		a.params.Nodes = nodes
+	}
+}
+
+// AnnotateComments enables writing evaluations to the notes of each step.
+func AnnotateComments(comments bool) AnnotateOption {
+	return func(a *annotator) {
+		a.comments = comments
+	}
+}
+
+func AnnotateProgress(f func(seq int32)) AnnotateOption {
+	return func(a *annotator) {
+		a.progress = f
+	}
+}
+
+// Annotate evaluates the position after every move of the main line and
+// stores the score and the principal variation in Step.Evaluation.
+func Annotate(ctx context.Context, k *ptypes.Kif, e Engine, ops ...AnnotateOption) error {
+	a := &annotator{
+		comments: true,
+		progress: func(int32) {},
+	}
+	for _, f := range ops {
+		f(a)
+	}
+	if a.params.Depth == 0 && a.params.MoveTime == 0 && a.params.Nodes == 0 {
+		a.params.MoveTime = time.Second
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return err
+	}
+	sfen := b.SFEN()
+
+	var moves []string
+	for _, step := range k.Steps {
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		moves = append(moves, kif.StepToMove(b.Last))
+
+		if err := e.SetPositionSFEN(sfen, moves...); err != nil {
+			return err
+		}
+		params := a.params
+		r, err := e.Go(ctx, &params, nil)
+		if err != nil {
+			return errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		if r.Info == nil || r.Info.Score == nil {
+			continue
+		}
+
+		step.Evaluation = newEvaluation(r.Info, b.Turn)
+		if a.comments {
+			pv, err := FormatPV(b, r.Info.PV)
+			if err != nil {
+				return errors.Wrapf(err, "seq=%v", step.Seq)
+			}
+			setNotes(step, pv)
+		}
+		a.progress(step.Seq)
+	}
+
+	return nil
+}
+
+// newEvaluation converts a score from the side to move to Sente's point of view.
+func newEvaluation(info *usi.Info, turn board.Side) *ptypes.Evaluation {
+	sign := int32(1)
+	if turn == board.Gote {
+		sign = -1
+	}
+	return &ptypes.Evaluation{
+		ScoreCp: sign * int32(info.Score.Cp),
+		Mate:    sign * int32(info.Score.Mate),
+		IsMate:  info.Score.IsMate,
+		Depth:   int32(info.Depth),
+		Pv:      info.PV,
+	}
+}
+
+// FormatPV formats USI moves from the position in KIF notation, like "▲７六歩(77)△３四歩(33)".
+func FormatPV(b *board.Board, pv []string) (string, error) {
+	b = b.Clone()
+	var sb strings.Builder
+	for _, m := range pv {
+		step, err := b.StepFromUSI(m)
+		if err != nil {
+			return "", err
+		}
+		if err := b.Apply(step); err != nil {
+			return "", err
+		}
+		sb.WriteString(kif.PrintMove(step))
+	}
+	return sb.String(), nil
+}
+
+func FormatScore(e *ptypes.Evaluation) string {
+	if e.IsMate {
+		if e.Mate < 0 {
+			return fmt.Sprintf("-%s%d", mateNote, -e.Mate)
+		}
+		return fmt.Sprintf("%s%d", mateNote, e.Mate)
+	}
+	return strconv.Itoa(int(e.ScoreCp))
+}
+
+func setNotes(step *ptypes.Step, pv string) {
+	var notes []string
+	for _, n := range step.Notes {
+		if !strings.HasPrefix(n, scoreNotePrefix) && !strings.HasPrefix(n, pvNotePrefix) {
+			notes = append(notes, n)
+		}
+	}
+	notes = append(notes, scoreNotePrefix+FormatScore(step.Evaluation))
+	if pv != "" {
+		notes = append(notes, pvNotePrefix+pv)
+	}
+	step.Notes = notes
+}
+
+func parseScore(s string) (*ptypes.Evaluation, bool) {
+	e := &ptypes.Evaluation{}
+	neg := strings.HasPrefix(s, "-")
+	if t := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+"); strings.HasPrefix(t, mateNote) {
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(t, mateNote)))
+		if err != nil {
+			return nil, false
+		}
+		e.IsMate = true
+		e.Mate = int32(n)
+		if neg {
+			e.Mate = -e.Mate
+		}
+		return e, true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, false
+	}
+	e.ScoreCp = int32(n)
+	return e, true
+}
+
+// LoadEvaluations fills Step.Evaluation from 評価値 notes, for games
+// annotated earlier and saved as KIF. Existing evaluations are kept.
+func LoadEvaluations(k *ptypes.Kif) {
+	for _, step := range k.Steps {
+		if step.Evaluation != nil {
+			continue
+		}
+		for _, n := range step.Notes {
+			if !strings.HasPrefix(n, scoreNotePrefix) {
+				continue
+			}
+			if e, ok := parseScore(strings.TrimPrefix(n, scoreNotePrefix)); ok {
+				step.Evaluation = e
+			}
+		}
+	}
+}
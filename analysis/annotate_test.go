@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/usi"
+)
+
+// fakeEngine scores every position +100 for the side to move.
+type fakeEngine struct {
+	moves []string
+}
+
+func (e *fakeEngine) SetPositionSFEN(sfen string, moves ...string) error {
+	e.moves = moves
+	return nil
+}
+
+func (e *fakeEngine) Go(ctx context.Context, params *usi.GoParams, onInfo func(*usi.Info)) (*usi.Result, error) {
+	pv := []string{"2g2f", "8c8d"}
+	if len(e.moves)%2 == 1 {
+		pv = []string{"8c8d", "2g2f"}
+	}
+	return &usi.Result{
+		BestMove: pv[0],
+		Info: &usi.Info{
+			Depth: params.Depth,
+			Score: &usi.Score{Cp: 100},
+			PV:    pv,
+		},
+	}, nil
+}
+
+func TestAnnotate(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}, Notes: []string{"#評価値=0", "comment"}},
+			{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}},
+			{Seq: 3, FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+		},
+	}
+
+	if err := Annotate(context.Background(), k, &fakeEngine{}, AnnotateDepth(10)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e1 := k.Steps[0].Evaluation
+	if e1 == nil || e1.ScoreCp != -100 || e1.Depth != 10 || e1.Pv[0] != "8c8d" {
+		t.Errorf("seq=1: unexpected evaluation: %v", e1)
+	}
+	if e2 := k.Steps[1].Evaluation; e2 == nil || e2.ScoreCp != 100 {
+		t.Errorf("seq=2: unexpected evaluation: %v", e2)
+	}
+	if k.Steps[2].Evaluation != nil {
+		t.Errorf("finished step must not be evaluated")
+	}
+
+	if e, a := "comment,#評価値=-100,読み筋=△８四歩(83)▲２六歩(27)", strings.Join(k.Steps[0].Notes, ","); e != a {
+		t.Errorf("expected=%v actual=%v", e, a)
+	}
+
+	for _, s := range k.Steps {
+		s.Evaluation = nil
+	}
+	k.Steps[1].Notes = append(k.Steps[1].Notes, "#評価値=-詰3")
+	LoadEvaluations(k)
+	if e := k.Steps[0].Evaluation; e == nil || e.ScoreCp != -100 {
+		t.Errorf("seq=1: unexpected evaluation: %v", e)
+	}
+	if e := k.Steps[1].Evaluation; e == nil || !e.IsMate || e.Mate != -3 {
+		t.Errorf("seq=2: unexpected evaluation: %v", e)
+	}
+}
@@ -0,0 +1,205 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type Class int
+
+const (
+	Class_BEST Class = iota
+	Class_GOOD
+	Class_INACCURACY
+	Class_MISTAKE
+	Class_BLUNDER
+
+	numClasses = iota
+)
+
+var classNames = []string{
+	"best",
+	"good",
+	"inaccuracy",
+	"mistake",
+	"blunder",
+}
+
+func (c Class) String() string {
+	return classNames[c]
+}
+
+// Thresholds are drops of the mover's win probability (0-1).
+type Thresholds struct {
+	Best       float64
+	Inaccuracy float64
+	Mistake    float64
+	Blunder    float64
+}
+
+var DefaultThresholds = Thresholds{
+	Best:       0.01,
+	Inaccuracy: 0.05,
+	Mistake:    0.10,
+	Blunder:    0.20,
+}
+
+type classifier struct {
+	thresholds Thresholds
+	scale      float64
+}
+
+type ClassifyOption func(*classifier)
+
+func ClassifyThresholds(t Thresholds) ClassifyOption {
+	return func(c *classifier) {
+		c.thresholds = t
+	}
+}
+
+// ClassifyScale sets the centipawns of the logistic curve converting
+// scores to win probabilities. The default is 600.
+func ClassifyScale(scale float64) ClassifyOption {
+	return func(c *classifier) {
+		c.scale = scale
+	}
+}
+
+// WinProbability returns Sente's win probability of the evaluation.
+func WinProbability(e *ptypes.Evaluation, scale float64) float64 {
+	if e.IsMate {
+		if e.Mate < 0 {
+			return 0
+		}
+		return 1
+	}
+	return 1 / (1 + math.Exp(-float64(e.ScoreCp)/scale))
+}
+
+type MoveClass struct {
+	Seq  int32
+	Side board.Side
+	// Before and After are the mover's win probabilities.
+	Before float64
+	After  float64
+	Loss   float64
+	Class  Class
+	// Accuracy is the score of the move in percent.
+	Accuracy float64
+}
+
+type Summary struct {
+	Side        board.Side
+	Moves       int
+	Counts      [numClasses]int
+	Accuracy    float64
+	AverageLoss float64
+}
+
+type Report struct {
+	Moves   []*MoveClass
+	Players [2]*Summary
+}
+
+// moveAccuracy maps the loss of win probability to 0-100,
+// following the formula used by lichess.
+func moveAccuracy(loss float64) float64 {
+	a := 103.1668*math.Exp(-0.04354*loss*100) - 3.1669
+	return math.Max(0, math.Min(100, a))
+}
+
+func (c *classifier) class(loss float64, best bool) Class {
+	t := c.thresholds
+	switch {
+	case best || loss < t.Best:
+		return Class_BEST
+	case loss < t.Inaccuracy:
+		return Class_GOOD
+	case loss < t.Mistake:
+		return Class_INACCURACY
+	case loss < t.Blunder:
+		return Class_MISTAKE
+	default:
+		return Class_BLUNDER
+	}
+}
+
+// Classify classifies the moves of the main line by the drop of the
+// mover's win probability between Step.Evaluation of the previous and
+// the current step. Moves without both evaluations are skipped.
+// A move equal to the first move of the previous principal variation is best.
+func Classify(k *ptypes.Kif, ops ...ClassifyOption) (*Report, error) {
+	c := &classifier{
+		thresholds: DefaultThresholds,
+		scale:      600,
+	}
+	for _, f := range ops {
+		f(c)
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{}
+	for i := range r.Players {
+		r.Players[i] = &Summary{Side: board.Side(i)}
+	}
+
+	var prev *ptypes.Evaluation
+	for _, step := range k.Steps {
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		side := b.Turn
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		curr := step.Evaluation
+		if prev == nil || curr == nil {
+			prev = curr
+			continue
+		}
+
+		before, after := WinProbability(prev, c.scale), WinProbability(curr, c.scale)
+		if side == board.Gote {
+			before, after = 1-before, 1-after
+		}
+		loss := math.Max(0, before-after)
+		best := len(prev.Pv) != 0 && prev.Pv[0] == kif.StepToMove(b.Last)
+
+		m := &MoveClass{
+			Seq:      step.Seq,
+			Side:     side,
+			Before:   before,
+			After:    after,
+			Loss:     loss,
+			Class:    c.class(loss, best),
+			Accuracy: moveAccuracy(loss),
+		}
+		r.Moves = append(r.Moves, m)
+
+		s := r.Players[side]
+		s.Moves++
+		s.Counts[m.Class]++
+		s.Accuracy += m.Accuracy
+		s.AverageLoss += m.Loss
+
+		prev = curr
+	}
+
+	for _, s := range r.Players {
+		if s.Moves != 0 {
+			s.Accuracy /= float64(s.Moves)
+			s.AverageLoss /= float64(s.Moves)
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestClassify(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7},
+				Evaluation: &ptypes.Evaluation{ScoreCp: 50, Pv: []string{"3c3d"}}},
+			{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3},
+				Evaluation: &ptypes.Evaluation{ScoreCp: 60}},
+			{Seq: 3, Dst: &ptypes.Pos{X: 2, Y: 2}, Piece: ptypes.Piece_KAKU, Modifier: ptypes.Modifier_PROMOTE, Src: &ptypes.Pos{X: 8, Y: 8},
+				Evaluation: &ptypes.Evaluation{ScoreCp: -800}},
+			{Seq: 4, Piece: ptypes.Piece_GIN, Src: &ptypes.Pos{X: 3, Y: 1},
+				Evaluation: &ptypes.Evaluation{ScoreCp: -300}},
+			{Seq: 5, FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+		},
+	}
+
+	r, err := Classify(k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(r.Moves); l != 3 {
+		t.Fatalf("moves: expected=3 actual=%v", l)
+	}
+	for i, e := range []Class{Class_BEST, Class_BLUNDER, Class_MISTAKE} {
+		if m := r.Moves[i]; m.Class != e {
+			t.Errorf("seq=%v: expected=%v actual=%v (loss=%v)", m.Seq, e, m.Class, m.Loss)
+		}
+	}
+
+	s := r.Players[board.Sente]
+	if s.Moves != 1 || s.Counts[Class_BLUNDER] != 1 || s.Accuracy > 50 {
+		t.Errorf("unexpected sente summary: %+v", s)
+	}
+	g := r.Players[board.Gote]
+	if g.Moves != 2 || g.Counts[Class_BEST] != 1 {
+		t.Errorf("unexpected gote summary: %+v", g)
+	}
+}
@@ -0,0 +1,200 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+type Square struct {
+	Side  Side
+	Piece ptypes.Piece_Id
+}
+
+func (s Square) Empty() bool {
+	return s.Piece == ptypes.Piece_NULL
+}
+
+type Board struct {
+	squares [81]Square
+	hands   [2][numPieces]int
+
+	// Turn is the side to move.
+	Turn Side
+	// Ply is the number of moves applied since the initial position.
+	Ply int32
+	// Last is the last applied move with its destination resolved.
+	Last *ptypes.Step
+
+	startNum int32
+}
+
+func index(x, y int32) int {
+	return int((y-1)*9 + (x - 1))
+}
+
+func inside(x, y int32) bool {
+	return 1 <= x && x <= 9 && 1 <= y && y <= 9
+}
+
+// Empty returns a board with no pieces and Sente to move.
+func Empty() *Board {
+	return &Board{
+		startNum: 1,
+	}
+}
+
+// New returns the even game (平手) initial position.
+func New() *Board {
+	b, err := FromSFEN(StartPos)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (b *Board) Clone() *Board {
+	ret := *b
+	return &ret
+}
+
+func (b *Board) At(x, y int32) Square {
+	if !inside(x, y) {
+		return Square{}
+	}
+	return b.squares[index(x, y)]
+}
+
+func (b *Board) Set(x, y int32, sq Square) {
+	b.squares[index(x, y)] = sq
+}
+
+func (b *Board) Hand(side Side, p ptypes.Piece_Id) int {
+	return b.hands[side][p]
+}
+
+func (b *Board) SetHand(side Side, p ptypes.Piece_Id, n int) {
+	b.hands[side][p] = n
+}
+
+// King returns the position of the king of the side, or nil.
+func (b *Board) King(side Side) *ptypes.Pos {
+	for i, sq := range b.squares {
+		if sq.Piece == ptypes.Piece_GYOKU && sq.Side == side {
+			return &ptypes.Pos{X: int32(i%9) + 1, Y: int32(i/9) + 1}
+		}
+	}
+	return nil
+}
+
+func validPos(p *ptypes.Pos) bool {
+	return p != nil && inside(p.X, p.Y)
+}
+
+func (b *Board) Apply(step *ptypes.Step) error {
+	if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+		return nil
+	}
+
+	dst := step.Dst
+	if dst == nil {
+		if b.Last == nil {
+			return fmt.Errorf("no previous move for 同")
+		}
+		dst = b.Last.Dst
+	}
+	if !validPos(dst) {
+		return fmt.Errorf("invalid destination: %v", dst)
+	}
+
+	side := b.Turn
+	target := b.At(dst.X, dst.Y)
+	if !target.Empty() && target.Side == side {
+		return fmt.Errorf("destination is occupied by own piece: %v", dst)
+	}
+
+	if step.Modifier == ptypes.Modifier_PUTTED || !validPos(step.Src) {
+		if !target.Empty() {
+			return fmt.Errorf("drop to occupied square: %v", dst)
+		}
+		if IsPromoted(step.Piece) || step.Piece == ptypes.Piece_GYOKU || step.Piece == ptypes.Piece_NULL {
+			return fmt.Errorf("cannot drop %v", step.Piece)
+		}
+		if b.hands[side][step.Piece] == 0 {
+			return fmt.Errorf("%v has no %v in hand", side, step.Piece)
+		}
+		b.hands[side][step.Piece]--
+		b.Set(dst.X, dst.Y, Square{Side: side, Piece: step.Piece})
+	} else {
+		src := step.Src
+		sq := b.At(src.X, src.Y)
+		if sq.Empty() || sq.Side != side {
+			return fmt.Errorf("no piece of %v at %v", side, src)
+		}
+		if step.Piece != ptypes.Piece_NULL && sq.Piece != step.Piece {
+			return fmt.Errorf("piece mismatch at %v: board=%v move=%v", src, sq.Piece, step.Piece)
+		}
+
+		p := sq.Piece
+		if step.Modifier == ptypes.Modifier_PROMOTE {
+			if !CanPromote(p) {
+				return fmt.Errorf("cannot promote %v", p)
+			}
+			p = Promote(p)
+		}
+
+		if !target.Empty() {
+			b.hands[side][Demote(target.Piece)]++
+		}
+		b.Set(src.X, src.Y, Square{})
+		b.Set(dst.X, dst.Y, Square{Side: side, Piece: p})
+	}
+
+	b.Last = &ptypes.Step{
+		Seq:      step.Seq,
+		Dst:      dst,
+		Piece:    step.Piece,
+		Modifier: step.Modifier,
+		Src:      step.Src,
+	}
+	b.Turn = side.Opponent()
+	b.Ply++
+
+	return nil
+}
+
+// FromKif returns the initial position of the game according to
+// the 手合割 header.
+func FromKif(k *ptypes.Kif) (*Board, error) {
+	for _, h := range k.GetHeaders() {
+		if h.Name == handicapHeaderName {
+			return NewHandicap(h.Value)
+		}
+	}
+	return New(), nil
+}
+
+// AtPly returns the position after ply moves of the main line.
+// A negative ply means the end of the game.
+func AtPly(k *ptypes.Kif, ply int32) (*Board, error) {
+	b, err := FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range k.GetSteps() {
+		if ply >= 0 && b.Ply >= ply {
+			break
+		}
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+	}
+
+	return b, nil
+}
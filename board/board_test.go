@@ -0,0 +1,76 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestBoard_Apply(t *testing.T) {
+	b := New()
+
+	steps := []*ptypes.Step{
+		{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+		{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}},
+		{Seq: 3, Dst: &ptypes.Pos{X: 2, Y: 2}, Piece: ptypes.Piece_KAKU, Modifier: ptypes.Modifier_PROMOTE, Src: &ptypes.Pos{X: 8, Y: 8}},
+		{Seq: 4, Piece: ptypes.Piece_GIN, Src: &ptypes.Pos{X: 3, Y: 1}},
+		{Seq: 5, Dst: &ptypes.Pos{X: 4, Y: 5}, Piece: ptypes.Piece_KAKU, Modifier: ptypes.Modifier_PUTTED},
+	}
+	for _, s := range steps {
+		if err := b.Apply(s); err != nil {
+			t.Fatalf("seq=%v: unexpected error: %v", s.Seq, err)
+		}
+	}
+
+	if sq := b.At(2, 2); sq.Piece != ptypes.Piece_GIN || sq.Side != Gote {
+		t.Errorf("2二: expected=Gote GIN actual=%v", sq)
+	}
+	if n := b.Hand(Gote, ptypes.Piece_KAKU); n != 1 {
+		t.Errorf("gote KAKU in hand: expected=1 actual=%v", n)
+	}
+	if sq := b.At(4, 5); sq.Piece != ptypes.Piece_KAKU || sq.Side != Sente {
+		t.Errorf("4五: expected=Sente KAKU actual=%v", sq)
+	}
+	if b.Turn != Gote {
+		t.Errorf("turn: expected=Gote actual=%v", b.Turn)
+	}
+
+	drop := &ptypes.Step{Seq: 6, Dst: &ptypes.Pos{X: 5, Y: 5}, Piece: ptypes.Piece_GIN, Modifier: ptypes.Modifier_PUTTED}
+	if err := b.Apply(drop); err == nil {
+		t.Errorf("drop without piece in hand must fail")
+	}
+}
+
+func TestSFEN(t *testing.T) {
+	for _, s := range []string{
+		StartPos,
+		"lnsgkgsn1/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL w - 1",
+		"8l/1l+R2P3/p2pBG1pp/kps1p4/Nn1P2G2/P1P1P2PP/1PS6/1KSG3+r1/LN2+p3L w Sbgn3p 124",
+	} {
+		b, err := FromSFEN(s)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", s, err)
+		}
+		if a := b.SFEN(); a != s {
+			t.Errorf("expected=%v actual=%v", s, a)
+		}
+	}
+
+	if _, err := FromSFEN("lnsgkgsnl/9 b - 1"); err == nil {
+		t.Errorf("invalid sfen must fail")
+	}
+}
+
+func TestNewHandicap(t *testing.T) {
+	b, err := NewHandicap("香落ち")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := "lnsgkgsn1/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL w - 1", b.SFEN(); e != a {
+		t.Errorf("expected=%v actual=%v", e, a)
+	}
+
+	if _, err := NewHandicap("九枚落ち"); err == nil {
+		t.Errorf("unknown handicap must fail")
+	}
+}
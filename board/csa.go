@@ -0,0 +1,275 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+var csaPieces = []string{
+	"",
+	"OU",
+	"HI",
+	"RY",
+	"KA",
+	"UM",
+	"KI",
+	"GI",
+	"NG",
+	"KE",
+	"NK",
+	"KY",
+	"NY",
+	"FU",
+	"TO",
+}
+
+// PieceFromCSA returns the piece of the two-letter name, or Piece_NULL.
+func PieceFromCSA(s string) ptypes.Piece_Id {
+	for i, n := range csaPieces {
+		if n != "" && n == s {
+			return ptypes.Piece_Id(i)
+		}
+	}
+	return ptypes.Piece_NULL
+}
+
+// CSAName returns the two-letter name of the piece used by CSA and JKF, like "FU".
+func CSAName(p ptypes.Piece_Id) string {
+	return csaPieces[p]
+}
+
+func csaSign(side Side) string {
+	if side == Sente {
+		return "+"
+	}
+	return "-"
+}
+
+func csaSide(c byte) (Side, bool) {
+	switch c {
+	case '+':
+		return Sente, true
+	case '-':
+		return Gote, true
+	default:
+		return Sente, false
+	}
+}
+
+// csaPos parses two digits. "00" is valid and returns nil.
+func csaPos(s string) (*ptypes.Pos, bool) {
+	if len(s) != 2 || s[0] < '0' || '9' < s[0] || s[1] < '0' || '9' < s[1] {
+		return nil, false
+	}
+	if s == "00" {
+		return nil, true
+	}
+	p := &ptypes.Pos{X: int32(s[0] - '0'), Y: int32(s[1] - '0')}
+	if !validPos(p) {
+		return nil, false
+	}
+	return p, true
+}
+
+// StepFromCSA converts a move in CSA notation ("+7776FU", "-0055KA") to a step
+// in this position. The board is not modified.
+func (b *Board) StepFromCSA(m string) (*ptypes.Step, error) {
+	if len(m) != 7 {
+		return nil, fmt.Errorf("invalid csa move: %q", m)
+	}
+	side, ok := csaSide(m[0])
+	if !ok {
+		return nil, fmt.Errorf("invalid csa move: %q", m)
+	}
+	if side != b.Turn {
+		return nil, fmt.Errorf("not the turn of %v: %q", side, m)
+	}
+	src, ok1 := csaPos(m[1:3])
+	dst, ok2 := csaPos(m[3:5])
+	p := PieceFromCSA(m[5:7])
+	if !ok1 || !ok2 || dst == nil || p == ptypes.Piece_NULL {
+		return nil, fmt.Errorf("invalid csa move: %q", m)
+	}
+
+	step := &ptypes.Step{
+		Seq: b.startNum + b.Ply,
+		Dst: dst,
+	}
+	if src == nil {
+		step.Piece = p
+		step.Modifier = ptypes.Modifier_PUTTED
+		return step, nil
+	}
+
+	sq := b.At(src.X, src.Y)
+	switch {
+	case sq.Empty():
+		return nil, fmt.Errorf("no piece at the source of %q", m)
+	case sq.Piece == p:
+	case CanPromote(sq.Piece) && Promote(sq.Piece) == p:
+		step.Modifier = ptypes.Modifier_PROMOTE
+	default:
+		return nil, fmt.Errorf("piece mismatch: board=%v move=%q", sq.Piece, m)
+	}
+	step.Src = src
+	step.Piece = sq.Piece
+
+	return step, nil
+}
+
+// CSAMove formats a step in this position in CSA notation.
+func (b *Board) CSAMove(step *ptypes.Step) (string, error) {
+	dst := step.Dst
+	if dst == nil && b.Last != nil {
+		dst = b.Last.Dst
+	}
+	if !validPos(dst) {
+		return "", fmt.Errorf("invalid destination: %v", step.Dst)
+	}
+
+	src := "00"
+	p := step.Piece
+	if step.Modifier != ptypes.Modifier_PUTTED && validPos(step.Src) {
+		src = fmt.Sprintf("%d%d", step.Src.X, step.Src.Y)
+		if p == ptypes.Piece_NULL {
+			p = b.At(step.Src.X, step.Src.Y).Piece
+		}
+		if step.Modifier == ptypes.Modifier_PROMOTE {
+			p = Promote(p)
+		}
+	}
+	if p == ptypes.Piece_NULL {
+		return "", fmt.Errorf("unknown piece: %v", step)
+	}
+
+	return fmt.Sprintf("%s%s%d%d%s", csaSign(b.Turn), src, dst.X, dst.Y, csaPieces[p]), nil
+}
+
+// FromCSA returns the position given by the lines of a CSA position section:
+// "PI" with optional removed pieces, or "P1" to "P9", followed by "P+" and "P-"
+// for additional pieces, and "+" or "-" for the side to move.
+func FromCSA(lines []string) (*Board, error) {
+	var b *Board
+	for _, line := range lines {
+		switch {
+		case line == "" || line[0] == '\'':
+		case strings.HasPrefix(line, "PI"):
+			b = New()
+			rest := line[2:]
+			for ; len(rest) >= 4; rest = rest[4:] {
+				pos, ok := csaPos(rest[:2])
+				p := PieceFromCSA(rest[2:4])
+				if !ok || pos == nil || p == ptypes.Piece_NULL || b.At(pos.X, pos.Y).Piece != p {
+					return nil, fmt.Errorf("invalid csa position: %q", line)
+				}
+				b.Set(pos.X, pos.Y, Square{})
+			}
+			if rest != "" {
+				return nil, fmt.Errorf("invalid csa position: %q", line)
+			}
+		case len(line) >= 2 && line[0] == 'P' && '1' <= line[1] && line[1] <= '9':
+			if b == nil {
+				b = Empty()
+			}
+			y := int32(line[1] - '0')
+			// the trailing space of the last empty square is often trimmed.
+			rank := line[2:]
+			if len(rank) < 27 {
+				rank += strings.Repeat(" ", 27-len(rank))
+			}
+			if len(rank) != 27 {
+				return nil, fmt.Errorf("invalid csa position: %q", line)
+			}
+			for x := int32(9); x >= 1; x-- {
+				cell := rank[:3]
+				rank = rank[3:]
+				if strings.TrimSpace(cell) == "*" {
+					b.Set(x, y, Square{})
+					continue
+				}
+				side, ok := csaSide(cell[0])
+				p := PieceFromCSA(cell[1:])
+				if !ok || p == ptypes.Piece_NULL {
+					return nil, fmt.Errorf("invalid csa position: %q", line)
+				}
+				b.Set(x, y, Square{Side: side, Piece: p})
+			}
+		case strings.HasPrefix(line, "P+") || strings.HasPrefix(line, "P-"):
+			if b == nil {
+				b = Empty()
+			}
+			side, _ := csaSide(line[1])
+			rest := line[2:]
+			for ; len(rest) >= 4; rest = rest[4:] {
+				pos, ok := csaPos(rest[:2])
+				p := PieceFromCSA(rest[2:4])
+				if !ok || p == ptypes.Piece_NULL {
+					return nil, fmt.Errorf("invalid csa position: %q", line)
+				}
+				if pos == nil {
+					if IsPromoted(p) || p == ptypes.Piece_GYOKU {
+						return nil, fmt.Errorf("invalid piece in hand: %q", line)
+					}
+					b.hands[side][p]++
+				} else {
+					b.Set(pos.X, pos.Y, Square{Side: side, Piece: p})
+				}
+			}
+			if rest != "" {
+				return nil, fmt.Errorf("invalid csa position: %q", line)
+			}
+		case line == "+" || line == "-":
+			if b == nil {
+				return nil, fmt.Errorf("csa position without board")
+			}
+			b.Turn, _ = csaSide(line[0])
+		default:
+			return nil, fmt.Errorf("invalid csa position: %q", line)
+		}
+	}
+	if b == nil {
+		return nil, fmt.Errorf("csa position without board")
+	}
+	return b, nil
+}
+
+// CSA returns the lines of the position in CSA notation.
+func (b *Board) CSA() []string {
+	var ret []string
+	for y := int32(1); y <= 9; y++ {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "P%d", y)
+		for x := int32(9); x >= 1; x-- {
+			sq := b.At(x, y)
+			if sq.Empty() {
+				sb.WriteString(" * ")
+				continue
+			}
+			sb.WriteString(csaSign(sq.Side) + csaPieces[sq.Piece])
+		}
+		ret = append(ret, sb.String())
+	}
+	for _, side := range []Side{Sente, Gote} {
+		line := "P" + csaSign(side)
+		for _, p := range HandPieces {
+			line += strings.Repeat("00"+csaPieces[p], b.hands[side][p])
+		}
+		ret = append(ret, line)
+	}
+	return append(ret, csaSign(b.Turn))
+}
+
+// HandicapName returns the 手合割 name of the position, if it is the initial
+// position of one.
+func HandicapName(b *Board) (string, bool) {
+	key := b.Key()
+	for name := range Handicaps {
+		h, _ := NewHandicap(name)
+		if h.Key() == key {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestCSA(t *testing.T) {
+	for _, s := range []string{
+		StartPos,
+		"8l/1l+R2P3/p2pBG1pp/kps1p4/Nn1P2G2/P1P1P2PP/1PS6/1KSG3+r1/LN2+p3L w Sbgn3p 1",
+	} {
+		b := mustSFEN(t, s)
+		a, err := FromCSA(b.CSA())
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", s, err)
+		}
+		if a.SFEN() != s {
+			t.Errorf("expected=%v actual=%v", s, a.SFEN())
+		}
+	}
+
+	b, err := FromCSA([]string{"PI22KA", "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name, ok := HandicapName(b); !ok || name != "角落ち" {
+		t.Errorf("expected=角落ち actual=%v", name)
+	}
+
+	// trailing spaces trimmed
+	lines := New().CSA()
+	for i := range lines {
+		for len(lines[i]) > 0 && lines[i][len(lines[i])-1] == ' ' {
+			lines[i] = lines[i][:len(lines[i])-1]
+		}
+	}
+	if a, err := FromCSA(lines); err != nil || a.SFEN() != StartPos {
+		t.Errorf("unexpected result: %v", err)
+	}
+}
+
+func TestStepFromCSA(t *testing.T) {
+	b := New()
+	for _, m := range []string{"+7776FU", "-3334FU", "+8822UM", "-3122GI", "+0045KA"} {
+		step, err := b.StepFromCSA(m)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", m, err)
+		}
+		s, err := b.CSAMove(step)
+		if err != nil || s != m {
+			t.Errorf("expected=%v actual=%v err=%v", m, s, err)
+		}
+		if err := b.Apply(step); err != nil {
+			t.Fatalf("%v: unexpected error: %v", m, err)
+		}
+	}
+	if sq := b.At(4, 5); sq.Piece != ptypes.Piece_KAKU || sq.Side != Sente {
+		t.Errorf("4五: expected=Sente KAKU actual=%v", sq)
+	}
+
+	if _, err := b.StepFromCSA("+5756FU"); err == nil {
+		t.Errorf("move of the opponent's turn must fail")
+	}
+}
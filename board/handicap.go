@@ -0,0 +1,70 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+const handicapHeaderName = "手合割"
+
+var (
+	gLeftKyou   = &ptypes.Pos{X: 1, Y: 1}
+	gRightKyou  = &ptypes.Pos{X: 9, Y: 1}
+	gLeftKei    = &ptypes.Pos{X: 2, Y: 1}
+	gRightKei   = &ptypes.Pos{X: 8, Y: 1}
+	gLeftGin    = &ptypes.Pos{X: 3, Y: 1}
+	gRightGin   = &ptypes.Pos{X: 7, Y: 1}
+	gLeftKin    = &ptypes.Pos{X: 4, Y: 1}
+	gRightKin   = &ptypes.Pos{X: 6, Y: 1}
+	gKaku       = &ptypes.Pos{X: 2, Y: 2}
+	gHisha      = &ptypes.Pos{X: 8, Y: 2}
+	gTwoPieces  = []*ptypes.Pos{gHisha, gKaku}
+	gFourPieces = []*ptypes.Pos{gHisha, gKaku, gLeftKyou, gRightKyou}
+	gSixPieces  = []*ptypes.Pos{gHisha, gKaku, gLeftKyou, gRightKyou, gLeftKei, gRightKei}
+)
+
+func concat(a []*ptypes.Pos, b ...*ptypes.Pos) []*ptypes.Pos {
+	return append(append([]*ptypes.Pos{}, a...), b...)
+}
+
+// Handicaps maps 手合割 names to the squares of the pieces Gote (上手) removes.
+var Handicaps = map[string][]*ptypes.Pos{
+	"平手":    nil,
+	"香落ち":   {gLeftKyou},
+	"右香落ち":  {gRightKyou},
+	"角落ち":   {gKaku},
+	"飛車落ち":  {gHisha},
+	"飛香落ち":  {gHisha, gLeftKyou},
+	"二枚落ち":  gTwoPieces,
+	"三枚落ち":  concat(gTwoPieces, gLeftKyou),
+	"四枚落ち":  gFourPieces,
+	"五枚落ち":  concat(gFourPieces, gLeftKei),
+	"左五枚落ち": concat(gFourPieces, gRightKei),
+	"六枚落ち":  gSixPieces,
+	"左七枚落ち": concat(gSixPieces, gRightGin),
+	"右七枚落ち": concat(gSixPieces, gLeftGin),
+	"八枚落ち":  concat(gSixPieces, gLeftGin, gRightGin),
+	"十枚落ち":  concat(gSixPieces, gLeftGin, gRightGin, gLeftKin, gRightKin),
+}
+
+// NewHandicap returns the initial position of the named handicap.
+// Gote moves first in handicap games.
+func NewHandicap(name string) (*Board, error) {
+	removed, ok := Handicaps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown handicap: %v", name)
+	}
+
+	b := New()
+	if name == "平手" {
+		return b, nil
+	}
+
+	for _, p := range removed {
+		b.Set(p.X, p.Y, Square{})
+	}
+	b.Turn = Gote
+
+	return b, nil
+}
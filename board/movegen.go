@@ -0,0 +1,287 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+type direction struct {
+	dx, dy int32
+	slide  bool
+}
+
+var (
+	kinDirs  = []direction{{-1, -1, false}, {0, -1, false}, {1, -1, false}, {-1, 0, false}, {1, 0, false}, {0, 1, false}}
+	ginDirs  = []direction{{-1, -1, false}, {0, -1, false}, {1, -1, false}, {-1, 1, false}, {1, 1, false}}
+	kingDirs = []direction{
+		{-1, -1, false}, {0, -1, false}, {1, -1, false},
+		{-1, 0, false}, {1, 0, false},
+		{-1, 1, false}, {0, 1, false}, {1, 1, false},
+	}
+	hishaDirs = []direction{{0, -1, true}, {0, 1, true}, {-1, 0, true}, {1, 0, true}}
+	kakuDirs  = []direction{{-1, -1, true}, {1, -1, true}, {-1, 1, true}, {1, 1, true}}
+)
+
+// directions of the pieces of Sente. Gote's are upside down.
+var pieceDirs = [numPieces][]direction{
+	ptypes.Piece_GYOKU:     kingDirs,
+	ptypes.Piece_HISHA:     hishaDirs,
+	ptypes.Piece_RYU:       append(append([]direction{}, hishaDirs...), direction{-1, -1, false}, direction{1, -1, false}, direction{-1, 1, false}, direction{1, 1, false}),
+	ptypes.Piece_KAKU:      kakuDirs,
+	ptypes.Piece_UMA:       append(append([]direction{}, kakuDirs...), direction{0, -1, false}, direction{0, 1, false}, direction{-1, 0, false}, direction{1, 0, false}),
+	ptypes.Piece_KIN:       kinDirs,
+	ptypes.Piece_GIN:       ginDirs,
+	ptypes.Piece_NARI_GIN:  kinDirs,
+	ptypes.Piece_KEI:       {{-1, -2, false}, {1, -2, false}},
+	ptypes.Piece_NARI_KEI:  kinDirs,
+	ptypes.Piece_KYOU:      {{0, -1, true}},
+	ptypes.Piece_NARI_KYOU: kinDirs,
+	ptypes.Piece_FU:        {{0, -1, false}},
+	ptypes.Piece_TO:        kinDirs,
+}
+
+// relativeRank returns the rank counted from the side's far end (1 is the last rank).
+func relativeRank(side Side, y int32) int32 {
+	if side == Sente {
+		return y
+	}
+	return 10 - y
+}
+
+func inPromotionZone(side Side, y int32) bool {
+	return relativeRank(side, y) <= 3
+}
+
+// deadEnd reports whether the piece would have no moves on the rank.
+func deadEnd(side Side, p ptypes.Piece_Id, y int32) bool {
+	r := relativeRank(side, y)
+	switch p {
+	case ptypes.Piece_FU, ptypes.Piece_KYOU:
+		return r == 1
+	case ptypes.Piece_KEI:
+		return r <= 2
+	default:
+		return false
+	}
+}
+
+// targets calls f for every square the piece at (x, y) attacks.
+func (b *Board) targets(x, y int32, f func(tx, ty int32)) {
+	sq := b.At(x, y)
+	sign := int32(1)
+	if sq.Side == Gote {
+		sign = -1
+	}
+	for _, d := range pieceDirs[sq.Piece] {
+		tx, ty := x+d.dx*sign, y+d.dy*sign
+		for inside(tx, ty) {
+			f(tx, ty)
+			if !d.slide || !b.At(tx, ty).Empty() {
+				break
+			}
+			tx, ty = tx+d.dx*sign, ty+d.dy*sign
+		}
+	}
+}
+
+// Attacked reports whether a piece of the side attacks the square.
+func (b *Board) Attacked(x, y int32, by Side) bool {
+	found := false
+	for i, sq := range b.squares {
+		if sq.Empty() || sq.Side != by {
+			continue
+		}
+		b.targets(int32(i%9)+1, int32(i/9)+1, func(tx, ty int32) {
+			if tx == x && ty == y {
+				found = true
+			}
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) InCheck(side Side) bool {
+	k := b.King(side)
+	if k == nil {
+		return false
+	}
+	return b.Attacked(k.X, k.Y, side.Opponent())
+}
+
+func (b *Board) newStep(src, dst *ptypes.Pos, p ptypes.Piece_Id, m ptypes.Modifier_Id) *ptypes.Step {
+	return &ptypes.Step{
+		Seq:      b.startNum + b.Ply,
+		Src:      src,
+		Dst:      dst,
+		Piece:    p,
+		Modifier: m,
+	}
+}
+
+// pseudoMoves returns the moves obeying the movement rules,
+// which may leave the king in check.
+func (b *Board) pseudoMoves() []*ptypes.Step {
+	side := b.Turn
+	var ret []*ptypes.Step
+
+	for i, sq := range b.squares {
+		if sq.Empty() || sq.Side != side {
+			continue
+		}
+		x, y := int32(i%9)+1, int32(i/9)+1
+		b.targets(x, y, func(tx, ty int32) {
+			if t := b.At(tx, ty); !t.Empty() && t.Side == side {
+				return
+			}
+			src, dst := &ptypes.Pos{X: x, Y: y}, &ptypes.Pos{X: tx, Y: ty}
+			if CanPromote(sq.Piece) && (inPromotionZone(side, y) || inPromotionZone(side, ty)) {
+				ret = append(ret, b.newStep(src, dst, sq.Piece, ptypes.Modifier_PROMOTE))
+			}
+			if !deadEnd(side, sq.Piece, ty) {
+				ret = append(ret, b.newStep(src, dst, sq.Piece, ptypes.Modifier_NULL))
+			}
+		})
+	}
+
+	for _, p := range HandPieces {
+		if b.hands[side][p] == 0 {
+			continue
+		}
+		for x := int32(1); x <= 9; x++ {
+			if p == ptypes.Piece_FU && b.hasPawn(side, x) {
+				continue
+			}
+			for y := int32(1); y <= 9; y++ {
+				if !b.At(x, y).Empty() || deadEnd(side, p, y) {
+					continue
+				}
+				ret = append(ret, b.newStep(nil, &ptypes.Pos{X: x, Y: y}, p, ptypes.Modifier_PUTTED))
+			}
+		}
+	}
+
+	return ret
+}
+
+func (b *Board) hasPawn(side Side, x int32) bool {
+	for y := int32(1); y <= 9; y++ {
+		if sq := b.At(x, y); sq.Piece == ptypes.Piece_FU && sq.Side == side {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) legalMoves(checkUchifuzume bool) []*ptypes.Step {
+	side := b.Turn
+	var ret []*ptypes.Step
+	for _, m := range b.pseudoMoves() {
+		next := b.Clone()
+		if err := next.Apply(m); err != nil {
+			continue
+		}
+		if next.InCheck(side) {
+			continue
+		}
+		if checkUchifuzume && m.Modifier == ptypes.Modifier_PUTTED && m.Piece == ptypes.Piece_FU &&
+			next.InCheck(side.Opponent()) && len(next.legalMoves(false)) == 0 {
+			continue
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// LegalMoves returns all legal moves of the side to move.
+func (b *Board) LegalMoves() []*ptypes.Step {
+	return b.legalMoves(true)
+}
+
+// Checkmated reports whether the side to move has no legal moves.
+func (b *Board) Checkmated() bool {
+	return len(b.legalMoves(true)) == 0
+}
+
+func samePos(a, b *ptypes.Pos) bool {
+	if !validPos(a) || !validPos(b) {
+		return !validPos(a) && !validPos(b)
+	}
+	return a.X == b.X && a.Y == b.Y
+}
+
+// Validate returns an error if the step is not a legal move in this position.
+func (b *Board) Validate(step *ptypes.Step) error {
+	dst := step.Dst
+	if dst == nil && b.Last != nil {
+		dst = b.Last.Dst
+	}
+	src := step.Src
+	if step.Modifier == ptypes.Modifier_PUTTED {
+		src = nil
+	}
+
+	for _, m := range b.LegalMoves() {
+		if samePos(m.Src, src) && samePos(m.Dst, dst) && m.Modifier == step.Modifier &&
+			(step.Piece == ptypes.Piece_NULL || m.Piece == step.Piece) {
+			return nil
+		}
+	}
+	return fmt.Errorf("illegal move: %v", step)
+}
+
+// Key identifies the position for repetition: the board, hands and side to move.
+func (b *Board) Key() string {
+	turn := "b"
+	if b.Turn == Gote {
+		turn = "w"
+	}
+	return b.sfenBoard() + " " + turn + " " + b.sfenHands()
+}
+
+var declarationPoints = [numPieces]int{
+	ptypes.Piece_HISHA: 5,
+	ptypes.Piece_RYU:   5,
+	ptypes.Piece_KAKU:  5,
+	ptypes.Piece_UMA:   5,
+}
+
+// CanDeclareWin reports whether the side can declare a win by entering king (入玉宣言),
+// according to the 27-point rule of the CSA protocol.
+func (b *Board) CanDeclareWin(side Side) bool {
+	k := b.King(side)
+	if k == nil || !inPromotionZone(side, k.Y) || b.InCheck(side) {
+		return false
+	}
+
+	count, points := 0, 0
+	for i, sq := range b.squares {
+		if sq.Empty() || sq.Side != side || sq.Piece == ptypes.Piece_GYOKU {
+			continue
+		}
+		if !inPromotionZone(side, int32(i/9)+1) {
+			continue
+		}
+		count++
+		points += piecePoint(sq.Piece)
+	}
+	for _, p := range HandPieces {
+		points += b.hands[side][p] * piecePoint(p)
+	}
+
+	required := 28
+	if side == Gote {
+		required = 27
+	}
+	return count >= 10 && points >= required
+}
+
+func piecePoint(p ptypes.Piece_Id) int {
+	if n := declarationPoints[p]; n != 0 {
+		return n
+	}
+	return 1
+}
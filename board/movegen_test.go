@@ -0,0 +1,98 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func mustSFEN(t *testing.T, s string) *Board {
+	t.Helper()
+	b, err := FromSFEN(s)
+	if err != nil {
+		t.Fatalf("%v: unexpected error: %v", s, err)
+	}
+	return b
+}
+
+func TestLegalMoves(t *testing.T) {
+	if n := len(New().LegalMoves()); n != 30 {
+		t.Errorf("initial position: expected=30 actual=%v", n)
+	}
+
+	// 二歩
+	b := mustSFEN(t, "4k4/9/9/9/9/4P4/9/9/4K4 b P 1")
+	for _, m := range b.LegalMoves() {
+		if m.Modifier == ptypes.Modifier_PUTTED && m.Dst.X == 5 {
+			t.Errorf("double pawn: %v", m)
+		}
+	}
+}
+
+func TestCheckmated(t *testing.T) {
+	b := mustSFEN(t, "4k4/4G4/4P4/9/9/9/9/9/4K4 w - 1")
+	if !b.InCheck(Gote) {
+		t.Errorf("expected check")
+	}
+	if !b.Checkmated() {
+		t.Errorf("expected checkmate")
+	}
+
+	if New().Checkmated() {
+		t.Errorf("initial position must not be checkmate")
+	}
+}
+
+func TestValidate_Uchifuzume(t *testing.T) {
+	b := mustSFEN(t, "3lkl3/9/4G4/9/9/9/9/9/4K4 b GP 1")
+
+	pawn := &ptypes.Step{Dst: &ptypes.Pos{X: 5, Y: 2}, Piece: ptypes.Piece_FU, Modifier: ptypes.Modifier_PUTTED}
+	if err := b.Validate(pawn); err == nil {
+		t.Errorf("pawn drop mate must be illegal")
+	}
+
+	gold := &ptypes.Step{Dst: &ptypes.Pos{X: 5, Y: 2}, Piece: ptypes.Piece_KIN, Modifier: ptypes.Modifier_PUTTED}
+	if err := b.Validate(gold); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	step, err := b.StepFromUSI("5c5b+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := b.Validate(step); err == nil {
+		t.Errorf("gold cannot promote")
+	}
+}
+
+func TestKey(t *testing.T) {
+	b := New()
+	key := b.Key()
+	for _, m := range []string{"5i5h", "5a5b", "5h5i", "5b5a"} {
+		step, err := b.StepFromUSI(m)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", m, err)
+		}
+		if err := b.Apply(step); err != nil {
+			t.Fatalf("%v: unexpected error: %v", m, err)
+		}
+	}
+	if k := b.Key(); k != key {
+		t.Errorf("expected=%v actual=%v", key, k)
+	}
+}
+
+func TestCanDeclareWin(t *testing.T) {
+	b := mustSFEN(t, "LNSGKGSNL/PPPPPPPPP/9/9/9/9/9/9/4k4 b RB 1")
+	if b.CanDeclareWin(Sente) {
+		t.Errorf("27 points must not be enough for Sente")
+	}
+
+	b = mustSFEN(t, "LNSGKGSNL/PPPPPPPPP/9/9/9/9/9/9/4k4 b RBP 1")
+	if !b.CanDeclareWin(Sente) {
+		t.Errorf("expected declaration")
+	}
+	if b.CanDeclareWin(Gote) {
+		t.Errorf("unexpected declaration of Gote")
+	}
+}
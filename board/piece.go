@@ -0,0 +1,155 @@
+package board
+
+import (
+	"github.com/yunomu/kif/ptypes"
+)
+
+type Side int
+
+const (
+	Sente Side = iota
+	Gote
+)
+
+func (s Side) Opponent() Side {
+	if s == Sente {
+		return Gote
+	}
+	return Sente
+}
+
+func (s Side) String() string {
+	if s == Sente {
+		return "先手"
+	}
+	return "後手"
+}
+
+const numPieces = int(ptypes.Piece_TO) + 1
+
+// HandPieces is the order pieces in hand are conventionally listed in.
+var HandPieces = []ptypes.Piece_Id{
+	ptypes.Piece_HISHA,
+	ptypes.Piece_KAKU,
+	ptypes.Piece_KIN,
+	ptypes.Piece_GIN,
+	ptypes.Piece_KEI,
+	ptypes.Piece_KYOU,
+	ptypes.Piece_FU,
+}
+
+func Promote(p ptypes.Piece_Id) ptypes.Piece_Id {
+	switch p {
+	case ptypes.Piece_HISHA:
+		return ptypes.Piece_RYU
+	case ptypes.Piece_KAKU:
+		return ptypes.Piece_UMA
+	case ptypes.Piece_GIN:
+		return ptypes.Piece_NARI_GIN
+	case ptypes.Piece_KEI:
+		return ptypes.Piece_NARI_KEI
+	case ptypes.Piece_KYOU:
+		return ptypes.Piece_NARI_KYOU
+	case ptypes.Piece_FU:
+		return ptypes.Piece_TO
+	default:
+		return p
+	}
+}
+
+func Demote(p ptypes.Piece_Id) ptypes.Piece_Id {
+	switch p {
+	case ptypes.Piece_RYU:
+		return ptypes.Piece_HISHA
+	case ptypes.Piece_UMA:
+		return ptypes.Piece_KAKU
+	case ptypes.Piece_NARI_GIN:
+		return ptypes.Piece_GIN
+	case ptypes.Piece_NARI_KEI:
+		return ptypes.Piece_KEI
+	case ptypes.Piece_NARI_KYOU:
+		return ptypes.Piece_KYOU
+	case ptypes.Piece_TO:
+		return ptypes.Piece_FU
+	default:
+		return p
+	}
+}
+
+func CanPromote(p ptypes.Piece_Id) bool {
+	return Promote(p) != p
+}
+
+func IsPromoted(p ptypes.Piece_Id) bool {
+	return Demote(p) != p
+}
+
+var sfenLetters = []string{
+	"",
+	"K",
+	"R",
+	"+R",
+	"B",
+	"+B",
+	"G",
+	"S",
+	"+S",
+	"N",
+	"+N",
+	"L",
+	"+L",
+	"P",
+	"+P",
+}
+
+// Letter returns the SFEN letter of the piece, lower-cased for Gote.
+func Letter(side Side, p ptypes.Piece_Id) string {
+	l := sfenLetters[int(p)]
+	if side == Gote {
+		bs := []byte(l)
+		for i, c := range bs {
+			if 'A' <= c && c <= 'Z' {
+				bs[i] = c - 'A' + 'a'
+			}
+		}
+		l = string(bs)
+	}
+	return l
+}
+
+func pieceFromLetter(c byte) (Side, ptypes.Piece_Id) {
+	side := Sente
+	if 'a' <= c && c <= 'z' {
+		side = Gote
+		c = c - 'a' + 'A'
+	}
+	for i, l := range sfenLetters {
+		if len(l) == 1 && l[0] == c {
+			return side, ptypes.Piece_Id(i)
+		}
+	}
+	return side, ptypes.Piece_NULL
+}
+
+var shortNames = []string{
+	"",
+	"玉",
+	"飛",
+	"龍",
+	"角",
+	"馬",
+	"金",
+	"銀",
+	"全",
+	"桂",
+	"圭",
+	"香",
+	"杏",
+	"歩",
+	"と",
+}
+
+// ShortName returns the one-character name used in board diagrams.
+func ShortName(p ptypes.Piece_Id) string {
+	return shortNames[int(p)]
+}
@@ -0,0 +1,57 @@
+package board
+
+type RepetitionResult int
+
+const (
+	// Repetition_NONE means the position has not appeared four times.
+	Repetition_NONE RepetitionResult = iota
+	// Repetition_DRAW is 千日手.
+	Repetition_DRAW
+	// Repetition_CHECK_BY_MOVER means the side which just moved gave perpetual check and loses.
+	Repetition_CHECK_BY_MOVER
+	// Repetition_CHECK_BY_OPPONENT means the side to move gave perpetual check and loses.
+	Repetition_CHECK_BY_OPPONENT
+)
+
+// Repetition tracks the positions of a game to detect 千日手.
+type Repetition struct {
+	seen map[string][]int32
+	// checks[i] is whether the (i+1)th move gave check.
+	checks []bool
+}
+
+func NewRepetition(b *Board) *Repetition {
+	return &Repetition{
+		seen:   map[string][]int32{b.Key(): {b.Ply}},
+		checks: make([]bool, b.Ply),
+	}
+}
+
+// Add records the position after a move.
+func (r *Repetition) Add(b *Board) RepetitionResult {
+	r.checks = append(r.checks, b.InCheck(b.Turn))
+
+	key := b.Key()
+	r.seen[key] = append(r.seen[key], b.Ply)
+	plies := r.seen[key]
+	if len(plies) < 4 {
+		return Repetition_NONE
+	}
+
+	perpetual := func(last int32) bool {
+		for i := last; i > plies[0]; i -= 2 {
+			if !r.checks[i-1] {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case perpetual(b.Ply):
+		return Repetition_CHECK_BY_MOVER
+	case perpetual(b.Ply - 1):
+		return Repetition_CHECK_BY_OPPONENT
+	default:
+		return Repetition_DRAW
+	}
+}
@@ -0,0 +1,151 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+const StartPos = "lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1"
+
+func FromSFEN(sfen string) (*Board, error) {
+	fields := strings.Fields(sfen)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid sfen: %q", sfen)
+	}
+
+	b := Empty()
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 9 {
+		return nil, fmt.Errorf("invalid sfen board: %q", fields[0])
+	}
+	for i, rank := range ranks {
+		y := int32(i + 1)
+		x := int32(9)
+		promoted := false
+		for j := 0; j < len(rank); j++ {
+			c := rank[j]
+			switch {
+			case c == '+':
+				promoted = true
+				continue
+			case '1' <= c && c <= '9':
+				x -= int32(c - '0')
+				continue
+			}
+
+			side, p := pieceFromLetter(c)
+			if p == ptypes.Piece_NULL || x < 1 {
+				return nil, fmt.Errorf("invalid sfen board: %q", rank)
+			}
+			if promoted {
+				if !CanPromote(p) {
+					return nil, fmt.Errorf("invalid sfen board: %q", rank)
+				}
+				p = Promote(p)
+				promoted = false
+			}
+			b.Set(x, y, Square{Side: side, Piece: p})
+			x--
+		}
+		if x != 0 {
+			return nil, fmt.Errorf("invalid sfen board: %q", rank)
+		}
+	}
+
+	switch fields[1] {
+	case "b":
+		b.Turn = Sente
+	case "w":
+		b.Turn = Gote
+	default:
+		return nil, fmt.Errorf("invalid sfen turn: %q", fields[1])
+	}
+
+	if fields[2] != "-" {
+		n := 0
+		for i := 0; i < len(fields[2]); i++ {
+			c := fields[2][i]
+			if '0' <= c && c <= '9' {
+				n = n*10 + int(c-'0')
+				continue
+			}
+			side, p := pieceFromLetter(c)
+			if p == ptypes.Piece_NULL || p == ptypes.Piece_GYOKU {
+				return nil, fmt.Errorf("invalid sfen hand: %q", fields[2])
+			}
+			if n == 0 {
+				n = 1
+			}
+			b.hands[side][p] += n
+			n = 0
+		}
+	}
+
+	if len(fields) >= 4 {
+		n, err := strconv.ParseInt(fields[3], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sfen move number: %q", fields[3])
+		}
+		b.startNum = int32(n)
+	}
+
+	return b, nil
+}
+
+func (b *Board) sfenBoard() string {
+	var sb strings.Builder
+	for y := int32(1); y <= 9; y++ {
+		if y != 1 {
+			sb.WriteByte('/')
+		}
+		blank := 0
+		for x := int32(9); x >= 1; x-- {
+			sq := b.At(x, y)
+			if sq.Empty() {
+				blank++
+				continue
+			}
+			if blank != 0 {
+				sb.WriteString(strconv.Itoa(blank))
+				blank = 0
+			}
+			sb.WriteString(Letter(sq.Side, sq.Piece))
+		}
+		if blank != 0 {
+			sb.WriteString(strconv.Itoa(blank))
+		}
+	}
+	return sb.String()
+}
+
+func (b *Board) sfenHands() string {
+	var sb strings.Builder
+	for _, side := range []Side{Sente, Gote} {
+		for _, p := range HandPieces {
+			n := b.hands[side][p]
+			if n == 0 {
+				continue
+			}
+			if n > 1 {
+				sb.WriteString(strconv.Itoa(n))
+			}
+			sb.WriteString(Letter(side, p))
+		}
+	}
+	if sb.Len() == 0 {
+		return "-"
+	}
+	return sb.String()
+}
+
+func (b *Board) SFEN() string {
+	turn := "b"
+	if b.Turn == Gote {
+		turn = "w"
+	}
+	return fmt.Sprintf("%s %s %s %d", b.sfenBoard(), turn, b.sfenHands(), b.startNum+b.Ply)
+}
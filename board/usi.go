@@ -0,0 +1,55 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func usiPos(s string) (*ptypes.Pos, bool) {
+	if len(s) != 2 || s[0] < '1' || '9' < s[0] || s[1] < 'a' || 'i' < s[1] {
+		return nil, false
+	}
+	return &ptypes.Pos{X: int32(s[0] - '0'), Y: int32(s[1]-'a') + 1}, true
+}
+
+// StepFromUSI converts a move in USI notation ("7g7f", "8h2b+", "P*5e")
+// to a step in this position. The board is not modified.
+func (b *Board) StepFromUSI(m string) (*ptypes.Step, error) {
+	step := &ptypes.Step{
+		Seq: b.startNum + b.Ply,
+	}
+
+	if len(m) == 4 && m[1] == '*' {
+		_, p := pieceFromLetter(m[0])
+		dst, ok := usiPos(m[2:])
+		if p == ptypes.Piece_NULL || !ok {
+			return nil, fmt.Errorf("invalid usi move: %q", m)
+		}
+		step.Dst = dst
+		step.Piece = p
+		step.Modifier = ptypes.Modifier_PUTTED
+		return step, nil
+	}
+
+	if len(m) != 4 && !(len(m) == 5 && m[4] == '+') {
+		return nil, fmt.Errorf("invalid usi move: %q", m)
+	}
+	src, ok1 := usiPos(m[0:2])
+	dst, ok2 := usiPos(m[2:4])
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("invalid usi move: %q", m)
+	}
+	sq := b.At(src.X, src.Y)
+	if sq.Empty() {
+		return nil, fmt.Errorf("no piece at the source of %q", m)
+	}
+	step.Src = src
+	step.Dst = dst
+	step.Piece = sq.Piece
+	if len(m) == 5 {
+		step.Modifier = ptypes.Modifier_PROMOTE
+	}
+
+	return step, nil
+}
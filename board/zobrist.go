@@ -0,0 +1,62 @@
+package board
+
+// maxHand is the most pieces of a kind that can be in hand (歩 18), plus one.
+const maxHand = 19
+
+// The Zobrist keys are generated from a fixed seed so that hashes can be
+// stored and compared across processes.
+var (
+	zobristSquares [2][numPieces][81]uint64
+	zobristHands   [2][numPieces][maxHand]uint64
+	zobristTurn    uint64
+)
+
+func init() {
+	seed := uint64(0x6b69665a6f627269)
+	next := func() uint64 {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for s := range zobristSquares {
+		for p := range zobristSquares[s] {
+			for i := range zobristSquares[s][p] {
+				zobristSquares[s][p][i] = next()
+			}
+		}
+	}
+	for s := range zobristHands {
+		for p := range zobristHands[s] {
+			for n := range zobristHands[s][p] {
+				zobristHands[s][p][n] = next()
+			}
+		}
+	}
+	zobristTurn = next()
+}
+
+// Hash returns the Zobrist hash of the position: the board, hands and side to
+// move. Positions with the same Key have the same Hash.
+func (b *Board) Hash() uint64 {
+	var h uint64
+	for i, sq := range b.squares {
+		if !sq.Empty() {
+			h ^= zobristSquares[sq.Side][sq.Piece][i]
+		}
+	}
+	for s := range b.hands {
+		for p, n := range b.hands[s] {
+			if n > 0 && n < maxHand {
+				h ^= zobristHands[s][p][n]
+			}
+		}
+	}
+	if b.Turn == Gote {
+		h ^= zobristTurn
+	}
+	return h
+}
@@ -0,0 +1,57 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestHash(t *testing.T) {
+	b := New()
+	start := b.Hash()
+
+	apply := func(moves ...string) {
+		t.Helper()
+		for _, m := range moves {
+			step, err := b.StepFromUSI(m)
+			if err != nil {
+				t.Fatalf("%v: unexpected error: %v", m, err)
+			}
+			if err := b.Apply(step); err != nil {
+				t.Fatalf("%v: unexpected error: %v", m, err)
+			}
+		}
+	}
+
+	apply("5i5h", "5a5b", "5h5i", "5b5a")
+	if h := b.Hash(); h != start {
+		t.Errorf("repeated position: expected=%x actual=%x", start, h)
+	}
+
+	apply("5i5h")
+	moved := b.Hash()
+	b.Turn = Sente
+	if h := b.Hash(); h == moved {
+		t.Errorf("side to move must change the hash")
+	}
+	b.Turn = Gote
+
+	// the same position by another move order
+	other, err := FromSFEN(b.SFEN())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := other.Hash(); h != moved {
+		t.Errorf("from SFEN: expected=%x actual=%x", moved, h)
+	}
+
+	apply("3c3d", "7g7f", "2b8h+", "7i8h")
+	if n := b.Hand(Sente, ptypes.Piece_KAKU); n != 1 {
+		t.Fatalf("sente KAKU in hand: expected=1 actual=%v", n)
+	}
+	withHand := b.Hash()
+	b.SetHand(Sente, ptypes.Piece_KAKU, 2)
+	if h := b.Hash(); h == withHand {
+		t.Errorf("hands must change the hash")
+	}
+}
@@ -0,0 +1,251 @@
+package book
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// Stats counts the games and their results.
+type Stats struct {
+	Games     int `json:"games"`
+	SenteWins int `json:"senteWins"`
+	GoteWins  int `json:"goteWins"`
+	Draws     int `json:"draws"`
+}
+
+func (s *Stats) add(o kif.Outcome) {
+	s.Games++
+	switch o {
+	case kif.Outcome_SENTE_WIN:
+		s.SenteWins++
+	case kif.Outcome_GOTE_WIN:
+		s.GoteWins++
+	case kif.Outcome_DRAW:
+		s.Draws++
+	}
+}
+
+// WinRate returns the score of the side in the finished games, counting a
+// draw as half a win, or 0.5 if no game is finished.
+func (s *Stats) WinRate(side board.Side) float64 {
+	n := s.SenteWins + s.GoteWins + s.Draws
+	if n == 0 {
+		return 0.5
+	}
+	wins := s.SenteWins
+	if side == board.Gote {
+		wins = s.GoteWins
+	}
+	return (float64(wins) + float64(s.Draws)/2) / float64(n)
+}
+
+type Move struct {
+	// Move is the move in USI notation.
+	Move string `json:"move"`
+	// Next is the SFEN of the position after the move.
+	Next string `json:"next"`
+	Stats
+}
+
+type Position struct {
+	// SFEN is the position with the number of the next move where it was
+	// reached earliest. Positions reached by different move orders are merged.
+	SFEN string `json:"sfen"`
+	Ply  int32  `json:"ply"`
+	Stats
+	// Moves are the moves played from the position, the most frequent first.
+	Moves []*Move `json:"moves"`
+}
+
+type Book struct {
+	// Positions are the positions with moves, in the order of their SFEN.
+	Positions []*Position `json:"positions"`
+}
+
+type moveNode struct {
+	usi   string
+	next  string
+	stats Stats
+}
+
+type positionNode struct {
+	sfen  string
+	ply   int32
+	stats Stats
+	moves map[string]*moveNode
+}
+
+// Builder builds an opening book from games.
+type Builder struct {
+	maxPly   int32
+	minCount int
+
+	positions map[string]*positionNode
+}
+
+type BuilderOption func(*Builder)
+
+// BuilderMaxPly sets the number of moves of each game to use. The default is 40.
+func BuilderMaxPly(n int) BuilderOption {
+	return func(b *Builder) {
+		b.maxPly = int32(n)
+	}
+}
+
+// BuilderMinCount sets the number of games a position or a move needs to be
+// in the book. The default is 1.
+func BuilderMinCount(n int) BuilderOption {
+	return func(b *Builder) {
+		b.minCount = n
+	}
+}
+
+func NewBuilder(ops ...BuilderOption) *Builder {
+	b := &Builder{
+		maxPly:    40,
+		minCount:  1,
+		positions: make(map[string]*positionNode),
+	}
+	for _, f := range ops {
+		f(b)
+	}
+	return b
+}
+
+type visit struct {
+	key, sfen string
+	ply       int32
+	usi       string
+	next      string
+}
+
+// Add adds the main line of the game. A position or a move repeated in a game
+// is counted once. Nothing is added if the game has an illegal move.
+func (bd *Builder) Add(k *ptypes.Kif) error {
+	o, err := kif.GetOutcome(k)
+	if err != nil {
+		return err
+	}
+	b, err := board.FromKif(k)
+	if err != nil {
+		return err
+	}
+
+	var visits []*visit
+	for _, step := range k.GetSteps() {
+		if b.Ply >= bd.maxPly || step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		v := &visit{key: b.Key(), sfen: b.SFEN(), ply: b.Ply}
+		if err := b.Apply(step); err != nil {
+			return errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		v.usi = kif.StepToMove(b.Last)
+		v.next = b.Key()
+		visits = append(visits, v)
+	}
+	visits = append(visits, &visit{key: b.Key(), sfen: b.SFEN(), ply: b.Ply})
+
+	seen := make(map[string]bool)
+	for _, v := range visits {
+		p, ok := bd.positions[v.key]
+		if !ok {
+			p = &positionNode{sfen: v.sfen, ply: v.ply, moves: make(map[string]*moveNode)}
+			bd.positions[v.key] = p
+		} else if v.ply < p.ply {
+			p.sfen, p.ply = v.sfen, v.ply
+		}
+		if !seen[v.key] {
+			p.stats.add(o)
+		}
+		if v.usi != "" && !seen[v.key+" "+v.usi] {
+			m, ok := p.moves[v.usi]
+			if !ok {
+				m = &moveNode{usi: v.usi, next: v.next}
+				p.moves[v.usi] = m
+			}
+			m.stats.add(o)
+			seen[v.key+" "+v.usi] = true
+		}
+		seen[v.key] = true
+	}
+	return nil
+}
+
+// Book returns the book of the games added, without the positions and moves
+// of fewer games than the minimum count.
+func (bd *Builder) Book() *Book {
+	ret := &Book{Positions: []*Position{}}
+	for _, p := range bd.positions {
+		if p.stats.Games < bd.minCount {
+			continue
+		}
+		pos := &Position{SFEN: p.sfen, Ply: p.ply, Stats: p.stats}
+		for _, m := range p.moves {
+			if m.stats.Games < bd.minCount {
+				continue
+			}
+			pos.Moves = append(pos.Moves, &Move{
+				Move:  m.usi,
+				Next:  bd.positions[m.next].sfen,
+				Stats: m.stats,
+			})
+		}
+		if len(pos.Moves) == 0 {
+			continue
+		}
+		sort.Slice(pos.Moves, func(i, j int) bool {
+			a, b := pos.Moves[i], pos.Moves[j]
+			if a.Games != b.Games {
+				return a.Games > b.Games
+			}
+			return a.Move < b.Move
+		})
+		ret.Positions = append(ret.Positions, pos)
+	}
+	sort.Slice(ret.Positions, func(i, j int) bool {
+		return ret.Positions[i].SFEN < ret.Positions[j].SFEN
+	})
+	return ret
+}
+
+// WriteJSON writes the book in JSON.
+func (bk *Book) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bk)
+}
+
+// WriteYaneuraOu writes the book in the standard book format of YaneuraOu
+// (YANEURAOU-DB2016). The moves have the most frequent reply as the ponder
+// move, no evaluation or depth, and the number of games as the frequency.
+func (bk *Book) WriteYaneuraOu(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#YANEURAOU-DB2016 1.00")
+
+	bySFEN := make(map[string]*Position, len(bk.Positions))
+	for _, p := range bk.Positions {
+		bySFEN[p.SFEN] = p
+	}
+
+	for _, p := range bk.Positions {
+		fmt.Fprintf(bw, "sfen %s\n", p.SFEN)
+		for _, m := range p.Moves {
+			ponder := "none"
+			if next, ok := bySFEN[m.Next]; ok && len(next.Moves) != 0 {
+				ponder = next.Moves[0].Move
+			}
+			fmt.Fprintf(bw, "%s %s 0 0 %d\n", m.Move, ponder, m.Games)
+		}
+	}
+	return bw.Flush()
+}
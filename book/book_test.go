@@ -0,0 +1,158 @@
+package book
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func findPosition(bk *Book, sfen string) *Position {
+	for _, p := range bk.Positions {
+		if p.SFEN == sfen {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestBuilder(t *testing.T) {
+	var games []*ptypes.Kif
+	for _, g := range []struct {
+		sfen   string
+		status ptypes.FinishedStatus_Id
+	}{
+		// gote resigns
+		{"startpos moves 7g7f 3c3d 2g2f 4c4d", ptypes.FinishedStatus_SURRENDER},
+		// sente resigns
+		{"startpos moves 7g7f 8c8d 2g2f", ptypes.FinishedStatus_SURRENDER},
+		{"startpos moves 2g2f 3c3d 7g7f", ptypes.FinishedStatus_REPETITION_DRAW},
+		{"startpos moves 5i5h 5a5b 5h5i 5b5a 7g7f", ptypes.FinishedStatus_NOT_FINISHED},
+	} {
+		k, err := kif.ParseSFEN(strings.NewReader(g.sfen))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", g.sfen, err)
+		}
+		if g.status != ptypes.FinishedStatus_NOT_FINISHED {
+			k.Steps = append(k.Steps, &ptypes.Step{Seq: int32(len(k.Steps)) + 1, FinishedStatus: g.status})
+		}
+		games = append(games, k)
+	}
+
+	bd := NewBuilder(BuilderMaxPly(3))
+	for _, k := range games {
+		if err := bd.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	bk := bd.Book()
+
+	start := findPosition(bk, board.StartPos)
+	if start == nil {
+		t.Fatalf("no start position")
+	}
+	expected := Stats{Games: 4, SenteWins: 1, GoteWins: 1, Draws: 1}
+	if start.Stats != expected {
+		t.Errorf("start: expected=%+v actual=%+v", expected, start.Stats)
+	}
+	var moves []string
+	for _, m := range start.Moves {
+		moves = append(moves, m.Move)
+	}
+	if s := strings.Join(moves, " "); s != "7g7f 2g2f 5i5h" {
+		t.Errorf("start moves: actual=%v", s)
+	}
+	if m := start.Moves[0]; m.Games != 2 || m.SenteWins != 1 || m.GoteWins != 1 {
+		t.Errorf("7g7f: actual=%+v", m.Stats)
+	}
+	if r := start.WinRate(board.Sente); r != 0.5 {
+		t.Errorf("win rate: expected=0.5 actual=%v", r)
+	}
+
+	// the transposition of the first and third games
+	after3 := "lnsgkgsnl/1r5b1/pppppp1pp/6p2/9/2P4P1/PP1PPPP1P/1B5R1/LNSGKGSNL w - 4"
+	for _, p := range bk.Positions {
+		if p.Ply >= 3 {
+			t.Errorf("position beyond the max ply: %v", p.SFEN)
+		}
+		for _, m := range p.Moves {
+			if m.Next == after3 && m.Move != "2g2f" && m.Move != "7g7f" {
+				t.Errorf("unexpected move to the transposition: %v", m.Move)
+			}
+		}
+	}
+
+	pruned := NewBuilder(BuilderMaxPly(3), BuilderMinCount(2))
+	for _, k := range games {
+		if err := pruned.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	bk = pruned.Book()
+	if len(bk.Positions) != 1 {
+		t.Errorf("pruned positions: expected=1 actual=%v", len(bk.Positions))
+	}
+	if start := findPosition(bk, board.StartPos); start == nil || len(start.Moves) != 1 || start.Moves[0].Move != "7g7f" {
+		t.Errorf("pruned start: actual=%+v", start)
+	}
+}
+
+func TestBuilder_AddIllegal(t *testing.T) {
+	bd := NewBuilder()
+	k, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k.Steps = append(k.Steps, &ptypes.Step{Seq: 2, Src: &ptypes.Pos{X: 5, Y: 5}, Dst: &ptypes.Pos{X: 5, Y: 6}, Piece: ptypes.Piece_FU})
+	if err := bd.Add(k); err == nil {
+		t.Fatalf("expected error")
+	}
+	if bk := bd.Book(); len(bk.Positions) != 0 {
+		t.Errorf("nothing must be added: actual=%v", len(bk.Positions))
+	}
+}
+
+func TestBook_Write(t *testing.T) {
+	bd := NewBuilder()
+	for _, sfen := range []string{"startpos moves 7g7f 3c3d", "startpos moves 7g7f 3c3d", "startpos moves 7g7f 8c8d"} {
+		k, err := kif.ParseSFEN(strings.NewReader(sfen))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := bd.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	bk := bd.Book()
+
+	var buf bytes.Buffer
+	if err := bk.WriteYaneuraOu(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `#YANEURAOU-DB2016 1.00
+sfen lnsgkgsnl/1r5b1/ppppppppp/9/9/2P6/PP1PPPPPP/1B5R1/LNSGKGSNL w - 2
+3c3d none 0 0 2
+8c8d none 0 0 1
+sfen lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1
+7g7f 3c3d 0 0 3
+`
+	if s := buf.String(); s != expected {
+		t.Errorf("expected=%v\nactual=%v", expected, s)
+	}
+
+	buf.Reset()
+	if err := bk.WriteJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Book
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded.Positions) != 2 || decoded.Positions[1].Moves[0].Games != 3 {
+		t.Errorf("actual=%v", buf.String())
+	}
+}
@@ -0,0 +1,154 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type Clock struct {
+	tc      *TimeControl
+	main    [2]time.Duration
+	periods [2]int
+	flagged [2]bool
+}
+
+func New(tc *TimeControl) *Clock {
+	c := &Clock{
+		tc: tc,
+	}
+	for i := range c.main {
+		c.main[i] = tc.Main
+		c.periods[i] = tc.periods()
+	}
+	return c
+}
+
+// Remaining returns the main time left to the side.
+func (c *Clock) Remaining(side board.Side) time.Duration {
+	return c.main[side]
+}
+
+// Periods returns the byoyomi periods left to the side.
+func (c *Clock) Periods(side board.Side) int {
+	return c.periods[side]
+}
+
+func (c *Clock) Flagged(side board.Side) bool {
+	return c.flagged[side]
+}
+
+// Available returns the longest time the side can think on the current move without flagging.
+func (c *Clock) Available(side board.Side) time.Duration {
+	if c.flagged[side] {
+		return 0
+	}
+	return c.main[side] + time.Duration(c.periods[side])*c.tc.Byoyomi
+}
+
+// Consume charges the thinking time of a move to the side.
+// It returns false if the side ran out of time.
+func (c *Clock) Consume(side board.Side, d time.Duration) bool {
+	if c.flagged[side] {
+		return false
+	}
+
+	if d <= c.main[side] {
+		c.main[side] -= d
+		c.main[side] += c.tc.Increment
+		return true
+	}
+
+	over := d - c.main[side]
+	c.main[side] = 0
+	for c.periods[side] > 0 {
+		if over <= c.tc.Byoyomi {
+			c.main[side] += c.tc.Increment
+			return true
+		}
+		over -= c.tc.Byoyomi
+		c.periods[side]--
+	}
+
+	c.flagged[side] = true
+	return false
+}
+
+type PlyClock struct {
+	Seq      int32
+	Side     board.Side
+	Thinking time.Duration
+	// Remaining is the main time left after the move.
+	Remaining time.Duration
+	// Periods is the byoyomi periods left after the move.
+	Periods int
+	Flagged bool
+}
+
+// Replay runs the clock over the main line of the game.
+// The replay stops at the move where a player flagged.
+func Replay(k *ptypes.Kif, tc *TimeControl) ([]*PlyClock, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New(tc)
+	side := b.Turn
+
+	var ret []*PlyClock
+	for _, step := range k.Steps {
+		d := kif.ThinkingDuration(step)
+		ok := c.Consume(side, d)
+		ret = append(ret, &PlyClock{
+			Seq:       step.Seq,
+			Side:      side,
+			Thinking:  d,
+			Remaining: c.Remaining(side),
+			Periods:   c.Periods(side),
+			Flagged:   !ok,
+		})
+		if !ok || step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		side = side.Opponent()
+	}
+
+	return ret, nil
+}
+
+// FlaggedAt returns the clock of the move where a player flagged, or nil.
+func FlaggedAt(cs []*PlyClock) *PlyClock {
+	for _, c := range cs {
+		if c.Flagged {
+			return c
+		}
+	}
+	return nil
+}
+
+// ApplyTimeLoss replaces the move where a player flagged, and the rest of
+// the main line, with a 切れ負け step. It reports whether anyone flagged.
+func ApplyTimeLoss(k *ptypes.Kif, tc *TimeControl) (bool, error) {
+	cs, err := Replay(k, tc)
+	if err != nil {
+		return false, err
+	}
+	f := FlaggedAt(cs)
+	if f == nil {
+		return false, nil
+	}
+
+	i := len(cs) - 1
+	step := &ptypes.Step{
+		Seq:            f.Seq,
+		FinishedStatus: ptypes.FinishedStatus_OVER_TIME_LIMIT,
+	}
+	kif.SetThinkingDuration(step, f.Thinking)
+	k.Steps = append(k.Steps[:i], step)
+	kif.RecomputeElapsed(k)
+
+	return true, nil
+}
@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	for _, c := range []struct {
+		in       string
+		expected TimeControl
+	}{
+		{"各10分", TimeControl{Main: 10 * time.Minute}},
+		{"各１時間３０分（ストップウォッチ方式）", TimeControl{Main: 90 * time.Minute}},
+		{"1時間+秒読み30秒", TimeControl{Main: time.Hour, Byoyomi: 30 * time.Second}},
+		{"15分+60秒", TimeControl{Main: 15 * time.Minute, Byoyomi: time.Minute}},
+		{"5分切れ負け", TimeControl{Main: 5 * time.Minute}},
+		{"10分+1手10秒加算", TimeControl{Main: 10 * time.Minute, Increment: 10 * time.Second}},
+		{"0分+秒読み30秒×3回", TimeControl{Byoyomi: 30 * time.Second, Periods: 3}},
+	} {
+		tc, err := ParseTimeControl(c.in)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.in, err)
+			continue
+		}
+		if *tc != c.expected {
+			t.Errorf("%v: expected=%+v actual=%+v", c.in, c.expected, *tc)
+		}
+	}
+
+	if _, err := ParseTimeControl("なし"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestClock_Consume(t *testing.T) {
+	c := New(&TimeControl{Main: time.Minute, Byoyomi: 10 * time.Second, Periods: 2})
+
+	if !c.Consume(board.Sente, 55*time.Second) {
+		t.Fatalf("flagged in main time")
+	}
+	if !c.Consume(board.Sente, 15*time.Second) {
+		t.Fatalf("flagged in byoyomi")
+	}
+	if r, p := c.Remaining(board.Sente), c.Periods(board.Sente); r != 0 || p != 2 {
+		t.Fatalf("unexpected remaining: %v %v", r, p)
+	}
+	if !c.Consume(board.Sente, 15*time.Second) {
+		t.Fatalf("flagged with a period left")
+	}
+	if p := c.Periods(board.Sente); p != 1 {
+		t.Fatalf("periods: expected=1 actual=%v", p)
+	}
+	if c.Consume(board.Sente, 11*time.Second) {
+		t.Fatalf("not flagged")
+	}
+	if c.Flagged(board.Gote) {
+		t.Fatalf("gote flagged")
+	}
+
+	f := New(&TimeControl{Main: time.Minute, Increment: 5 * time.Second})
+	f.Consume(board.Gote, 30*time.Second)
+	if r := f.Remaining(board.Gote); r != 35*time.Second {
+		t.Errorf("fischer: expected=35s actual=%v", r)
+	}
+}
+
+func TestApplyTimeLoss(t *testing.T) {
+	k := &ptypes.Kif{
+		Headers: []*ptypes.Header{
+			{Name: "持ち時間", Value: "1分切れ負け"},
+		},
+		Steps: []*ptypes.Step{
+			{Seq: 1, ThinkingSec: 30},
+			{Seq: 2, ThinkingSec: 10},
+			{Seq: 3, ThinkingSec: 31},
+			{Seq: 4, ThinkingSec: 1},
+			{Seq: 5, FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+		},
+	}
+	tc, err := FromKif(k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cs, err := Replay(k, tc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := FlaggedAt(cs); f == nil || f.Seq != 3 || f.Side != board.Sente {
+		t.Fatalf("unexpected flag: %+v", f)
+	}
+
+	ok, err := ApplyTimeLoss(k, tc)
+	if err != nil || !ok {
+		t.Fatalf("unexpected result: %v %v", ok, err)
+	}
+	if l := len(k.Steps); l != 3 {
+		t.Fatalf("steps: expected=3 actual=%v", l)
+	}
+	if s := k.Steps[2]; s.FinishedStatus != ptypes.FinishedStatus_OVER_TIME_LIMIT || s.ElapsedSec != 61 {
+		t.Errorf("unexpected last step: %v", s)
+	}
+}
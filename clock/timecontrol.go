@@ -0,0 +1,174 @@
+package clock
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+const headerName = "持ち時間"
+
+type TimeControl struct {
+	// Main is the main time (持ち時間) of each side.
+	Main time.Duration
+	// Byoyomi is the time per move after the main time is used up.
+	Byoyomi time.Duration
+	// Periods is the number of byoyomi periods. Zero means one.
+	Periods int
+	// Increment is the Fischer increment added after each move.
+	Increment time.Duration
+}
+
+// SuddenDeath reports whether the game is lost as soon as the main time is used up (切れ負け).
+func (tc *TimeControl) SuddenDeath() bool {
+	return tc.Byoyomi == 0 && tc.Increment == 0
+}
+
+func (tc *TimeControl) periods() int {
+	if tc.Byoyomi == 0 {
+		return 0
+	}
+	if tc.Periods == 0 {
+		return 1
+	}
+	return tc.Periods
+}
+
+func (tc *TimeControl) String() string {
+	var ss []string
+	ss = append(ss, formatDuration(tc.Main))
+	if tc.Byoyomi != 0 {
+		s := "秒読み" + formatDuration(tc.Byoyomi)
+		if tc.Periods > 1 {
+			s += fmt.Sprintf("×%d回", tc.Periods)
+		}
+		ss = append(ss, s)
+	}
+	if tc.Increment != 0 {
+		ss = append(ss, "1手"+formatDuration(tc.Increment)+"加算")
+	}
+	if tc.SuddenDeath() {
+		ss = append(ss, "切れ負け")
+	}
+	return strings.Join(ss, "+")
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0分"
+	}
+	var s string
+	if h := d / time.Hour; h != 0 {
+		s += fmt.Sprintf("%d時間", h)
+	}
+	if m := d % time.Hour / time.Minute; m != 0 {
+		s += fmt.Sprintf("%d分", m)
+	}
+	if sec := d % time.Minute / time.Second; sec != 0 {
+		s += fmt.Sprintf("%d秒", sec)
+	}
+	return s
+}
+
+var (
+	fullWidthDigits = strings.NewReplacer(
+		"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+		"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+		"＋", "+", "（", "(", "）", ")", "／", "/", "　", " ",
+	)
+	separators = regexp.MustCompile(`[+、,()・ ]+`)
+	durationRe = regexp.MustCompile(`(\d+)\s*(時間|分|秒|h|m|s)`)
+	periodsRe  = regexp.MustCompile(`[×x*](\d+)回?|(\d+)回`)
+)
+
+func parseDuration(s string) (time.Duration, bool) {
+	ms := durationRe.FindAllStringSubmatch(s, -1)
+	if len(ms) == 0 {
+		return 0, false
+	}
+	var d time.Duration
+	for _, m := range ms {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, false
+		}
+		switch m[2] {
+		case "時間", "h":
+			d += time.Duration(n) * time.Hour
+		case "分", "m":
+			d += time.Duration(n) * time.Minute
+		case "秒", "s":
+			d += time.Duration(n) * time.Second
+		}
+	}
+	return d, true
+}
+
+// ParseTimeControl parses 持ち時間 header values like
+// "各10分", "1時間+秒読み30秒", "5分切れ負け", "10分+1手10秒加算" or "10分+30秒×3回".
+func ParseTimeControl(s string) (*TimeControl, error) {
+	tc := &TimeControl{}
+	var hasMain, hasByoyomi bool
+
+	norm := fullWidthDigits.Replace(s)
+	for _, seg := range separators.Split(norm, -1) {
+		if seg == "" {
+			continue
+		}
+		seg = strings.TrimPrefix(seg, "各")
+
+		if m := periodsRe.FindStringSubmatch(seg); m != nil {
+			n := m[1]
+			if n == "" {
+				n = m[2]
+			}
+			tc.Periods, _ = strconv.Atoi(n)
+			seg = strings.Replace(seg, m[0], "", 1)
+		}
+
+		d, ok := parseDuration(seg)
+		switch {
+		case strings.Contains(seg, "秒読み"):
+			if !ok {
+				return nil, fmt.Errorf("invalid byoyomi: %q", s)
+			}
+			tc.Byoyomi = d
+			hasByoyomi = true
+		case strings.Contains(seg, "加算") || strings.Contains(seg, "フィッシャー") || strings.Contains(seg, "/手"):
+			if !ok {
+				return nil, fmt.Errorf("invalid increment: %q", s)
+			}
+			tc.Increment = d
+		case !ok:
+			// 切れ負け, ストップウォッチ and other notes
+		case !hasMain:
+			tc.Main = d
+			hasMain = true
+		case !hasByoyomi:
+			tc.Byoyomi = d
+			hasByoyomi = true
+		default:
+			return nil, fmt.Errorf("unexpected duration in time control: %q", s)
+		}
+	}
+
+	if !hasMain && !hasByoyomi && tc.Increment == 0 {
+		return nil, fmt.Errorf("no time control: %q", s)
+	}
+
+	return tc, nil
+}
+
+// FromKif returns the time control of the 持ち時間 header, or nil if the header is absent.
+func FromKif(k *ptypes.Kif) (*TimeControl, error) {
+	for _, h := range k.GetHeaders() {
+		if h.Name == headerName {
+			return ParseTimeControl(h.Value)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/service"
+)
+
+var (
+	grpcAddr = flag.String("grpc", ":8081", "Address of the gRPC server (empty to disable)")
+	httpAddr = flag.String("http", ":8080", "Address of the JSON/HTTP server (empty to disable)")
+	maxBytes = flag.Int64("maxbytes", 1<<20, "Maximum size of HTTP request bodies")
+)
+
+func init() {
+	log.SetOutput(os.Stderr)
+}
+
+func main() {
+	flag.Parse()
+
+	s := service.NewServer()
+	errc := make(chan error, 2)
+
+	if *grpcAddr != "" {
+		l, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		gs := grpc.NewServer()
+		ptypes.RegisterKifServiceServer(gs, s)
+		log.Printf("gRPC listening on %s", l.Addr())
+		go func() { errc <- gs.Serve(l) }()
+	}
+
+	if *httpAddr != "" {
+		h := service.NewHandler(s, service.HandlerMaxBytes(*maxBytes))
+		log.Printf("HTTP listening on %s", *httpAddr)
+		go func() { errc <- http.ListenAndServe(*httpAddr, h) }()
+	}
+
+	if *grpcAddr == "" && *httpAddr == "" {
+		log.Fatalln("no address to listen on")
+	}
+	log.Fatalln(<-errc)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/yunomu/kif/analysis"
+	"github.com/yunomu/kif/usi"
+)
+
+func annotate(args []string) {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file")
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, true)
+	engine := fs.String("engine", "", "Path to the USI engine")
+	depth := fs.Int("depth", 0, "Search depth per move")
+	movetime := fs.Duration("movetime", 0, "Search time per move (default 1s if -depth is not set)")
+	verbose := fs.Bool("v", false, "Print progress")
+	fs.Parse(args)
+
+	if *engine == "" {
+		log.Fatalln("-engine is required")
+	}
+
+	in, closeIn := openInput()
+	defer closeIn()
+
+	read, write := formats.reader(), formats.writer()
+	k, err := read(in)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ctx := context.Background()
+	e, err := usi.Start(ctx, *engine)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer e.Quit()
+	if err := e.NewGame(); err != nil {
+		log.Fatalln(err)
+	}
+
+	ops := []analysis.AnnotateOption{
+		analysis.AnnotateDepth(*depth),
+		analysis.AnnotateMoveTime(*movetime),
+	}
+	if *verbose {
+		ops = append(ops, analysis.AnnotateProgress(func(seq int32) {
+			log.Printf("seq=%v", seq)
+		}))
+	}
+	if err := analysis.Annotate(ctx, k, e, ops...); err != nil {
+		log.Fatalln(err)
+	}
+
+	out, closeOut := openOutput()
+	defer closeOut()
+	if err := write(out, k); err != nil {
+		log.Fatalln(err)
+	}
+}
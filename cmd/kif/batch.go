@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+type batchJob struct {
+	src, dst string
+}
+
+func replaceExt(path, ext string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ext
+}
+
+// extMatcher returns the function selecting the files in directories: by the
+// extension if it is given, and by the extensions of the format otherwise.
+func extMatcher(format, ext string) func(string) bool {
+	return func(path string) bool {
+		if ext != "" {
+			return strings.EqualFold(filepath.Ext(path), ext)
+		}
+		return matchExt(format, path)
+	}
+}
+
+type inputFile struct {
+	path string
+	// rel is the path relative to the directory given, or the base name.
+	rel string
+}
+
+// expandInputs expands the files, globs and directories of args to the files
+// to read. Files in directories are selected by match.
+func expandInputs(args []string, match func(string) bool) ([]*inputFile, []error) {
+	var files []*inputFile
+	var errs []error
+
+	for _, arg := range args {
+		paths := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			paths, err = filepath.Glob(arg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", arg, err))
+				continue
+			}
+			if len(paths) == 0 {
+				errs = append(errs, fmt.Errorf("%s: no matching files", arg))
+				continue
+			}
+		}
+
+		for _, path := range paths {
+			fi, err := os.Stat(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !fi.IsDir() {
+				files = append(files, &inputFile{path: path, rel: filepath.Base(path)})
+				continue
+			}
+
+			root := path
+			err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+				if err != nil {
+					errs = append(errs, err)
+					return nil
+				}
+				if !fi.Mode().IsRegular() || !match(path) {
+					return nil
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				files = append(files, &inputFile{path: path, rel: rel})
+				return nil
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return files, errs
+}
+
+// batchJobs returns the conversions of the inputs of args. The outputs keep
+// the paths relative to the directories given under outDir. Inputs whose
+// output is the same as that of an earlier input are errors.
+func batchJobs(args []string, match func(string) bool, outDir, outExt string) ([]*batchJob, []error) {
+	files, errs := expandInputs(args, match)
+
+	var jobs []*batchJob
+	dsts := make(map[string]string)
+	for _, f := range files {
+		dst := filepath.Join(outDir, replaceExt(f.rel, outExt))
+		if filepath.Clean(f.path) == dst {
+			errs = append(errs, fmt.Errorf("%s: output would overwrite the input", f.path))
+			continue
+		}
+		if src, ok := dsts[dst]; ok {
+			errs = append(errs, fmt.Errorf("%s: output %s is also the output of %s", f.path, dst, src))
+			continue
+		}
+		dsts[dst] = f.path
+		jobs = append(jobs, &batchJob{src: f.path, dst: dst})
+	}
+
+	return jobs, errs
+}
+
+func convertFile(job *batchJob, read readFunc, write writeFunc) error {
+	in, err := os.Open(job.src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	k, err := read(in)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(job.dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(job.dst)
+	if err != nil {
+		return err
+	}
+	if err := write(out, k); err != nil {
+		out.Close()
+		os.Remove(job.dst)
+		return err
+	}
+	return out.Close()
+}
+
+type batchResult struct {
+	converted, skipped int
+	errs               []error
+}
+
+// runBatch converts the files with the number of workers. Existing outputs are
+// skipped if skipExisting is set. onDone is called for each converted file.
+func runBatch(jobs []*batchJob, read readFunc, write writeFunc, workers int, skipExisting bool, onDone func(*batchJob)) *batchResult {
+	res := &batchResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	ch := make(chan *batchJob)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				if skipExisting {
+					if _, err := os.Stat(job.dst); err == nil {
+						mu.Lock()
+						res.skipped++
+						mu.Unlock()
+						continue
+					}
+				}
+
+				err := convertFile(job, read, write)
+
+				mu.Lock()
+				if err != nil {
+					res.errs = append(res.errs, fmt.Errorf("%s: %v", job.src, err))
+				} else {
+					res.converted++
+					onDone(job)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		ch <- job
+	}
+	close(ch)
+	wg.Wait()
+
+	return res
+}
+
+// readFiles reads the files with the number of workers, and calls f with each
+// game in the order they are read.
+func readFiles(files []*inputFile, read readFunc, workers int, f func(path string, k *ptypes.Kif, err error)) {
+	type result struct {
+		path string
+		k    *ptypes.Kif
+		err  error
+	}
+
+	var wg sync.WaitGroup
+	paths := make(chan string)
+	results := make(chan *result)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				r := &result{path: path}
+				if in, err := os.Open(path); err != nil {
+					r.err = err
+				} else {
+					r.k, r.err = read(in)
+					in.Close()
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		for _, file := range files {
+			paths <- file.path
+		}
+		close(paths)
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		f(r.path, r.k, r.err)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+
+	"github.com/yunomu/kif/book"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func buildBook(args []string) {
+	fs := flag.NewFlagSet("book", flag.ExitOnError)
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, false)
+	ext := fs.String("ext", "", "Extension of the files to read in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel readers")
+	depth := fs.Int("depth", 40, "Number of moves of each game to use")
+	minCount := fs.Int("min", 1, "Number of games a position or a move needs to be in the book")
+	output := fs.String("format", "db", "Output format: db (YaneuraOu standard book), json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif book [flags] files, globs or directories...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		log.Fatalln("no input files")
+	}
+	if *workers < 1 {
+		log.Fatalln("-j must be positive")
+	}
+	if *output != "db" && *output != "json" {
+		log.Fatalf("unknown format: %v", *output)
+	}
+
+	from, _ := formats.names()
+	files, errs := expandInputs(fs.Args(), extMatcher(from, *ext))
+
+	bd := book.NewBuilder(book.BuilderMaxPly(*depth), book.BuilderMinCount(*minCount))
+	var added int
+	readFiles(files, formats.reader(), *workers, func(path string, k *ptypes.Kif, err error) {
+		if err == nil {
+			err = bd.Add(k)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+			return
+		}
+		added++
+	})
+	bk := bd.Book()
+
+	out, closeOut := openOutput()
+	defer closeOut()
+
+	var err error
+	if *output == "json" {
+		err = bk.WriteJSON(out)
+	} else {
+		err = bk.WriteYaneuraOu(out)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	log.Printf("games %d, positions %d, failed %d", added, len(bk.Positions), len(errs))
+	if len(errs) != 0 {
+		closeOut()
+		os.Exit(1)
+	}
+}
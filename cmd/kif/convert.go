@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+
+	"github.com/yunomu/kif"
+)
+
+func convert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file")
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, true)
+	outDir := fs.String("outdir", "", "Output directory of batch conversion")
+	ext := fs.String("ext", "", "Extension of the files to convert in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel conversions")
+	skipExisting := fs.Bool("skip-existing", false, "Do not convert files whose output exists")
+	verbose := fs.Bool("v", false, "Print converted files")
+	fillStrategy := fs.Bool("strategy", false, "Set the 戦型 header to the classified opening if it is empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif convert [flags] [file]\n")
+		fmt.Fprintf(fs.Output(), "       kif convert -outdir DIR [flags] files, globs or directories...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var writerOps []kif.WriterOption
+	if *fillStrategy {
+		writerOps = append(writerOps, kif.WriteFillStrategy())
+	}
+	read, write := formats.reader(), formats.writer(writerOps...)
+
+	if *outDir != "" {
+		if *outFile != "" {
+			log.Fatalln("-o cannot be used with -outdir")
+		}
+		if *workers < 1 {
+			log.Fatalln("-j must be positive")
+		}
+		from, to := formats.names()
+		convertBatch(fs.Args(), extMatcher(from, *ext), *outDir, formatDefs[to].ext, read, write, *workers, *skipExisting, *verbose)
+		return
+	}
+
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		*inFile = fs.Arg(0)
+	default:
+		log.Fatalln("multiple inputs need -outdir")
+	}
+
+	in, closeIn := openInput()
+	defer closeIn()
+
+	k, err := read(in)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	out, closeOut := openOutput()
+	defer closeOut()
+
+	if err := write(out, k); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func convertBatch(args []string, match func(string) bool, outDir, outExt string, read readFunc, write writeFunc, workers int, skipExisting, verbose bool) {
+	if len(args) == 0 {
+		args = []string{*inFile}
+		if *inFile == "" {
+			log.Fatalln("no input files")
+		}
+	}
+
+	jobs, errs := batchJobs(args, match, outDir, outExt)
+	res := runBatch(jobs, read, write, workers, skipExisting, func(job *batchJob) {
+		if verbose {
+			log.Printf("%s -> %s", job.src, job.dst)
+		}
+	})
+	errs = append(errs, res.errs...)
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	log.Printf("converted %d, skipped %d, failed %d", res.converted, res.skipped, len(errs))
+	if len(errs) != 0 {
+		os.Exit(1)
+	}
+}
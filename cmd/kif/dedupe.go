@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/yunomu/kif/collection"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func dedupe(args []string) {
+	fs := flag.NewFlagSet("dedupe", flag.ExitOnError)
+	formats := addFormatFlags(fs, true, false)
+	ext := fs.String("ext", "", "Extension of the files to read in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel readers")
+	minMoves := fs.Int("min", 20, "Number of moves records need to have in common unless all moves are the same")
+	maxDiff := fs.Int("diff", 2, "Number of the last moves which may be different")
+	list := fs.Bool("l", false, "Print only the duplicates to remove")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif dedupe [flags] files, globs or directories...\n")
+		fmt.Fprintf(fs.Output(), "\nPrints the groups of the records of the same game. The richest record of\n")
+		fmt.Fprintf(fs.Output(), "each group, with the most headers, comments and times, is marked keep.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		log.Fatalln("no input files")
+	}
+	if *workers < 1 {
+		log.Fatalln("-j must be positive")
+	}
+
+	from, _ := formats.names()
+	files, errs := expandInputs(fs.Args(), extMatcher(from, *ext))
+
+	var games []*collection.Game
+	readFiles(files, formats.reader(), *workers, func(path string, k *ptypes.Kif, err error) {
+		if err == nil {
+			// drop illegal games not to fail the grouping
+			_, err = collection.Fingerprint(k)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+			return
+		}
+		games = append(games, &collection.Game{ID: path, Kif: k})
+	})
+
+	// the files are read in parallel, and the first record wins ties
+	sort.Slice(games, func(i, j int) bool { return games[i].ID < games[j].ID })
+
+	m := collection.NewMatcher(collection.MatcherMinMoves(*minMoves), collection.MatcherMaxDiff(*maxDiff))
+	groups, err := m.Group(games)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var dups int
+	for i, group := range groups {
+		if !*list && i != 0 {
+			fmt.Println()
+		}
+		for j, g := range group {
+			switch {
+			case *list && j == 0:
+			case *list:
+				fmt.Println(g.ID)
+			case j == 0:
+				fmt.Printf("keep\t%s\n", g.ID)
+			default:
+				fmt.Printf("dup\t%s\n", g.ID)
+			}
+		}
+		dups += len(group) - 1
+	}
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	log.Printf("games %d, duplicates %d in %d groups, failed %d", len(games), dups, len(groups), len(errs))
+	if len(errs) != 0 {
+		os.Exit(1)
+	}
+}
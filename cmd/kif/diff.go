@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func diff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	formats := addFormatFlags(fs, true, false)
+	asJSON := fs.Bool("json", false, "Print the difference in JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif diff [flags] old new\n")
+		fmt.Fprintf(fs.Output(), "\nThe exit status is 0 if the games are the same, 1 if they differ, and 2 on errors.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var games []*ptypes.Kif
+	eachInput(fs.Args(), formats.reader(), func(name string, k *ptypes.Kif, err error) {
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+			os.Exit(2)
+		}
+		games = append(games, k)
+	})
+
+	d, err := kif.Diff(games[0], games[1])
+	if err != nil {
+		log.Println(err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	} else {
+		err = d.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Println(err)
+		os.Exit(2)
+	}
+
+	if !d.Empty() {
+		os.Exit(1)
+	}
+}
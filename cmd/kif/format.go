@@ -0,0 +1,327 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type readFunc func(io.Reader) (*ptypes.Kif, error)
+
+type writeFunc func(io.Writer, *ptypes.Kif) error
+
+type formatDef struct {
+	desc  string
+	ext   string
+	read  readFunc
+	write writeFunc
+	// kifWriter is set for the formats written by kif.Writer with writerOps,
+	// which have no write.
+	kifWriter bool
+	writerOps []kif.WriterOption
+}
+
+func (f *formatDef) writable() bool {
+	return f.write != nil || f.kifWriter
+}
+
+func kifWrite(ops ...kif.WriterOption) writeFunc {
+	return func(out io.Writer, k *ptypes.Kif) error {
+		return kif.NewWriter(ops...).Write(out, k)
+	}
+}
+
+var formatDefs = map[string]*formatDef{
+	"auto": {
+		desc: "Detect from the content and the file name (input only)",
+		read: autoRead,
+	},
+	"kif": {
+		desc:      "KIF (Shift_JIS)",
+		ext:       ".kif",
+		read:      sjisRead,
+		kifWriter: true,
+	},
+	"kifu": {
+		desc: "KIF (UTF-8)",
+		ext:  ".kifu",
+		read: func(in io.Reader) (*ptypes.Kif, error) {
+			return kif.NewParser(kif.ParseEncodingUTF8()).Parse(in)
+		},
+		kifWriter: true,
+		writerOps: []kif.WriterOption{kif.WriteEncodingUTF8()},
+	},
+	"ki2": {
+		desc: "KI2 (input only)",
+		ext:  ".ki2",
+		read: func(in io.Reader) (*ptypes.Kif, error) {
+			return kif.ReadAny(in, kif.ReadFormat(kif.InputFormat_KI2))
+		},
+	},
+	"csa": {
+		desc:      "CSA (written in Shift_JIS)",
+		ext:       ".csa",
+		read:      kif.ParseCSA,
+		kifWriter: true,
+		writerOps: []kif.WriterOption{kif.SetFormat(kif.Format_CSA)},
+	},
+	"jkf": {
+		desc:      "JSON Kifu Format",
+		ext:       ".jkf",
+		read:      kif.ParseJKF,
+		kifWriter: true,
+		writerOps: []kif.WriterOption{kif.SetFormat(kif.Format_JKF)},
+	},
+	"json": {
+		desc:  "Protocol Buffer (JSON)",
+		ext:   ".json",
+		read:  jsonRead,
+		write: jsonWrite,
+	},
+	"pb": {
+		desc:  "Protocol Buffer (byte strings)",
+		ext:   ".pb",
+		read:  binRead,
+		write: binWrite,
+	},
+	"sfen": {
+		desc:      "SFEN moves",
+		ext:       ".sfen",
+		read:      kif.ParseSFEN,
+		kifWriter: true,
+		writerOps: []kif.WriterOption{kif.SetFormat(kif.Format_SFEN)},
+	},
+	"html": {
+		desc:      "HTML viewer (output only)",
+		ext:       ".html",
+		kifWriter: true,
+		writerOps: []kif.WriterOption{kif.SetFormat(kif.Format_HTML)},
+	},
+}
+
+func formatNames(ok func(*formatDef) bool) []string {
+	var ret []string
+	for name, f := range formatDefs {
+		if ok(f) {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func formatHelp() string {
+	var sb strings.Builder
+	for _, name := range formatNames(func(*formatDef) bool { return true }) {
+		fmt.Fprintf(&sb, "\t%-5s %s\n", name, formatDefs[name].desc)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func lookupReader(name string) (readFunc, error) {
+	if f, ok := formatDefs[name]; ok && f.read != nil {
+		return f.read, nil
+	}
+	return nil, fmt.Errorf("unknown input format: %q (known: %s)",
+		name, strings.Join(formatNames(func(f *formatDef) bool { return f.read != nil }), ", "))
+}
+
+// lookupWriter returns the writer of the format, with the options of
+// kif.Writer added to those of the format.
+func lookupWriter(name string, ops ...kif.WriterOption) (writeFunc, error) {
+	f, ok := formatDefs[name]
+	switch {
+	case ok && f.kifWriter:
+		return kifWrite(append(append([]kif.WriterOption{}, f.writerOps...), ops...)...), nil
+	case ok && f.write != nil:
+		if len(ops) != 0 {
+			return nil, fmt.Errorf("the options are not supported for the output format: %q", name)
+		}
+		return f.write, nil
+	}
+	return nil, fmt.Errorf("unknown output format: %q (known: %s)",
+		name, strings.Join(formatNames((*formatDef).writable), ", "))
+}
+
+// autoRead detects the format, using the file name if in is a file.
+func autoRead(in io.Reader) (*ptypes.Kif, error) {
+	var ops []kif.ReadOption
+	if f, ok := in.(interface{ Name() string }); ok {
+		ops = append(ops, kif.ReadFilename(f.Name()))
+	}
+	return kif.ReadAny(in, ops...)
+}
+
+// matchExt reports whether the file has the extension of a format that can be
+// read by the reader of the name.
+func matchExt(name, path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if name != "auto" {
+		return ext == formatDefs[name].ext
+	}
+	for _, f := range formatDefs {
+		if f.read != nil && f.ext != "" && ext == f.ext {
+			return true
+		}
+	}
+	return false
+}
+
+func sjisRead(in io.Reader) (*ptypes.Kif, error) {
+	return kif.NewParser().Parse(in)
+}
+
+func jsonRead(in io.Reader) (*ptypes.Kif, error) {
+	unmarshaler := &jsonpb.Unmarshaler{
+		AllowUnknownFields: true,
+	}
+	kif := &ptypes.Kif{}
+	if err := unmarshaler.Unmarshal(in, kif); err != nil {
+		return nil, err
+	}
+	return kif, nil
+}
+
+func jsonWrite(out io.Writer, kif *ptypes.Kif) error {
+	marshaler := &jsonpb.Marshaler{
+		Indent:       "  ",
+		EmitDefaults: true,
+	}
+	return marshaler.Marshal(out, kif)
+}
+
+func binRead(in io.Reader) (*ptypes.Kif, error) {
+	bs, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	kif := &ptypes.Kif{}
+	if err := proto.Unmarshal(bs, kif); err != nil {
+		return nil, err
+	}
+	return kif, nil
+}
+
+func binWrite(out io.Writer, kif *ptypes.Kif) error {
+	bs, err := proto.Marshal(kif)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(bs)
+	return err
+}
+
+// legacyFormats are the letters of -fmt. Lower case letters select the input
+// format and upper case letters the output format.
+var legacyFormats = map[rune]string{
+	's': "kif",
+	'u': "kifu",
+	'j': "json",
+	'b': "pb",
+	'c': "csa",
+	'k': "jkf",
+	'S': "kif",
+	'U': "kifu",
+	'J': "json",
+	'B': "pb",
+	'F': "sfen",
+	'H': "html",
+	'C': "csa",
+	'K': "jkf",
+}
+
+const legacyFormatHelp = `Input/Output format letters (deprecated, use -from and -to)
+	s/S: kif (ShiftJIS) (default output)
+	u/U: kif (UTF8)
+	j/J: Protocol Buffer (JSON)
+	b/B: Protocol Buffer (byte strings)
+	  F: SFEN from
+	  H: HTML viewer
+	c/C: CSA
+	k/K: JKF (JSON Kifu Format)`
+
+// parseFormat returns the input and output formats of the letters of -fmt.
+func parseFormat(letters string) (from, to string, err error) {
+	from, to = "auto", "kif"
+	for _, r := range letters {
+		name, ok := legacyFormats[r]
+		if !ok {
+			return "", "", fmt.Errorf("unknown format letter: %q", r)
+		}
+		if unicode.IsLower(r) {
+			from = name
+		} else {
+			to = name
+		}
+	}
+	return from, to, nil
+}
+
+// formatFlags are the -from and -to flags of a subcommand, with -fmt as the
+// old syntax of both.
+type formatFlags struct {
+	from, to, letters string
+}
+
+// addFormatFlags registers the flags on fs. The defaults are the values of the
+// flags given before the subcommand.
+func addFormatFlags(fs *flag.FlagSet, input, output bool) *formatFlags {
+	f := &formatFlags{}
+	if globalFormat != nil {
+		*f = *globalFormat
+	}
+	if input {
+		fs.StringVar(&f.from, "from", f.from, "Input format (default auto)\n"+formatHelp())
+	}
+	if output {
+		fs.StringVar(&f.to, "to", f.to, "Output format (default kif)")
+	}
+	fs.StringVar(&f.letters, "fmt", f.letters, legacyFormatHelp)
+	return f
+}
+
+func (f *formatFlags) names() (string, string) {
+	from, to, err := parseFormat(f.letters)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if f.from != "" {
+		from = f.from
+	}
+	if f.to != "" {
+		to = f.to
+	}
+	return from, to
+}
+
+func (f *formatFlags) reader() readFunc {
+	from, _ := f.names()
+	read, err := lookupReader(from)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return read
+}
+
+// writer returns the writer of the output format with the options of
+// kif.Writer.
+func (f *formatFlags) writer(ops ...kif.WriterOption) writeFunc {
+	_, to := f.names()
+	write, err := lookupWriter(to, ops...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return write
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// grepSteps calls f with the location and the text of the moves and comments
+// including variations.
+func grepSteps(steps []*ptypes.Step, f func(loc, text string)) {
+	for _, s := range steps {
+		loc := fmt.Sprint(s.Seq)
+		f(loc, kif.PrintMove(s))
+		for _, note := range s.Notes {
+			f(loc, "*"+note)
+		}
+		for _, v := range s.Variations {
+			grepSteps(v.Steps, f)
+		}
+	}
+}
+
+func grep(args []string) {
+	fs := flag.NewFlagSet("grep", flag.ExitOnError)
+	formats := addFormatFlags(fs, true, false)
+	ignoreCase := fs.Bool("i", false, "Ignore case")
+	filesOnly := fs.Bool("l", false, "Print only the names of matching files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif grep [flags] PATTERN [files...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	pattern := fs.Arg(0)
+	if *ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	read := formats.reader()
+
+	matched, failed := false, false
+	eachInput(fs.Args()[1:], read, func(name string, k *ptypes.Kif, err error) {
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+			failed = true
+			return
+		}
+		found := false
+		match := func(loc, text string) {
+			if !re.MatchString(text) || (found && *filesOnly) {
+				return
+			}
+			found = true
+			if *filesOnly {
+				fmt.Println(name)
+			} else {
+				fmt.Printf("%s:%s:%s\n", name, loc, text)
+			}
+		}
+		for _, h := range k.Headers {
+			match("header", h.Name+"："+h.Value)
+		}
+		grepSteps(k.Steps, match)
+		matched = matched || found
+	})
+
+	switch {
+	case failed:
+		os.Exit(2)
+	case !matched:
+		os.Exit(1)
+	}
+}
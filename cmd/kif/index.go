@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+
+	"github.com/yunomu/kif/collection"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// indexBatch is the number of games added to the index in a transaction.
+const indexBatch = 500
+
+func index(args []string) {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	dbPath := fs.String("db", "kif.db", "Index file")
+	formats := addFormatFlags(fs, true, false)
+	ext := fs.String("ext", "", "Extension of the files to index in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel readers")
+	verbose := fs.Bool("v", false, "Print indexed files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif index [flags] files, globs or directories...\n")
+		fmt.Fprintf(fs.Output(), "\nGames are identified by their paths. Indexing a path again replaces the game.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		log.Fatalln("no input files")
+	}
+	if *workers < 1 {
+		log.Fatalln("-j must be positive")
+	}
+
+	from, _ := formats.names()
+	files, errs := expandInputs(fs.Args(), extMatcher(from, *ext))
+
+	db, err := collection.Open(*dbPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer db.Close()
+
+	var indexed int
+	var batch []*collection.Game
+	flush := func() {
+		if err := db.Add(batch...); err != nil {
+			log.Fatalln(err)
+		}
+		indexed += len(batch)
+		if *verbose {
+			for _, g := range batch {
+				log.Println(g.ID)
+			}
+		}
+		batch = batch[:0]
+	}
+	readFiles(files, formats.reader(), *workers, func(path string, k *ptypes.Kif, err error) {
+		if err == nil {
+			// check that the moves can be indexed, not to fail the batch
+			_, err = collection.Hashes(k)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+			return
+		}
+		batch = append(batch, &collection.Game{ID: path, Kif: k})
+		if len(batch) == indexBatch {
+			flush()
+		}
+	})
+	flush()
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	log.Printf("indexed %d, failed %d", indexed, len(errs))
+	if len(errs) != 0 {
+		os.Exit(1)
+	}
+}
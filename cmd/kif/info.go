@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func countVariations(steps []*ptypes.Step) int {
+	var n int
+	for _, s := range steps {
+		for _, v := range s.Variations {
+			n += 1 + countVariations(v.Steps)
+		}
+	}
+	return n
+}
+
+func info(args []string) {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file (when no files are given)")
+	formats := addFormatFlags(fs, true, false)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif info [flags] [files...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	read := formats.reader()
+
+	failed := false
+	first := true
+	eachInput(fs.Args(), read, func(name string, k *ptypes.Kif, err error) {
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+			failed = true
+			return
+		}
+		if fs.NArg() > 1 {
+			if !first {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", name)
+		}
+		first = false
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, h := range k.Headers {
+			fmt.Fprintf(w, "%s\t%s\n", h.Name, h.Value)
+		}
+		moves := len(k.Steps)
+		result := "-"
+		if moves != 0 {
+			if last := k.Steps[moves-1]; last.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+				moves--
+				result = kif.PrintMove(last)
+			}
+		}
+		fmt.Fprintf(w, "moves\t%d\n", moves)
+		fmt.Fprintf(w, "variations\t%d\n", countVariations(k.Steps))
+		fmt.Fprintf(w, "result\t%s\n", result)
+		w.Flush()
+	})
+	if failed {
+		os.Exit(1)
+	}
+}
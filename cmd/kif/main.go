@@ -2,155 +2,120 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
 
-	"github.com/golang/protobuf/jsonpb"
-	"github.com/golang/protobuf/proto"
-
-	"github.com/yunomu/kif"
 	"github.com/yunomu/kif/ptypes"
 )
 
 var (
 	inFile  = flag.String("f", "", "Input file")
 	outFile = flag.String("o", "", "Output file")
-	format  = flag.String("fmt", "", `Input/Output format
-	s/S: kif (ShiftJIS) (default)
-	u/U: kif (UTF8)
-	j/J: Protocol Buffer (JSON)
-	b/B: Protocol Buffer (byte strings)
-	  F: SFEN from
-`)
+
+	globalFormat *formatFlags
 )
 
 func init() {
-	flag.Parse()
 	log.SetOutput(os.Stderr)
+	globalFormat = addFormatFlags(flag.CommandLine, true, true)
+	flag.Usage = usage
 }
 
-func sjisRead(in io.Reader) (*ptypes.Kif, error) {
-	return kif.NewParser().Parse(in)
-}
-
-func sjisWrite(out io.Writer, k *ptypes.Kif) error {
-	kifWriter := kif.NewWriter()
-	return kifWriter.Write(out, k)
+var commands = []struct {
+	name string
+	desc string
+	run  func(args []string)
+}{
+	{"convert", "Convert a game to another format (default)", convert},
+	{"validate", "Check the legality of the moves", validate},
+	{"show", "Show a position", show},
+	{"info", "Print the headers and the result", info},
+	{"grep", "Search headers, moves and comments", grep},
+	{"index", "Index the positions of games", index},
+	{"search", "Find the games which reached a position", search},
+	{"book", "Build an opening book from games", buildBook},
+	{"dedupe", "Find the records of the same game", dedupe},
+	{"stats", "Print statistics of a set of games", statistics},
+	{"rating", "Rate the players of a set of games", rate},
+	{"merge", "Merge records of the same game", merge},
+	{"diff", "Compare two games", diff},
+	{"annotate", "Annotate a game with a USI engine", annotate},
+	{"review", "Classify the moves of an annotated game", review},
+	{"match", "Play a game between USI engines", playMatch},
+	{"serve", "Run a CSA game server", serve},
 }
 
-func jsonRead(in io.Reader) (*ptypes.Kif, error) {
-	unmarshaler := &jsonpb.Unmarshaler{
-		AllowUnknownFields: true,
-	}
-	kif := &ptypes.Kif{}
-	if err := unmarshaler.Unmarshal(in, kif); err != nil {
-		return nil, err
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] [command] [command flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-9s %s\n", c.name, c.desc)
 	}
-	return kif, nil
+	fmt.Fprintf(out, "\nWithout a command, the game is converted as by convert.\n\nFlags:\n")
+	flag.PrintDefaults()
 }
 
-func jsonWrite(out io.Writer, kif *ptypes.Kif) error {
-	marshaler := &jsonpb.Marshaler{
-		Indent:       "  ",
-		EmitDefaults: true,
+func openInput() (io.Reader, func()) {
+	if *inFile == "" {
+		return os.Stdin, func() {}
 	}
-	return marshaler.Marshal(out, kif)
-}
 
-func binRead(in io.Reader) (*ptypes.Kif, error) {
-	bs, err := ioutil.ReadAll(in)
+	f, err := os.Open(*inFile)
 	if err != nil {
-		return nil, err
-	}
-	kif := &ptypes.Kif{}
-	if err := proto.Unmarshal(bs, kif); err != nil {
-		return nil, err
+		log.Fatalln(err)
 	}
-	return kif, nil
+	return f, func() { f.Close() }
 }
 
-func binWrite(out io.Writer, kif *ptypes.Kif) error {
-	bs, err := proto.Marshal(kif)
-	if err != nil {
-		return err
+func openOutput() (io.Writer, func()) {
+	if *outFile == "" {
+		return os.Stdout, func() {}
 	}
-	_, err = out.Write(bs)
-	return err
-}
-
-func sfenWrite(out io.Writer, k *ptypes.Kif) error {
-	return kif.NewWriter(kif.SetFormat(kif.Format_SFEN)).Write(out, k)
-}
 
-func parseFormat(fmt string) (
-	read func(io.Reader) (*ptypes.Kif, error),
-	write func(io.Writer, *ptypes.Kif) error,
-) {
-	read = sjisRead
-	write = sjisWrite
-	for _, r := range []rune(fmt) {
-		switch r {
-		case 's':
-			read = sjisRead
-		case 'S':
-			write = sjisWrite
-		case 'u':
-			read = func(in io.Reader) (*ptypes.Kif, error) {
-				return kif.NewParser(kif.ParseEncodingUTF8()).Parse(in)
-			}
-		case 'U':
-			write = func(out io.Writer, k *ptypes.Kif) error {
-				kifWriter := kif.NewWriter(kif.WriteEncodingUTF8())
-				return kifWriter.Write(out, k)
-			}
-		case 'j':
-			read = jsonRead
-		case 'J':
-			write = jsonWrite
-		case 'b':
-			read = binRead
-		case 'B':
-			write = binWrite
-		case 'F':
-			write = sfenWrite
-		}
+	f, err := os.Create(*outFile)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	return
+	return f, func() { f.Close() }
 }
 
-func main() {
-	var in io.Reader = os.Stdin
-	if *inFile != "" {
-		f, err := os.Open(*inFile)
-		if err != nil {
-			log.Fatalln(err)
-		}
-		defer f.Close()
-
-		in = f
+// eachInput reads the games of the files in order, or the input of -f if no
+// files are given. The name of the standard input is "-".
+func eachInput(files []string, read readFunc, f func(name string, k *ptypes.Kif, err error)) {
+	if len(files) == 0 {
+		files = []string{*inFile}
 	}
-
-	var out io.Writer = os.Stdout
-	if *outFile != "" {
-		f, err := os.Create(*outFile)
+	for _, name := range files {
+		if name == "" || name == "-" {
+			k, err := read(os.Stdin)
+			f("-", k, err)
+			continue
+		}
+		in, err := os.Open(name)
 		if err != nil {
-			log.Fatalln(err)
+			f(name, nil, err)
+			continue
 		}
-		defer f.Close()
-
-		out = f
+		k, err := read(in)
+		in.Close()
+		f(name, k, err)
 	}
+}
 
-	read, write := parseFormat(*format)
+func main() {
+	flag.Parse()
 
-	kif, err := read(in)
-	if err != nil {
-		log.Fatalln(err)
+	if flag.NArg() == 0 {
+		convert(nil)
+		return
 	}
-
-	if err := write(out, kif); err != nil {
-		log.Fatalln(err)
+	for _, c := range commands {
+		if c.name == flag.Arg(0) {
+			c.run(flag.Args()[1:])
+			return
+		}
 	}
+	log.Fatalf("unknown command: %q (see -help)", flag.Arg(0))
 }
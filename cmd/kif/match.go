@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/clock"
+	"github.com/yunomu/kif/match"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/usi"
+)
+
+func startPlayer(ctx context.Context, path string) (*match.Player, func()) {
+	e, err := usi.Start(ctx, path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	name := e.Name
+	if name == "" {
+		name = path
+	}
+	return &match.Player{Name: name, Engine: e}, func() { e.Quit() }
+}
+
+func playMatch(args []string) {
+	fs := flag.NewFlagSet("match", flag.ExitOnError)
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, false, true)
+	sentePath := fs.String("sente", "", "Path to the USI engine of Sente (下手 in handicap games)")
+	gotePath := fs.String("gote", "", "Path to the USI engine of Gote (上手 in handicap games)")
+	handicap := fs.String("handicap", "平手", "手合割")
+	timeControl := fs.String("time", "", `Time control in 持ち時間 notation (e.g. "10分+秒読み30秒")`)
+	depth := fs.Int("depth", 0, "Search depth per move without -time")
+	movetime := fs.Duration("movetime", 0, "Search time per move without -time (default 1s)")
+	maxMoves := fs.Int("maxmoves", 256, "Draw after this number of moves (0 for no limit)")
+	verbose := fs.Bool("v", false, "Print moves")
+	fs.Parse(args)
+
+	if *sentePath == "" || *gotePath == "" {
+		log.Fatalln("-sente and -gote are required")
+	}
+
+	ops := []match.PlayOption{
+		match.PlayHandicap(*handicap),
+		match.PlayGoParams(usi.GoParams{Depth: *depth, MoveTime: *movetime}),
+		match.PlayMaxMoves(int32(*maxMoves)),
+	}
+	if *timeControl != "" {
+		tc, err := clock.ParseTimeControl(*timeControl)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ops = append(ops, match.PlayTimeControl(tc))
+	}
+	if *verbose {
+		ops = append(ops, match.PlayOnMove(func(s *ptypes.Step) {
+			log.Println(kif.PrintMove(s))
+		}))
+	}
+
+	ctx := context.Background()
+	sente, quitSente := startPlayer(ctx, *sentePath)
+	defer quitSente()
+	gote, quitGote := startPlayer(ctx, *gotePath)
+	defer quitGote()
+
+	r, err := match.Play(ctx, sente, gote, ops...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	write := formats.writer()
+	out, closeOut := openOutput()
+	defer closeOut()
+	if err := write(out, r.Kif); err != nil {
+		log.Fatalln(err)
+	}
+}
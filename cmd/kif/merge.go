@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func merge(args []string) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, true)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif merge [flags] file1 file2...\n")
+		fmt.Fprintf(fs.Output(), "\nMerges records of the same game. The headers of the earlier files win on conflicts.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var games []*ptypes.Kif
+	eachInput(fs.Args(), formats.reader(), func(name string, k *ptypes.Kif, err error) {
+		if err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		games = append(games, k)
+	})
+
+	k := games[0]
+	for i, g := range games[1:] {
+		merged, conflicts, err := kif.Merge(k, g)
+		if err != nil {
+			log.Fatalf("%s: %v", fs.Arg(i+1), err)
+		}
+		for _, c := range conflicts {
+			log.Printf("%s: header conflict: %v", fs.Arg(i+1), c)
+		}
+		k = merged
+	}
+
+	out, closeOut := openOutput()
+	defer closeOut()
+
+	write := formats.writer()
+	if err := write(out, k); err != nil {
+		log.Fatalln(err)
+	}
+}
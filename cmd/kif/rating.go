@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/rating"
+)
+
+const ratingDateFormat = "2006/01/02 15:04:05"
+
+// ratingRows returns the table of the players, or of their histories. The
+// rating deviations are printed if withRD is set.
+func ratingRows(players []*rating.Player, history, withRD bool) ([]string, [][]string) {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	}
+	ratings := func(r, rd float64) []string {
+		if withRD {
+			return []string{f(r), f(rd)}
+		}
+		return []string{f(r)}
+	}
+	ratingHeader := []string{"rating"}
+	if withRD {
+		ratingHeader = append(ratingHeader, "rd")
+	}
+
+	var rows [][]string
+	if history {
+		for _, p := range players {
+			for _, e := range p.History {
+				row := []string{p.Name, e.Date.Format(ratingDateFormat)}
+				rows = append(rows, append(row, ratings(e.Rating, e.RD)...))
+			}
+		}
+		return append([]string{"name", "date"}, ratingHeader...), rows
+	}
+	for _, p := range players {
+		row := append([]string{p.Name}, ratings(p.Rating, p.RD)...)
+		rows = append(rows, append(row,
+			strconv.Itoa(p.Games), strconv.Itoa(p.Wins), strconv.Itoa(p.Losses), strconv.Itoa(p.Draws),
+		))
+	}
+	header := append([]string{"name"}, ratingHeader...)
+	return append(header, "games", "wins", "losses", "draws"), rows
+}
+
+func readAliases(path string) (rating.Aliases, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return rating.ReadAliases(f)
+}
+
+func rate(args []string) {
+	fs := flag.NewFlagSet("rating", flag.ExitOnError)
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, false)
+	ext := fs.String("ext", "", "Extension of the files to read in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel readers")
+	system := fs.String("system", "elo", "Rating system: elo, glicko2")
+	aliasFile := fs.String("aliases", "", "File of player names: a name and its other spellings on each line, separated by tabs")
+	kFactor := fs.Float64("k", 32, "K-factor of Elo")
+	period := fs.Duration("period", 24*time.Hour, "Rating period of Glicko-2")
+	noHandicap := fs.Bool("nohandicap", false, "Rate handicap games as even games")
+	history := fs.Bool("history", false, "Print the rating history of each player")
+	output := fs.String("format", "text", "Output format: text, csv, json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif rating [flags] files, globs or directories...\n")
+		fmt.Fprintf(fs.Output(), "\nRates the players of the games in the order of 開始日時 (or 対局日).\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		log.Fatalln("no input files")
+	}
+	if *workers < 1 {
+		log.Fatalln("-j must be positive")
+	}
+	switch *output {
+	case "text", "csv", "json":
+	default:
+		log.Fatalf("unknown format: %v", *output)
+	}
+
+	handicaps := rating.DefaultHandicaps
+	if *noHandicap {
+		handicaps = map[string]float64{}
+	}
+	var rater rating.Rater
+	switch *system {
+	case "elo":
+		rater = rating.NewElo(rating.EloK(*kFactor), rating.EloHandicaps(handicaps))
+	case "glicko2":
+		rater = rating.NewGlicko2(rating.Glicko2Period(*period), rating.Glicko2Handicaps(handicaps))
+	default:
+		log.Fatalf("unknown rating system: %v", *system)
+	}
+
+	aliases, err := readAliases(*aliasFile)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	from, _ := formats.names()
+	files, errs := expandInputs(fs.Args(), extMatcher(from, *ext))
+
+	var games []*rating.Game
+	readFiles(files, formats.reader(), *workers, func(path string, k *ptypes.Kif, err error) {
+		var g *rating.Game
+		if err == nil {
+			g, err = rating.NewGame(path, k, aliases)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+			return
+		}
+		games = append(games, g)
+	})
+
+	// the files are read in parallel, and games at the same time are rated
+	// in the order of the paths
+	sort.Slice(games, func(i, j int) bool { return games[i].ID < games[j].ID })
+	players := rater.Rate(games)
+
+	out, closeOut := openOutput()
+	defer closeOut()
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(players)
+	case "csv":
+		header, rows := ratingRows(players, *history, *system == "glicko2")
+		w := csv.NewWriter(out)
+		w.Write(header)
+		w.WriteAll(rows)
+		err = w.Error()
+	default:
+		header, rows := ratingRows(players, *history, *system == "glicko2")
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		err = w.Flush()
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	log.Printf("games %d, players %d, failed %d", len(games), len(players), len(errs))
+	if len(errs) != 0 {
+		closeOut()
+		os.Exit(1)
+	}
+}
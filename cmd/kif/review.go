@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/yunomu/kif/analysis"
+)
+
+var sideNames = []string{"sente", "gote"}
+
+func review(args []string) {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file")
+	formats := addFormatFlags(fs, true, false)
+	inaccuracy := fs.Float64("inaccuracy", analysis.DefaultThresholds.Inaccuracy, "Win probability drop of an inaccuracy")
+	mistake := fs.Float64("mistake", analysis.DefaultThresholds.Mistake, "Win probability drop of a mistake")
+	blunder := fs.Float64("blunder", analysis.DefaultThresholds.Blunder, "Win probability drop of a blunder")
+	fs.Parse(args)
+
+	in, closeIn := openInput()
+	defer closeIn()
+
+	read := formats.reader()
+	k, err := read(in)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	analysis.LoadEvaluations(k)
+
+	t := analysis.DefaultThresholds
+	t.Inaccuracy, t.Mistake, t.Blunder = *inaccuracy, *mistake, *blunder
+	r, err := analysis.Classify(k, analysis.ClassifyThresholds(t))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "seq\tside\tbefore\tafter\tclass")
+	for _, m := range r.Moves {
+		fmt.Fprintf(w, "%d\t%s\t%.1f%%\t%.1f%%\t%s\n", m.Seq, sideNames[m.Side], m.Before*100, m.After*100, m.Class)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "side\tmoves\taccuracy\tinaccuracy\tmistake\tblunder")
+	for _, s := range r.Players {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%d\t%d\n", sideNames[s.Side], s.Moves, s.Accuracy,
+			s.Counts[analysis.Class_INACCURACY], s.Counts[analysis.Class_MISTAKE], s.Counts[analysis.Class_BLUNDER])
+	}
+	w.Flush()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/collection"
+)
+
+func search(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	dbPath := fs.String("db", "kif.db", "Index file")
+	fs.StringVar(inFile, "f", *inFile, "Search the position of a game instead of a SFEN")
+	formats := addFormatFlags(fs, true, false)
+	ply := fs.Int("ply", -1, "Search the position after N moves of the game of -f (default: last)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif search [flags] SFEN|startpos\n")
+		fmt.Fprintf(fs.Output(), "       kif search [flags] -f file [-ply N]\n")
+		fmt.Fprintf(fs.Output(), "\nPrints the games which reached the position and the numbers of moves before it.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var b *board.Board
+	if fs.NArg() != 0 {
+		if *inFile != "" {
+			log.Fatalln("SFEN cannot be used with -f")
+		}
+		sfen := strings.TrimPrefix(strings.Join(fs.Args(), " "), "position ")
+		sfen = strings.TrimPrefix(sfen, "sfen ")
+		if sfen == "startpos" {
+			sfen = board.StartPos
+		}
+		var err error
+		b, err = board.FromSFEN(sfen)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	} else {
+		if *inFile == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+		in, closeIn := openInput()
+		defer closeIn()
+
+		read := formats.reader()
+		k, err := read(in)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		b, err = board.AtPly(k, int32(*ply))
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	db, err := collection.Open(*dbPath, collection.DBReadOnly())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer db.Close()
+
+	hits, err := db.Search(b)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, h := range hits {
+		fmt.Printf("%s\t%d\n", h.ID, h.Ply)
+	}
+}
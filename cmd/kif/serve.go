@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/clock"
+	"github.com/yunomu/kif/csa"
+)
+
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":4081", "Address to listen on")
+	dir := fs.String("dir", ".", "Directory to store finished games")
+	handicap := fs.String("handicap", "平手", "手合割")
+	timeControl := fs.String("time", "10分+秒読み10秒", "Time control in 持ち時間 notation")
+	maxMoves := fs.Int("maxmoves", 256, "Draw after this number of moves (0 for no limit)")
+	verbose := fs.Bool("v", false, "Print the communication")
+	fs.Parse(args)
+
+	tc, err := clock.ParseTimeControl(*timeControl)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ops := []csa.ServerOption{
+		csa.ServerDir(*dir),
+		csa.ServerHandicap(*handicap),
+		csa.ServerTimeControl(tc),
+		csa.ServerMaxMoves(*maxMoves),
+		csa.ServerOnGame(func(g *csa.Game) {
+			last := g.Kif.Steps[len(g.Kif.Steps)-1]
+			log.Printf("%s: %s vs %s: %s", g.Summary.ID, g.Summary.Names[0], g.Summary.Names[1], kif.PrintMove(last))
+		}),
+	}
+	if *verbose {
+		ops = append(ops, csa.ServerLogger(func(name string, send bool, line string) {
+			dir := "<"
+			if send {
+				dir = ">"
+			}
+			log.Printf("%s %s %s", name, dir, line)
+		}))
+	}
+
+	log.Fatalln(csa.NewServer(ops...).ListenAndServe(*addr))
+}
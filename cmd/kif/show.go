@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/render"
+)
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func show(args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file")
+	formats := addFormatFlags(fs, true, false)
+	ply := fs.Int("ply", -1, "Show the position after N moves (default: last)")
+	style := fs.String("style", "bod", "Board style: bod, western, svg, png, gif (whole game)")
+	arrow := fs.Bool("arrow", false, "Draw an arrow for the last move (svg)")
+	delay := fs.Duration("delay", time.Second, "Delay between frames (gif)")
+	color := fs.String("color", "auto", "Highlight the last move: auto, always, never")
+	fs.Parse(args)
+
+	var ops []render.TextOption
+	switch *style {
+	case "bod":
+		ops = append(ops, render.SetTextStyle(render.TextStyle_BOD))
+	case "western":
+		ops = append(ops, render.SetTextStyle(render.TextStyle_WESTERN))
+	case "svg", "png", "gif":
+	default:
+		log.Fatalf("unknown style: %v", *style)
+	}
+	switch *color {
+	case "auto":
+		ops = append(ops, render.SetColor(isTerminal(os.Stdout)))
+	case "always":
+		ops = append(ops, render.SetColor(true))
+	case "never":
+		ops = append(ops, render.SetColor(false))
+	default:
+		log.Fatalf("unknown color mode: %v", *color)
+	}
+
+	in, closeIn := openInput()
+	defer closeIn()
+
+	read := formats.reader()
+	k, err := read(in)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch *style {
+	case "svg":
+		r := render.NewSVGRenderer(render.SVGArrowLastMove(*arrow))
+		if err := r.RenderPly(os.Stdout, k, int32(*ply)); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	case "png":
+		b, err := board.AtPly(k, int32(*ply))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := render.NewImageRenderer().WritePNG(os.Stdout, b); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	case "gif":
+		r := render.NewImageRenderer(render.ImageDelay(*delay))
+		if err := r.WriteGIF(os.Stdout, k); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if err := render.NewTextRenderer(ops...).RenderPly(os.Stdout, k, int32(*ply)); err != nil {
+		log.Fatalln(err)
+	}
+}
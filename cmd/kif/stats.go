@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/stats"
+)
+
+func statistics(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.StringVar(outFile, "o", *outFile, "Output file")
+	formats := addFormatFlags(fs, true, false)
+	ext := fs.String("ext", "", "Extension of the files to read in directories (default: by -from)")
+	workers := fs.Int("j", runtime.NumCPU(), "Number of parallel readers")
+	bucket := fs.Int("bucket", 10, "Width of the game length distribution in moves")
+	maxSeq := fs.Int("maxseq", 200, "Last move number of the thinking times")
+	output := fs.String("format", "text", "Output format: text, csv, json")
+	tables := fs.String("table", "", "Comma separated tables to print, required for csv: "+strings.Join(stats.Tables, ", "))
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif stats [flags] files, globs or directories...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		log.Fatalln("no input files")
+	}
+	if *workers < 1 {
+		log.Fatalln("-j must be positive")
+	}
+	var names []string
+	if *tables != "" {
+		names = strings.Split(*tables, ",")
+	}
+	switch *output {
+	case "text", "json":
+	case "csv":
+		if len(names) != 1 {
+			log.Fatalln("-table must be one table for csv")
+		}
+	default:
+		log.Fatalf("unknown format: %v", *output)
+	}
+
+	from, _ := formats.names()
+	files, errs := expandInputs(fs.Args(), extMatcher(from, *ext))
+
+	c := stats.NewCollector(stats.CollectorLengthBucket(*bucket), stats.CollectorMaxSeq(*maxSeq))
+	readFiles(files, formats.reader(), *workers, func(path string, k *ptypes.Kif, err error) {
+		if err == nil {
+			err = c.Add(k)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+		}
+	})
+	r := c.Report()
+
+	out, closeOut := openOutput()
+	defer closeOut()
+
+	var err error
+	switch *output {
+	case "json":
+		err = r.WriteJSON(out)
+	case "csv":
+		err = r.WriteCSV(out, names[0])
+	default:
+		err = r.WriteText(out, names...)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, err := range errs {
+		log.Println(err)
+	}
+	if len(errs) != 0 {
+		closeOut()
+		os.Exit(1)
+	}
+}
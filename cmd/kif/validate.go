@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func validate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.StringVar(inFile, "f", *inFile, "Input file (when no files are given)")
+	formats := addFormatFlags(fs, true, false)
+	verbose := fs.Bool("v", false, "Print valid files too")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kif validate [flags] [files...]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	read := formats.reader()
+
+	invalid := false
+	eachInput(fs.Args(), read, func(name string, k *ptypes.Kif, err error) {
+		if err != nil {
+			fmt.Printf("%s: %v\n", name, err)
+			invalid = true
+			return
+		}
+		errs := kif.Validate(k)
+		for _, e := range errs {
+			fmt.Printf("%s: %v\n", name, e)
+		}
+		if len(errs) != 0 {
+			invalid = true
+		} else if *verbose {
+			fmt.Printf("%s: ok\n", name)
+		}
+	})
+	if invalid {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,305 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+var (
+	// id -> serial
+	idsBucket = []byte("ids")
+	// serial -> id
+	gamesBucket = []byte("games")
+	// serial -> the hashes of the positions of the game, to remove them on update
+	hashesBucket = []byte("hashes")
+	// hash + serial + ply -> empty
+	positionsBucket = []byte("positions")
+
+	buckets = [][]byte{idsBucket, gamesBucket, hashesBucket, positionsBucket}
+)
+
+const (
+	hashLen     = 8
+	serialLen   = 4
+	plyLen      = 2
+	positionLen = hashLen + serialLen + plyLen
+)
+
+// DB is an index of the positions of games. Every position of the main lines,
+// including the initial position, is indexed by its Zobrist hash.
+type DB struct {
+	db *bolt.DB
+
+	readOnly bool
+	timeout  time.Duration
+}
+
+type DBOption func(*DB)
+
+// DBReadOnly opens the index for searching only, allowing other processes to
+// search at the same time.
+func DBReadOnly() DBOption {
+	return func(db *DB) {
+		db.readOnly = true
+	}
+}
+
+// DBTimeout sets how long to wait for another process using the index.
+// The default is 0, which waits indefinitely.
+func DBTimeout(d time.Duration) DBOption {
+	return func(db *DB) {
+		db.timeout = d
+	}
+}
+
+// Open opens the index file, creating it if it does not exist.
+func Open(path string, ops ...DBOption) (*DB, error) {
+	ret := &DB{}
+	for _, f := range ops {
+		f(ret)
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{
+		Timeout:  ret.timeout,
+		ReadOnly: ret.readOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret.db = db
+
+	if !ret.readOnly {
+		if err := db.Update(func(tx *bolt.Tx) error {
+			for _, name := range buckets {
+				if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (db *DB) Close() error {
+	return db.db.Close()
+}
+
+type Game struct {
+	// ID identifies the game in the collection, e.g. its file name.
+	ID  string
+	Kif *ptypes.Kif
+}
+
+// Hashes returns the hashes of the positions of the main line. The ith hash is
+// of the position after i moves.
+func Hashes(k *ptypes.Kif) ([]uint64, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []uint64{b.Hash()}
+	for _, step := range k.GetSteps() {
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		ret = append(ret, b.Hash())
+	}
+	return ret, nil
+}
+
+func positionKey(hash uint64, serial uint32, ply int) []byte {
+	key := make([]byte, positionLen)
+	binary.BigEndian.PutUint64(key, hash)
+	binary.BigEndian.PutUint32(key[hashLen:], serial)
+	binary.BigEndian.PutUint16(key[hashLen+serialLen:], uint16(ply))
+	return key
+}
+
+// Add indexes the games in a transaction. A game with an ID already in the
+// index replaces the old one.
+func (db *DB) Add(games ...*Game) error {
+	type indexed struct {
+		id     string
+		hashes []uint64
+	}
+	var idx []*indexed
+	for _, g := range games {
+		hashes, err := Hashes(g.Kif)
+		if err != nil {
+			return errors.Wrapf(err, "id=%v", g.ID)
+		}
+		if len(hashes) > 1<<(8*plyLen) {
+			return errors.Errorf("id=%v too many moves: %v", g.ID, len(hashes)-1)
+		}
+		idx = append(idx, &indexed{id: g.ID, hashes: hashes})
+	}
+
+	return db.db.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(idsBucket)
+		gamesB := tx.Bucket(gamesBucket)
+		hashesB := tx.Bucket(hashesBucket)
+		positions := tx.Bucket(positionsBucket)
+
+		for _, g := range idx {
+			serialKey := clone(ids.Get([]byte(g.id)))
+			if serialKey != nil {
+				if err := removePositions(tx, serialKey); err != nil {
+					return err
+				}
+			} else {
+				seq, err := ids.NextSequence()
+				if err != nil {
+					return err
+				}
+				if seq > 1<<(8*serialLen)-1 {
+					return errors.New("too many games")
+				}
+				serialKey = make([]byte, serialLen)
+				binary.BigEndian.PutUint32(serialKey, uint32(seq))
+				if err := ids.Put([]byte(g.id), serialKey); err != nil {
+					return err
+				}
+				if err := gamesB.Put(serialKey, []byte(g.id)); err != nil {
+					return err
+				}
+			}
+			serial := binary.BigEndian.Uint32(serialKey)
+
+			hashes := make([]byte, len(g.hashes)*hashLen)
+			for ply, h := range g.hashes {
+				if err := positions.Put(positionKey(h, serial, ply), nil); err != nil {
+					return err
+				}
+				binary.BigEndian.PutUint64(hashes[ply*hashLen:], h)
+			}
+			if err := hashesB.Put(serialKey, hashes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// clone copies a value of the store, which is only valid until the
+// transaction modifies the store.
+func clone(bs []byte) []byte {
+	if bs == nil {
+		return nil
+	}
+	return append([]byte{}, bs...)
+}
+
+func removePositions(tx *bolt.Tx, serialKey []byte) error {
+	serial := binary.BigEndian.Uint32(serialKey)
+	positions := tx.Bucket(positionsBucket)
+	old := clone(tx.Bucket(hashesBucket).Get(serialKey))
+	for i := 0; i+hashLen <= len(old); i += hashLen {
+		key := positionKey(binary.BigEndian.Uint64(old[i:]), serial, i/hashLen)
+		if err := positions.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove removes the game from the index. It is not an error if the game is
+// not in the index.
+func (db *DB) Remove(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(idsBucket)
+		serialKey := clone(ids.Get([]byte(id)))
+		if serialKey == nil {
+			return nil
+		}
+
+		if err := removePositions(tx, serialKey); err != nil {
+			return err
+		}
+		if err := tx.Bucket(hashesBucket).Delete(serialKey); err != nil {
+			return err
+		}
+		if err := tx.Bucket(gamesBucket).Delete(serialKey); err != nil {
+			return err
+		}
+		return ids.Delete([]byte(id))
+	})
+}
+
+// Len returns the number of games in the index.
+func (db *DB) Len() (int, error) {
+	var n int
+	err := db.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(idsBucket)
+		if b == nil {
+			return nil
+		}
+		n = b.Stats().KeyN
+		return nil
+	})
+	return n, err
+}
+
+type Hit struct {
+	ID string
+	// Ply is the number of moves before the position.
+	Ply int32
+}
+
+// Search returns the games which reached the position, in the order they were
+// first added. A game which reached the position several times has a hit for
+// each.
+func (db *DB) Search(b *board.Board) ([]*Hit, error) {
+	prefix := make([]byte, hashLen)
+	binary.BigEndian.PutUint64(prefix, b.Hash())
+
+	var ret []*Hit
+	err := db.db.View(func(tx *bolt.Tx) error {
+		positions := tx.Bucket(positionsBucket)
+		if positions == nil {
+			return nil
+		}
+		games := tx.Bucket(gamesBucket)
+
+		c := positions.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			id := games.Get(k[hashLen : hashLen+serialLen])
+			if id == nil {
+				return errors.Errorf("game not found: serial=%v", binary.BigEndian.Uint32(k[hashLen:]))
+			}
+			ret = append(ret, &Hit{
+				ID:  string(id),
+				Ply: int32(binary.BigEndian.Uint16(k[hashLen+serialLen:])),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// SearchSFEN returns the games which reached the position of the SFEN.
+func (db *DB) SearchSFEN(sfen string) ([]*Hit, error) {
+	b, err := board.FromSFEN(sfen)
+	if err != nil {
+		return nil, err
+	}
+	return db.Search(b)
+}
@@ -0,0 +1,118 @@
+package collection
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func openTemp(t *testing.T) (*DB, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "collection")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db, err := Open(filepath.Join(dir, "index.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDB(t *testing.T) {
+	db, closeDB := openTemp(t)
+	defer closeDB()
+
+	var games []*Game
+	for _, g := range []struct {
+		id, sfen string
+	}{
+		{"a", "startpos moves 7g7f 3c3d 2g2f"},
+		{"b", "startpos moves 2g2f 3c3d 7g7f 8c8d"},
+		{"c", "startpos moves 5i5h 5a5b 5h5i 5b5a"},
+	} {
+		k, err := kif.ParseSFEN(strings.NewReader(g.sfen))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", g.id, err)
+		}
+		games = append(games, &Game{ID: g.id, Kif: k})
+	}
+	if err := db.Add(games...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	search := func(sfen string, expected []*Hit) {
+		t.Helper()
+		hits, err := db.SearchSFEN(sfen)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(hits, expected) {
+			var actual []Hit
+			for _, h := range hits {
+				actual = append(actual, *h)
+			}
+			t.Errorf("%v: actual=%v", sfen, actual)
+		}
+	}
+
+	// reached by transposition
+	after3 := "lnsgkgsnl/1r5b1/pppppp1pp/6p2/9/2P4P1/PP1PPPP1P/1B5R1/LNSGKGSNL w - 4"
+	search(after3, []*Hit{{ID: "a", Ply: 3}, {ID: "b", Ply: 3}})
+	search(board.StartPos, []*Hit{{ID: "a", Ply: 0}, {ID: "b", Ply: 0}, {ID: "c", Ply: 0}, {ID: "c", Ply: 4}})
+	search("9/9/9/9/4k4/9/9/9/4K4 b - 1", nil)
+
+	if n, err := db.Len(); err != nil || n != 3 {
+		t.Errorf("len: expected=3 actual=%v err=%v", n, err)
+	}
+
+	// replace a game
+	a, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.Add(&Game{ID: "a", Kif: a}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	search(after3, []*Hit{{ID: "b", Ply: 3}})
+
+	if err := db.Remove("b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	search(after3, nil)
+	search(board.StartPos, []*Hit{{ID: "a", Ply: 0}, {ID: "c", Ply: 0}, {ID: "c", Ply: 4}})
+	if n, err := db.Len(); err != nil || n != 2 {
+		t.Errorf("len: expected=2 actual=%v err=%v", n, err)
+	}
+}
+
+func TestDB_AddIllegal(t *testing.T) {
+	db, closeDB := openTemp(t)
+	defer closeDB()
+
+	ok, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bad := proto.Clone(ok).(*ptypes.Kif)
+	bad.Steps = append(bad.Steps, &ptypes.Step{Seq: 2, Src: &ptypes.Pos{X: 5, Y: 5}, Dst: &ptypes.Pos{X: 5, Y: 6}, Piece: ptypes.Piece_FU})
+	if err := db.Add(&Game{ID: "ok", Kif: ok}, &Game{ID: "bad", Kif: bad}); err == nil {
+		t.Fatalf("expected error")
+	}
+	if n, err := db.Len(); err != nil || n != 0 {
+		t.Errorf("nothing must be added: actual=%v err=%v", n, err)
+	}
+}
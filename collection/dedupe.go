@@ -0,0 +1,234 @@
+package collection
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// moveList returns the initial position and the moves of the main line in USI
+// notation.
+func moveList(k *ptypes.Kif) (string, []string, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return "", nil, err
+	}
+	start := b.SFEN()
+
+	var moves []string
+	for _, step := range k.GetSteps() {
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return "", nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		moves = append(moves, kif.StepToMove(b.Last))
+	}
+	return start, moves, nil
+}
+
+func fingerprint(start string, moves []string) string {
+	sum := sha1.Sum([]byte(start + "\n" + strings.Join(moves, " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint identifies the game by its initial position and the moves of
+// the main line. Headers, times, comments, variations and the finishing step
+// are ignored.
+func Fingerprint(k *ptypes.Kif) (string, error) {
+	start, moves, err := moveList(k)
+	if err != nil {
+		return "", err
+	}
+	return fingerprint(start, moves), nil
+}
+
+// Richness scores how much a record has: the headers, comments and times, and
+// the moves of the main line.
+func Richness(k *ptypes.Kif) int {
+	var n int
+	for _, h := range k.GetHeaders() {
+		if h.Value != "" {
+			n++
+		}
+	}
+	var count func(steps []*ptypes.Step)
+	count = func(steps []*ptypes.Step) {
+		for _, s := range steps {
+			n += len(s.Notes)
+			for _, v := range s.Variations {
+				count(v.Steps)
+			}
+		}
+	}
+	count(k.GetSteps())
+	for _, s := range k.GetSteps() {
+		n++
+		if s.ThinkingSec != 0 || s.ThinkingNanos != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Matcher finds the records of the same game.
+type Matcher struct {
+	minMoves int
+	maxDiff  int
+}
+
+type MatcherOption func(*Matcher)
+
+// MatcherMinMoves sets the number of moves two records need to have in common
+// unless they have the same moves. The default is 20. With 0, all the records
+// from the same position are compared, which is slow for large sets.
+func MatcherMinMoves(n int) MatcherOption {
+	return func(m *Matcher) {
+		m.minMoves = n
+	}
+}
+
+// MatcherMaxDiff sets the number of the last moves which may be different.
+// The default is 2.
+func MatcherMaxDiff(n int) MatcherOption {
+	return func(m *Matcher) {
+		m.maxDiff = n
+	}
+}
+
+func NewMatcher(ops ...MatcherOption) *Matcher {
+	m := &Matcher{
+		minMoves: 20,
+		maxDiff:  2,
+	}
+	for _, f := range ops {
+		f(m)
+	}
+	return m
+}
+
+func (m *Matcher) similar(a, b []string) bool {
+	c := 0
+	for c < len(a) && c < len(b) && a[c] == b[c] {
+		c++
+	}
+	if c == len(a) && c == len(b) {
+		return true
+	}
+	if c < m.minMoves {
+		return false
+	}
+	// one is truncated, or they have different endings
+	return c == len(a) || c == len(b) || (len(a)-c <= m.maxDiff && len(b)-c <= m.maxDiff)
+}
+
+// Match reports whether the records are of the same game: they start from the
+// same position, and have the same moves except that one may be truncated or
+// they may have a few different moves at the end.
+func (m *Matcher) Match(a, b *ptypes.Kif) (bool, error) {
+	startA, movesA, err := moveList(a)
+	if err != nil {
+		return false, err
+	}
+	startB, movesB, err := moveList(b)
+	if err != nil {
+		return false, err
+	}
+	return startA == startB && m.similar(movesA, movesB), nil
+}
+
+// Group returns the groups of the records of the same game, with the richest
+// record first. Games without duplicates are not returned.
+func (m *Matcher) Group(games []*Game) ([][]*Game, error) {
+	type entry struct {
+		start string
+		moves []string
+	}
+	entries := make([]*entry, len(games))
+	for i, g := range games {
+		start, moves, err := moveList(g.Kif)
+		if err != nil {
+			return nil, errors.Wrapf(err, "id=%v", g.ID)
+		}
+		entries[i] = &entry{start: start, moves: moves}
+	}
+
+	parents := make([]int, len(games))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	union := func(i, j int) {
+		if pi, pj := find(i), find(j); pi != pj {
+			parents[pj] = pi
+		}
+	}
+
+	exact := make(map[string]int)
+	// records with the same first moves, compared with each other
+	buckets := make(map[string][]int)
+	for i, e := range entries {
+		fp := fingerprint(e.start, e.moves)
+		if j, ok := exact[fp]; ok {
+			union(j, i)
+			continue
+		}
+		exact[fp] = i
+
+		// without the minimum, all the records from a position are compared
+		prefix := m.minMoves
+		if prefix < 0 {
+			prefix = 0
+		}
+		if len(e.moves) >= prefix {
+			key := fingerprint(e.start, e.moves[:prefix])
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+	for _, bucket := range buckets {
+		for x, i := range bucket {
+			for _, j := range bucket[x+1:] {
+				if find(i) != find(j) && m.similar(entries[i].moves, entries[j].moves) {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*Game)
+	var roots []int
+	for i, g := range games {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], g)
+	}
+
+	var ret [][]*Game
+	for _, r := range roots {
+		group := groups[r]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return Richness(group[i].Kif) > Richness(group[j].Kif)
+		})
+		ret = append(ret, group)
+	}
+	return ret, nil
+}
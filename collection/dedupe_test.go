@@ -0,0 +1,160 @@
+package collection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestFingerprint(t *testing.T) {
+	a, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f 3c3d 2g2f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f 3c3d 2g2f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.Headers = []*ptypes.Header{{Name: "先手", Value: "A"}}
+	b.Steps[0].ThinkingSec = 10
+	b.Steps[1].Notes = []string{"comment"}
+	b.Steps = append(b.Steps, &ptypes.Step{Seq: 4, FinishedStatus: ptypes.FinishedStatus_SURRENDER})
+	c, err := kif.ParseSFEN(strings.NewReader("startpos moves 2g2f 3c3d 7g7f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fa, err := Fingerprint(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fb, err := Fingerprint(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fa != fb {
+		t.Errorf("fingerprints differ: %v %v", fa, fb)
+	}
+
+	fc, err := Fingerprint(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fc == fa {
+		t.Errorf("other move order must differ")
+	}
+
+	if ra, rb := Richness(a), Richness(b); ra >= rb {
+		t.Errorf("richness: a=%v b=%v", ra, rb)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	const moves = "7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 3i4h 8b4b"
+	m := NewMatcher(MatcherMinMoves(4), MatcherMaxDiff(1))
+
+	base, err := kif.ParseSFEN(strings.NewReader("startpos moves " + moves))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		moves    string
+		expected bool
+	}{
+		{"same", moves, true},
+		{"truncated", "7g7f 3c3d 2g2f 4c4d 2f2e", true},
+		{"too short", "7g7f 3c3d 2g2f", false},
+		{"different last move", "7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 3i4h 8b5b", true},
+		{"different last moves", "7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 5i6h 8b5b", false},
+		{"different start", "2g2f", false},
+	}
+	for _, test := range tests {
+		k, err := kif.ParseSFEN(strings.NewReader("startpos moves " + test.moves))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.name, err)
+		}
+		ok, err := m.Match(base, k)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.name, err)
+		}
+		if ok != test.expected {
+			t.Errorf("%v: expected=%v actual=%v", test.name, test.expected, ok)
+		}
+	}
+}
+
+func TestMatcher_Group(t *testing.T) {
+	var games []*Game
+	for _, g := range []struct {
+		id, sfen string
+	}{
+		{"plain", "startpos moves 7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 3i4h 8b4b"},
+		{"other", "startpos moves 2g2f 8c8d"},
+		{"truncated", "startpos moves 7g7f 3c3d 2g2f 4c4d 2f2e 2b3c"},
+		{"rich", "startpos moves 7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 3i4h 8b4b"},
+		{"other2", "startpos moves 2g2f 3c3d"},
+	} {
+		k, err := kif.ParseSFEN(strings.NewReader(g.sfen))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", g.id, err)
+		}
+		games = append(games, &Game{ID: g.id, Kif: k})
+	}
+	games[3].Kif.Headers = append(games[3].Kif.Headers,
+		&ptypes.Header{Name: "先手", Value: "A"},
+		&ptypes.Header{Name: "後手", Value: "B"},
+	)
+
+	groups, err := NewMatcher(MatcherMinMoves(4)).Group(games)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("groups: expected=1 actual=%v", len(groups))
+	}
+	var ids []string
+	for _, g := range groups[0] {
+		ids = append(ids, g.ID)
+	}
+	if len(ids) != 3 || ids[0] != "rich" || ids[1] != "plain" || ids[2] != "truncated" {
+		t.Errorf("group: actual=%v", ids)
+	}
+}
+
+func TestMatcher_GroupNoMinimum(t *testing.T) {
+	var games []*Game
+	for _, g := range []struct {
+		id, sfen string
+	}{
+		{"a", "startpos moves 2g2f 8c8d"},
+		{"b", "startpos moves 2g2f 3c3d"},
+		{"c", "startpos moves 7g7f 3c3d 2g2f 4c4d"},
+	} {
+		k, err := kif.ParseSFEN(strings.NewReader(g.sfen))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", g.id, err)
+		}
+		games = append(games, &Game{ID: g.id, Kif: k})
+	}
+	m := NewMatcher(MatcherMinMoves(0))
+
+	ok, err := m.Match(games[0].Kif, games[1].Kif)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected match")
+	}
+
+	groups, err := m.Group(games)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Errorf("groups: %v", groups)
+	}
+}
@@ -0,0 +1,307 @@
+package kif
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/japanese"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// csaHeaders maps header names to the CSA information lines.
+var csaHeaders = map[string]string{
+	"先手":   "N+",
+	"下手":   "N+",
+	"後手":   "N-",
+	"上手":   "N-",
+	"棋戦":   "$EVENT:",
+	"場所":   "$SITE:",
+	"開始日時": "$START_TIME:",
+	"終了日時": "$END_TIME:",
+	"戦型":   "$OPENING:",
+}
+
+// specialMove returns the name of the final step used by CSA and JKF.
+// turn is the side to move at the step.
+func specialMove(s ptypes.FinishedStatus_Id, turn board.Side) string {
+	sign := func(side board.Side) string {
+		if side == board.Sente {
+			return "+"
+		}
+		return "-"
+	}
+
+	switch s {
+	case ptypes.FinishedStatus_SUSPEND:
+		return "CHUDAN"
+	case ptypes.FinishedStatus_SURRENDER:
+		return "TORYO"
+	case ptypes.FinishedStatus_DRAW:
+		return "JISHOGI"
+	case ptypes.FinishedStatus_REPETITION_DRAW:
+		return "SENNICHITE"
+	case ptypes.FinishedStatus_CHECKMATE:
+		return "TSUMI"
+	case ptypes.FinishedStatus_OVER_TIME_LIMIT:
+		return "TIME_UP"
+	case ptypes.FinishedStatus_FOUL_WIN:
+		return sign(turn.Opponent()) + "ILLEGAL_ACTION"
+	case ptypes.FinishedStatus_FOUL_LOSS:
+		return sign(turn) + "ILLEGAL_ACTION"
+	case ptypes.FinishedStatus_NYUGYOKU_WIN:
+		return "KACHI"
+	default:
+		return ""
+	}
+}
+
+// csaTime returns the thinking time of the step in seconds, with the
+// milliseconds if it has.
+func csaTime(step *ptypes.Step) string {
+	d := ThinkingDuration(step).Truncate(time.Millisecond)
+	sec, ms := d/time.Second, d%time.Second/time.Millisecond
+	if ms == 0 {
+		return fmt.Sprintf("T%d", sec)
+	}
+	return strings.TrimRight(fmt.Sprintf("T%d.%03d", sec, ms), "0")
+}
+
+// writeCSA writes the main line in CSA format V2.2, or V3.0 if the thinking
+// times have fractions of seconds. Variations are not written.
+func (w *Writer) writeCSA(out io.Writer, k *ptypes.Kif) error {
+	p := &linePrinter{
+		newline: w.delimiter,
+		w:       w.encodingTransformer(out),
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return err
+	}
+
+	version := "V2.2"
+	for _, step := range k.Steps {
+		if strings.Contains(csaTime(step), ".") {
+			version = "V3.0"
+		}
+	}
+	if err := p.Print(version); err != nil {
+		return err
+	}
+	for _, h := range k.Headers {
+		var line string
+		if prefix, ok := csaHeaders[h.Name]; ok {
+			line = prefix + h.Value
+		} else if h.Name != "手合割" {
+			line = fmt.Sprintf("'%s:%s", h.Name, h.Value)
+		} else {
+			continue
+		}
+		if err := p.Print(line); err != nil {
+			return err
+		}
+	}
+	for _, line := range b.CSA() {
+		if err := p.Print(line); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range k.Steps {
+		var line string
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			if name := specialMove(step.FinishedStatus, b.Turn); name != "" {
+				line = "%" + name
+			}
+		} else {
+			line, err = b.CSAMove(step)
+			if err != nil {
+				return errors.Wrapf(err, "seq=%v", step.Seq)
+			}
+			if err := b.Apply(step); err != nil {
+				return errors.Wrapf(err, "seq=%v", step.Seq)
+			}
+		}
+		if line == "" {
+			break
+		}
+
+		if err := p.Print(line); err != nil {
+			return err
+		}
+		if err := p.Print(csaTime(step)); err != nil {
+			return err
+		}
+		for _, note := range step.Notes {
+			if err := p.Print("'*" + note); err != nil {
+				return err
+			}
+		}
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+	}
+
+	return nil
+}
+
+// csaStatements splits a line at the commas joining statements. Comments are not split.
+func csaStatements(line string) []string {
+	if strings.HasPrefix(line, "'") {
+		return []string{line}
+	}
+	return strings.Split(line, ",")
+}
+
+func csaHeaderName(prefix string) (string, bool) {
+	for name, p := range csaHeaders {
+		if p == prefix && name != "下手" && name != "上手" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ParseCSA reads a game in CSA format. The input is read as UTF-8 if it is valid
+// UTF-8, and as Shift_JIS otherwise. Only the initial positions of 手合割 are supported.
+func ParseCSA(in io.Reader) (*ptypes.Kif, error) {
+	bs, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(bs) {
+		bs, err = japanese.ShiftJIS.NewDecoder().Bytes(bs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	k := &ptypes.Kif{}
+	var pos []string
+	var b *board.Board
+	var last *ptypes.Step
+	start := func() error {
+		if b != nil {
+			return nil
+		}
+		var err error
+		b, err = board.FromCSA(pos)
+		if err != nil {
+			return err
+		}
+		handicap, ok := board.HandicapName(b)
+		if !ok {
+			return fmt.Errorf("unsupported initial position")
+		}
+		// 手合割 goes before the names of the players, who are 下手 and 上手 in handicap games.
+		i := -1
+		for j, h := range k.Headers {
+			if h.Name != "先手" && h.Name != "後手" {
+				continue
+			}
+			if i < 0 {
+				i = j
+			}
+			if handicap == "平手" {
+				continue
+			}
+			if h.Name == "先手" {
+				h.Name = "下手"
+			} else {
+				h.Name = "上手"
+			}
+		}
+		if i < 0 {
+			i = len(k.Headers)
+		}
+		h := &ptypes.Header{Name: "手合割", Value: handicap}
+		k.Headers = append(k.Headers[:i], append([]*ptypes.Header{h}, k.Headers[i:]...)...)
+		return nil
+	}
+
+	for n, line := range strings.Split(string(bs), "\n") {
+		line = strings.TrimRight(line, "\r")
+		for _, stmt := range csaStatements(line) {
+			var err error
+			switch {
+			case stmt == "" || stmt[0] == 'V':
+			case strings.HasPrefix(stmt, "'*"):
+				if last != nil {
+					last.Notes = append(last.Notes, stmt[2:])
+				}
+			case stmt[0] == '\'':
+				// headers not in CSA are written as comments before the position
+				if i := strings.Index(stmt, ":"); i > 1 && pos == nil && b == nil {
+					k.Headers = append(k.Headers, &ptypes.Header{Name: stmt[1:i], Value: stmt[i+1:]})
+				}
+			case strings.HasPrefix(stmt, "N+") || strings.HasPrefix(stmt, "N-"):
+				name, _ := csaHeaderName(stmt[:2])
+				k.Headers = append(k.Headers, &ptypes.Header{Name: name, Value: stmt[2:]})
+			case stmt[0] == '$':
+				i := strings.Index(stmt, ":")
+				if i < 0 {
+					return nil, fmt.Errorf("line %d: invalid information: %q", n+1, stmt)
+				}
+				name, ok := csaHeaderName(stmt[:i+1])
+				if !ok {
+					name = stmt[1:i]
+				}
+				k.Headers = append(k.Headers, &ptypes.Header{Name: name, Value: stmt[i+1:]})
+			case stmt[0] == 'P' || stmt == "+" || stmt == "-":
+				if b != nil {
+					return nil, fmt.Errorf("line %d: position after moves: %q", n+1, stmt)
+				}
+				pos = append(pos, stmt)
+			case stmt[0] == '+' || stmt[0] == '-':
+				if last != nil && last.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+					return nil, fmt.Errorf("line %d: move after the end: %q", n+1, stmt)
+				}
+				if err := start(); err != nil {
+					return nil, errors.Wrapf(err, "line %d", n+1)
+				}
+				last, err = b.StepFromCSA(stmt)
+				if err == nil {
+					err = b.Apply(last)
+				}
+				k.Steps = append(k.Steps, last)
+			case stmt[0] == 'T':
+				if last == nil {
+					return nil, fmt.Errorf("line %d: time without move: %q", n+1, stmt)
+				}
+				var sec float64
+				sec, err = strconv.ParseFloat(stmt[1:], 64)
+				SetThinkingDuration(last, time.Duration(sec*float64(time.Second)))
+			case stmt[0] == '%':
+				if err := start(); err != nil {
+					return nil, errors.Wrapf(err, "line %d", n+1)
+				}
+				s, ok := specialStatus(stmt[1:], b.Turn)
+				if !ok {
+					// other specials like %MATTA do not end the game
+					continue
+				}
+				last = &ptypes.Step{Seq: b.Ply + 1, FinishedStatus: s}
+				k.Steps = append(k.Steps, last)
+			default:
+				err = fmt.Errorf("unknown statement: %q", stmt)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", n+1)
+			}
+		}
+	}
+	if err := start(); err != nil {
+		return nil, err
+	}
+	RecomputeElapsed(k)
+
+	return k, nil
+}
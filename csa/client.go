@@ -0,0 +1,244 @@
+package csa
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+var (
+	ErrClosed         = errors.New("connection closed")
+	ErrLoginIncorrect = errors.New("login incorrect")
+	ErrRejected       = errors.New("game rejected")
+)
+
+type Client struct {
+	// Game is the current game, set by WaitGame.
+	Game *Game
+
+	logger func(send bool, line string)
+	now    func() time.Time
+
+	conn  net.Conn
+	lines chan string
+
+	special string
+	reason  string
+}
+
+type ClientOption func(*Client)
+
+// ClientLogger sets a function called with every line sent to and received from the server.
+func ClientLogger(f func(send bool, line string)) ClientOption {
+	return func(c *Client) {
+		c.logger = f
+	}
+}
+
+// Dial connects to a CSA server.
+func Dial(ctx context.Context, addr string, ops ...ClientOption) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn, ops...), nil
+}
+
+// NewClient returns a client communicating over the connection.
+func NewClient(conn net.Conn, ops ...ClientOption) *Client {
+	c := &Client{
+		logger: func(bool, string) {},
+		now:    time.Now,
+		conn:   conn,
+		lines:  make(chan string, 64),
+	}
+	for _, f := range ops {
+		f(c)
+	}
+
+	go func() {
+		defer close(c.lines)
+		s := bufio.NewScanner(conn)
+		for s.Scan() {
+			line := strings.TrimRight(s.Text(), "\r")
+			c.logger(false, line)
+			c.lines <- line
+		}
+	}()
+
+	return c
+}
+
+func (c *Client) send(format string, args ...interface{}) error {
+	line := fmt.Sprintf(format, args...)
+	c.logger(true, line)
+	_, err := io.WriteString(c.conn, line+"\n")
+	return err
+}
+
+func (c *Client) readLine(ctx context.Context) (string, error) {
+	select {
+	case line, ok := <-c.lines:
+		if !ok {
+			return "", ErrClosed
+		}
+		return line, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (c *Client) Login(ctx context.Context, name, password string) error {
+	if err := c.send("LOGIN %s %s", name, password); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return errors.Wrap(err, "login")
+		}
+		switch {
+		case line == "LOGIN:incorrect":
+			return ErrLoginIncorrect
+		case strings.HasPrefix(line, "LOGIN:") && strings.HasSuffix(line, " OK"):
+			return nil
+		}
+	}
+}
+
+// WaitGame waits for a Game_Summary and starts recording the game.
+func (c *Client) WaitGame(ctx context.Context) (*Game, error) {
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if line == "BEGIN Game_Summary" {
+			break
+		}
+	}
+
+	var lines []string
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if line == "END Game_Summary" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	s, err := parseSummary(lines)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewGame(s)
+	if err != nil {
+		return nil, err
+	}
+	c.Game = g
+	c.special, c.reason = "", ""
+
+	return g, nil
+}
+
+// Agree accepts the game and waits for the server to start it.
+func (c *Client) Agree(ctx context.Context) error {
+	id := c.Game.Summary.ID
+	if err := c.send("AGREE %s", id); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return errors.Wrap(err, "agree")
+		}
+		switch {
+		case line == "START:"+id:
+			kif.SetStartTime(c.Game.Kif, c.now())
+			return nil
+		case strings.HasPrefix(line, "REJECT:"+id):
+			return ErrRejected
+		}
+	}
+}
+
+func (c *Client) Reject() error {
+	return c.send("REJECT %s", c.Game.Summary.ID)
+}
+
+// Move sends a move in the current position. The game is updated
+// when the server sends it back with the time.
+func (c *Client) Move(step *ptypes.Step) error {
+	m, err := c.Game.Board.CSAMove(step)
+	if err != nil {
+		return err
+	}
+	return c.send("%s", m)
+}
+
+func (c *Client) Resign() error {
+	return c.send("%s", Resign)
+}
+
+// DeclareWin declares a win by entering king (入玉宣言).
+func (c *Client) DeclareWin() error {
+	return c.send("%s", Kachi)
+}
+
+type Event struct {
+	// Step is a move of either side, or nil when the game ended.
+	Step *ptypes.Step
+	// Result is set when the game ended.
+	Result string
+}
+
+// Next waits for the next move or the end of the game and records it.
+func (c *Client) Next(ctx context.Context) (*Event, error) {
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case line == "":
+			// keep alive
+		case isMove(line):
+			step, err := c.Game.Play(line)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Step: step}, nil
+		case strings.HasPrefix(line, "%"):
+			c.special = line
+		case line == ResultWin || line == ResultLose || line == ResultDraw || line == ResultCensored:
+			if err := c.Game.Finish(c.special, c.reason, line); err != nil {
+				return nil, err
+			}
+			kif.SetEndTime(c.Game.Kif, c.now())
+			return &Event{Result: line}, nil
+		case strings.HasPrefix(line, "#"):
+			c.reason = line
+		}
+	}
+}
+
+func (c *Client) Logout() error {
+	return c.send("LOGOUT")
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,206 @@
+package csa
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/clock"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// fakeServer serves one connection with a script. Lines starting with "<"
+// are expected from the client, and the others are sent.
+func fakeServer(t *testing.T, script []string) (string, chan error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for _, s := range script {
+			if !strings.HasPrefix(s, "<") {
+				fmt.Fprintf(conn, "%s\n", s)
+				continue
+			}
+			line, err := r.ReadString('\n')
+			if err != nil {
+				done <- err
+				return
+			}
+			if line = strings.TrimRight(line, "\n"); line != s[1:] {
+				done <- fmt.Errorf("expected=%q actual=%q", s[1:], line)
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	return l.Addr().String(), done
+}
+
+func testSummary() *Summary {
+	return &Summary{
+		ID:       "test-1",
+		Names:    [2]string{"alice", "bob"},
+		YourTurn: board.Sente,
+		ToMove:   board.Sente,
+		MaxMoves: 256,
+		TimeUnit: time.Second,
+		TimeControl: &clock.TimeControl{
+			Main:    10 * time.Minute,
+			Byoyomi: 10 * time.Second,
+		},
+		Position: board.New().CSA(),
+	}
+}
+
+func TestClient(t *testing.T) {
+	script := []string{"<LOGIN alice pw", "LOGIN:alice OK", "BEGIN Game_Summary"}
+	script = append(script, testSummary().Lines()...)
+	script = append(script,
+		"END Game_Summary",
+		"<AGREE test-1",
+		"START:test-1",
+		"<+7776FU",
+		"+7776FU,T5",
+		"",
+		"-3334FU,T3",
+		"<%TORYO",
+		"%TORYO,T2",
+		"#RESIGN",
+		"#LOSE",
+	)
+	addr, done := fakeServer(t, script)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Login(ctx, "alice", "pw"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	g, err := c.WaitGame(ctx)
+	if err != nil {
+		t.Fatalf("game summary: %v", err)
+	}
+	if g.Summary.Names[board.Gote] != "bob" || g.Summary.TimeControl.Byoyomi != 10*time.Second {
+		t.Errorf("unexpected summary: %+v", g.Summary)
+	}
+	if err := c.Agree(ctx); err != nil {
+		t.Fatalf("agree: %v", err)
+	}
+
+	step, err := g.Board.StepFromUSI("7g7f")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Move(step); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	for _, seq := range []int32{1, 2} {
+		e, err := c.Next(ctx)
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if e.Step == nil || e.Step.Seq != seq {
+			t.Fatalf("expected move %v: %+v", seq, e)
+		}
+	}
+	if err := c.Resign(); err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	e, err := c.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if e.Result != ResultLose || g.Reason != ReasonResign {
+		t.Errorf("unexpected result: %+v reason=%v", e, g.Reason)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+
+	k := g.Kif
+	if len(k.Steps) != 3 {
+		t.Fatalf("unexpected steps: %v", k.Steps)
+	}
+	if s := k.Steps[0]; s.ThinkingSec != 5 || s.Piece != ptypes.Piece_FU {
+		t.Errorf("unexpected first move: %v", s)
+	}
+	if s := k.Steps[2]; s.FinishedStatus != ptypes.FinishedStatus_SURRENDER || s.ThinkingSec != 2 || s.ElapsedSec != 7 {
+		t.Errorf("unexpected last step: %v", s)
+	}
+	headers := map[string]string{}
+	for _, h := range k.Headers {
+		headers[h.Name] = h.Value
+	}
+	if headers["先手"] != "alice" || headers["後手"] != "bob" || headers["手合割"] != "平手" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if headers["開始日時"] == "" || headers["終了日時"] == "" {
+		t.Errorf("missing time headers: %v", headers)
+	}
+}
+
+func TestClient_LoginIncorrect(t *testing.T) {
+	addr, done := fakeServer(t, []string{"<LOGIN alice bad", "LOGIN:incorrect"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Login(ctx, "alice", "bad"); err != ErrLoginIncorrect {
+		t.Errorf("expected=%v actual=%v", ErrLoginIncorrect, err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+}
+
+func TestFinishedStatus(t *testing.T) {
+	for _, c := range []struct {
+		reason     string
+		draw       bool
+		toMoveWins bool
+		declared   bool
+		expected   ptypes.FinishedStatus_Id
+	}{
+		{ReasonResign, false, false, false, ptypes.FinishedStatus_SURRENDER},
+		{ReasonTimeUp, false, false, false, ptypes.FinishedStatus_OVER_TIME_LIMIT},
+		{ReasonSennichite, true, false, false, ptypes.FinishedStatus_REPETITION_DRAW},
+		{ReasonOuteSennichite, false, true, false, ptypes.FinishedStatus_FOUL_WIN},
+		{ReasonIllegalMove, false, false, false, ptypes.FinishedStatus_FOUL_LOSS},
+		{ReasonJishogi, false, true, true, ptypes.FinishedStatus_NYUGYOKU_WIN},
+		{ReasonMaxMoves, true, false, false, ptypes.FinishedStatus_DRAW},
+	} {
+		if s := finishedStatus(c.reason, c.draw, c.toMoveWins, c.declared); s != c.expected {
+			t.Errorf("%v: expected=%v actual=%v", c.reason, c.expected, s)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package csa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+const (
+	Resign = "%TORYO"
+	Kachi  = "%KACHI"
+
+	ResultWin      = "#WIN"
+	ResultLose     = "#LOSE"
+	ResultDraw     = "#DRAW"
+	ResultCensored = "#CENSORED"
+
+	ReasonResign         = "#RESIGN"
+	ReasonTimeUp         = "#TIME_UP"
+	ReasonIllegalMove    = "#ILLEGAL_MOVE"
+	ReasonSennichite     = "#SENNICHITE"
+	ReasonOuteSennichite = "#OUTE_SENNICHITE"
+	ReasonJishogi        = "#JISHOGI"
+	ReasonMaxMoves       = "#MAX_MOVES"
+	ReasonChudan         = "#CHUDAN"
+)
+
+// Game is the record of a game, updated as the moves are played.
+type Game struct {
+	Summary *Summary
+	Board   *board.Board
+	Kif     *ptypes.Kif
+
+	// Result is one of ResultWin, ResultLose, ResultDraw or ResultCensored
+	// from the point of view of Summary.YourTurn, empty while playing.
+	Result string
+	// Reason is the event which ended the game, like ReasonResign.
+	Reason string
+}
+
+func addHeader(k *ptypes.Kif, name, value string) {
+	k.Headers = append(k.Headers, &ptypes.Header{Name: name, Value: value})
+}
+
+// NewGame starts recording a game from the summary, including the moves already played.
+func NewGame(s *Summary) (*Game, error) {
+	b, err := board.FromCSA(s.Position)
+	if err != nil {
+		return nil, err
+	}
+	handicap, ok := board.HandicapName(b)
+	if !ok {
+		return nil, fmt.Errorf("unsupported initial position")
+	}
+
+	k := &ptypes.Kif{}
+	addHeader(k, "手合割", handicap)
+	if handicap == "平手" {
+		addHeader(k, "先手", s.Names[board.Sente])
+		addHeader(k, "後手", s.Names[board.Gote])
+	} else {
+		addHeader(k, "下手", s.Names[board.Sente])
+		addHeader(k, "上手", s.Names[board.Gote])
+	}
+	if s.TimeControl != nil {
+		addHeader(k, "持ち時間", s.TimeControl.String())
+	}
+
+	g := &Game{
+		Summary: s,
+		Board:   b,
+		Kif:     k,
+	}
+	for _, m := range s.Moves {
+		if _, err := g.Play(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// parseTime parses the ",T12" suffix of a move.
+func (g *Game) parseTime(line string) (string, time.Duration, error) {
+	fields := strings.Split(line, ",")
+	var d time.Duration
+	for _, f := range fields[1:] {
+		if strings.HasPrefix(f, "T") {
+			n, err := strconv.Atoi(f[1:])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid time: %q", line)
+			}
+			d = time.Duration(n) * g.Summary.TimeUnit
+		}
+	}
+	return fields[0], d, nil
+}
+
+// Play records a move sent by the server, like "+7776FU,T12".
+// The move is validated with the board.
+func (g *Game) Play(line string) (*ptypes.Step, error) {
+	m, d, err := g.parseTime(line)
+	if err != nil {
+		return nil, err
+	}
+	step, err := g.Board.StepFromCSA(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Board.Validate(step); err != nil {
+		return nil, errors.Wrapf(err, "%v", m)
+	}
+	if err := g.Board.Apply(step); err != nil {
+		return nil, err
+	}
+	kif.SetThinkingDuration(step, d)
+	g.Kif.Steps = append(g.Kif.Steps, step)
+	kif.RecomputeElapsed(g.Kif)
+
+	return step, nil
+}
+
+// finishedStatus returns the status of the final step for the reason.
+// toMoveWins tells whether the side to move won.
+func finishedStatus(reason string, draw, toMoveWins, declared bool) ptypes.FinishedStatus_Id {
+	switch reason {
+	case ReasonResign:
+		return ptypes.FinishedStatus_SURRENDER
+	case ReasonTimeUp:
+		return ptypes.FinishedStatus_OVER_TIME_LIMIT
+	case ReasonSennichite:
+		return ptypes.FinishedStatus_REPETITION_DRAW
+	case ReasonChudan:
+		return ptypes.FinishedStatus_SUSPEND
+	case ReasonMaxMoves:
+		return ptypes.FinishedStatus_DRAW
+	case ReasonJishogi:
+		if !declared || draw {
+			return ptypes.FinishedStatus_DRAW
+		}
+		if toMoveWins {
+			return ptypes.FinishedStatus_NYUGYOKU_WIN
+		}
+		return ptypes.FinishedStatus_FOUL_LOSS
+	case ReasonIllegalMove, ReasonOuteSennichite:
+		if toMoveWins {
+			return ptypes.FinishedStatus_FOUL_WIN
+		}
+		return ptypes.FinishedStatus_FOUL_LOSS
+	default:
+		if draw {
+			return ptypes.FinishedStatus_DRAW
+		}
+		return ptypes.FinishedStatus_SUSPEND
+	}
+}
+
+// Finish records the end of the game. special is the special move
+// ("%TORYO,T3" or "%KACHI,T3") sent before the reason, if any.
+func (g *Game) Finish(special, reason, result string) error {
+	_, d, err := g.parseTime(special)
+	if err != nil {
+		return err
+	}
+	draw := result == ResultDraw || result == ResultCensored
+	toMoveWins := (g.Board.Turn == g.Summary.YourTurn) == (result == ResultWin)
+	declared := strings.HasPrefix(special, Kachi)
+
+	step := &ptypes.Step{
+		Seq:            g.Board.Ply + 1,
+		FinishedStatus: finishedStatus(reason, draw, toMoveWins, declared),
+	}
+	kif.SetThinkingDuration(step, d)
+	g.Kif.Steps = append(g.Kif.Steps, step)
+	kif.RecomputeElapsed(g.Kif)
+
+	g.Result = result
+	g.Reason = reason
+	return nil
+}
@@ -0,0 +1,435 @@
+package csa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/clock"
+)
+
+const ReasonAbnormal = "#ABNORMAL"
+
+// Server matches logged in clients in pairs and plays games between them.
+// Players return to the queue after each game until they log out.
+type Server struct {
+	handicap string
+	tc       *clock.TimeControl
+	maxMoves int
+	dir      string
+	logger   func(name string, send bool, line string)
+	onGame   func(*Game)
+	now      func() time.Time
+
+	mu      sync.Mutex
+	waiting *serverConn
+	games   int
+}
+
+type ServerOption func(*Server)
+
+// ServerHandicap sets the 手合割 of the games. The default is 平手.
+func ServerHandicap(name string) ServerOption {
+	return func(s *Server) {
+		s.handicap = name
+	}
+}
+
+// ServerTimeControl sets the time control. The default is 10 minutes and 10 seconds byoyomi.
+func ServerTimeControl(tc *clock.TimeControl) ServerOption {
+	return func(s *Server) {
+		s.tc = tc
+	}
+}
+
+// ServerMaxMoves sets the number of moves after which games are drawn. The default is 256.
+func ServerMaxMoves(n int) ServerOption {
+	return func(s *Server) {
+		s.maxMoves = n
+	}
+}
+
+// ServerDir sets the directory to store finished games in KIF and CSA formats.
+func ServerDir(dir string) ServerOption {
+	return func(s *Server) {
+		s.dir = dir
+	}
+}
+
+// ServerLogger sets a function called with every line sent to and received from clients.
+func ServerLogger(f func(name string, send bool, line string)) ServerOption {
+	return func(s *Server) {
+		s.logger = f
+	}
+}
+
+// ServerOnGame sets a function called with every finished game.
+func ServerOnGame(f func(*Game)) ServerOption {
+	return func(s *Server) {
+		s.onGame = f
+	}
+}
+
+func NewServer(ops ...ServerOption) *Server {
+	s := &Server{
+		handicap: "平手",
+		tc: &clock.TimeControl{
+			Main:    10 * time.Minute,
+			Byoyomi: 10 * time.Second,
+		},
+		maxMoves: 256,
+		logger:   func(string, bool, string) {},
+		onGame:   func(*Game) {},
+		now:      time.Now,
+	}
+	for _, f := range ops {
+		f(s)
+	}
+	return s
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return s.Serve(l)
+}
+
+// Serve accepts connections until the listener is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(nc)
+	}
+}
+
+type serverConn struct {
+	s      *Server
+	name   string
+	conn   net.Conn
+	lines  chan string
+	closed bool
+}
+
+func (c *serverConn) send(line string) {
+	c.s.logger(c.name, true, line)
+	io.WriteString(c.conn, line+"\n")
+}
+
+func (c *serverConn) close() {
+	c.closed = true
+	c.conn.Close()
+}
+
+func (s *Server) handle(nc net.Conn) {
+	c := &serverConn{
+		s:     s,
+		conn:  nc,
+		lines: make(chan string, 64),
+	}
+	go func() {
+		defer close(c.lines)
+		sc := bufio.NewScanner(nc)
+		for sc.Scan() {
+			line := strings.TrimRight(sc.Text(), "\r")
+			s.logger(c.name, false, line)
+			c.lines <- line
+		}
+	}()
+
+	for line := range c.lines {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "LOGIN" {
+			if line != "" {
+				c.send("LOGIN:incorrect")
+				c.close()
+				return
+			}
+			continue
+		}
+		c.name = fields[1]
+		c.send("LOGIN:" + c.name + " OK")
+		s.enqueue(c)
+		return
+	}
+	c.close()
+}
+
+// enqueue pairs the client with the waiting one, or makes it wait.
+func (s *Server) enqueue(c *serverConn) {
+	if c.closed {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.waiting == nil {
+		s.waiting = c
+		return
+	}
+	sente := s.waiting
+	s.waiting = nil
+	s.games++
+	id := fmt.Sprintf("%s-%d", s.now().Format("20060102150405"), s.games)
+	go s.play(id, sente, c)
+}
+
+// next waits for a line other than keep-alive. ok is false if the client disconnected or logged out.
+func (c *serverConn) next(timeout <-chan time.Time) (line string, ok, timedOut bool) {
+	for {
+		select {
+		case line, ok := <-c.lines:
+			switch {
+			case !ok:
+				c.close()
+				return "", false, false
+			case line == "LOGOUT":
+				c.send("LOGOUT:completed")
+				c.close()
+				return "", false, false
+			case line == "":
+				continue
+			}
+			return line, true, false
+		case <-timeout:
+			return "", true, true
+		}
+	}
+}
+
+func (c *serverConn) agree(id string) bool {
+	for {
+		line, ok, _ := c.next(nil)
+		switch {
+		case !ok:
+			return false
+		case line == "AGREE "+id || line == "AGREE":
+			return true
+		case strings.HasPrefix(line, "REJECT"):
+			return false
+		}
+	}
+}
+
+type serverGame struct {
+	s       *Server
+	players [2]*serverConn
+	game    *Game
+	clock   *clock.Clock
+	rep     *board.Repetition
+}
+
+func (g *serverGame) broadcast(line string) {
+	for _, c := range g.players {
+		if !c.closed {
+			c.send(line)
+		}
+	}
+}
+
+// end finishes the game. winner is ignored if draw is not empty.
+func (g *serverGame) end(special, reason string, winner board.Side, draw string) {
+	if special != "" {
+		g.broadcast(special)
+	}
+	g.broadcast(reason)
+
+	var results [2]string
+	for i := range results {
+		switch {
+		case draw != "":
+			results[i] = draw
+		case board.Side(i) == winner:
+			results[i] = ResultWin
+		default:
+			results[i] = ResultLose
+		}
+		if c := g.players[i]; !c.closed {
+			c.send(results[i])
+		}
+	}
+
+	g.game.Finish(special, reason, results[board.Sente])
+	kif.SetEndTime(g.game.Kif, g.s.now())
+	if err := g.s.save(g.game); err != nil {
+		g.s.logger("", false, err.Error())
+	}
+	g.s.onGame(g.game)
+
+	for _, c := range g.players {
+		g.s.enqueue(c)
+	}
+}
+
+func (s *Server) save(g *Game) error {
+	if s.dir == "" {
+		return nil
+	}
+	for _, f := range []struct {
+		ext    string
+		format kif.Format
+	}{
+		{".kif", kif.Format_KIF},
+		{".csa", kif.Format_CSA},
+	} {
+		out, err := os.Create(filepath.Join(s.dir, g.Summary.ID+f.ext))
+		if err != nil {
+			return err
+		}
+		err = kif.NewWriter(kif.SetFormat(f.format)).Write(out, g.Kif)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) play(id string, sente, gote *serverConn) {
+	b, err := board.NewHandicap(s.handicap)
+	if err != nil {
+		s.logger("", false, err.Error())
+		return
+	}
+
+	players := [2]*serverConn{sente, gote}
+	summary := &Summary{
+		ID:          id,
+		Names:       [2]string{sente.name, gote.name},
+		ToMove:      b.Turn,
+		MaxMoves:    s.maxMoves,
+		TimeUnit:    time.Second,
+		TimeControl: s.tc,
+		Position:    b.CSA(),
+	}
+	for i, c := range players {
+		summary.YourTurn = board.Side(i)
+		c.send("BEGIN Game_Summary")
+		for _, line := range summary.Lines() {
+			c.send(line)
+		}
+		c.send("END Game_Summary")
+	}
+	summary.YourTurn = board.Sente
+
+	for _, c := range players {
+		if c.agree(id) {
+			continue
+		}
+		for _, p := range players {
+			if !p.closed {
+				p.send(fmt.Sprintf("REJECT:%s by %s", id, c.name))
+			}
+		}
+		for _, p := range players {
+			s.enqueue(p)
+		}
+		return
+	}
+
+	game, err := NewGame(summary)
+	if err != nil {
+		s.logger("", false, err.Error())
+		return
+	}
+	kif.SetStartTime(game.Kif, s.now())
+	g := &serverGame{
+		s:       s,
+		players: players,
+		game:    game,
+		clock:   clock.New(s.tc),
+		rep:     board.NewRepetition(game.Board),
+	}
+	g.broadcast("START:" + id)
+	g.run()
+}
+
+func (g *serverGame) run() {
+	for {
+		b := g.game.Board
+		side := b.Turn
+		mover := g.players[side]
+
+		start := g.s.now()
+		// the time is counted in whole seconds, rounded down.
+		timer := time.NewTimer(g.clock.Available(side) + time.Second)
+		var m string
+		for {
+			line, ok, timedOut := mover.next(timer.C)
+			if !ok {
+				timer.Stop()
+				g.end("", ReasonAbnormal, side.Opponent(), "")
+				return
+			}
+			if timedOut {
+				g.end("", ReasonTimeUp, side.Opponent(), "")
+				return
+			}
+			// drop comments sent with the move, and ignore the other lines
+			// without charging the time
+			m = strings.SplitN(line, ",", 2)[0]
+			if m == Resign || m == Kachi || isMove(m) {
+				break
+			}
+		}
+		timer.Stop()
+		d := g.s.now().Sub(start).Truncate(time.Second)
+		t := fmt.Sprintf(",T%d", d/time.Second)
+
+		if !g.clock.Consume(side, d) {
+			g.end("", ReasonTimeUp, side.Opponent(), "")
+			return
+		}
+
+		switch m {
+		case Resign:
+			g.end(m+t, ReasonResign, side.Opponent(), "")
+			return
+		case Kachi:
+			if b.CanDeclareWin(side) {
+				g.end(m+t, ReasonJishogi, side, "")
+			} else {
+				g.end(m+t, ReasonIllegalMove, side.Opponent(), "")
+			}
+			return
+		}
+
+		if _, err := g.game.Play(m + t); err != nil {
+			g.end("", ReasonIllegalMove, side.Opponent(), "")
+			return
+		}
+		g.broadcast(m + t)
+
+		switch g.rep.Add(b) {
+		case board.Repetition_DRAW:
+			g.end("", ReasonSennichite, side, ResultDraw)
+			return
+		case board.Repetition_CHECK_BY_MOVER:
+			g.end("", ReasonOuteSennichite, side.Opponent(), "")
+			return
+		case board.Repetition_CHECK_BY_OPPONENT:
+			g.end("", ReasonOuteSennichite, side, "")
+			return
+		}
+		if g.s.maxMoves > 0 && int(b.Ply) >= g.s.maxMoves {
+			g.end("", ReasonMaxMoves, side, ResultCensored)
+			return
+		}
+	}
+}
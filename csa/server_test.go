@@ -0,0 +1,198 @@
+package csa
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func login(ctx context.Context, t *testing.T, addr, name string) *Client {
+	t.Helper()
+	c, err := Dial(ctx, addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	if err := c.Login(ctx, name, "pw"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return c
+}
+
+// startGame waits for the game on both clients and returns them ordered by turn.
+func startGame(ctx context.Context, t *testing.T, a, b *Client) [2]*Client {
+	t.Helper()
+	errs := make(chan error, 2)
+	for _, c := range []*Client{a, b} {
+		go func(c *Client) {
+			if _, err := c.WaitGame(ctx); err != nil {
+				errs <- err
+				return
+			}
+			errs <- c.Agree(ctx)
+		}(c)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("start: %v", err)
+		}
+	}
+
+	var ret [2]*Client
+	ret[a.Game.Summary.YourTurn] = a
+	ret[b.Game.Summary.YourTurn] = b
+	return ret
+}
+
+func move(ctx context.Context, t *testing.T, cs [2]*Client, usiMove string) {
+	t.Helper()
+	mover := cs[cs[0].Game.Board.Turn]
+	step, err := mover.Game.Board.StepFromUSI(usiMove)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mover.Move(step); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+}
+
+func next(ctx context.Context, t *testing.T, cs [2]*Client) [2]*Event {
+	t.Helper()
+	var ret [2]*Event
+	for i, c := range cs {
+		e, err := c.Next(ctx)
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		ret[i] = e
+	}
+	return ret
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csa")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	games := make(chan *Game, 2)
+	s := NewServer(ServerDir(dir), ServerOnGame(func(g *Game) { games <- g }))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	go s.Serve(l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	a := login(ctx, t, l.Addr().String(), "alice")
+	defer a.Close()
+	b := login(ctx, t, l.Addr().String(), "bob")
+	defer b.Close()
+
+	cs := startGame(ctx, t, a, b)
+	move(ctx, t, cs, "7g7f")
+	next(ctx, t, cs)
+	move(ctx, t, cs, "3c3d")
+	next(ctx, t, cs)
+	if err := cs[board.Sente].Resign(); err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	es := next(ctx, t, cs)
+	if es[board.Sente].Result != ResultLose || es[board.Gote].Result != ResultWin {
+		t.Errorf("unexpected results: %+v %+v", es[0], es[1])
+	}
+
+	g := <-games
+	if n := len(g.Kif.Steps); n != 3 || g.Kif.Steps[2].FinishedStatus != ptypes.FinishedStatus_SURRENDER {
+		t.Errorf("unexpected record: %v", g.Kif.Steps)
+	}
+	f, err := os.Open(filepath.Join(dir, g.Summary.ID+".kif"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k, err := kif.NewParser().Parse(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(k.Steps) != 3 {
+		t.Errorf("unexpected stored record: %v", k.Steps)
+	}
+	if _, err := os.Stat(filepath.Join(dir, g.Summary.ID+".csa")); err != nil {
+		t.Errorf("csa file: %v", err)
+	}
+
+	// the players are matched again, and an illegal move loses.
+	cs = startGame(ctx, t, a, b)
+	move(ctx, t, cs, "5g5e")
+	es = next(ctx, t, cs)
+	if es[board.Sente].Result != ResultLose || cs[board.Sente].Game.Reason != ReasonIllegalMove {
+		t.Errorf("unexpected result: %+v reason=%v", es[board.Sente], cs[board.Sente].Game.Reason)
+	}
+	g = <-games
+	if s := g.Kif.Steps[0].FinishedStatus; s != ptypes.FinishedStatus_FOUL_LOSS {
+		t.Errorf("expected=FOUL_LOSS actual=%v", s)
+	}
+}
+
+func TestServer_ignoredLines(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		offset time.Duration
+	)
+	base := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	setOffset := func(d time.Duration) {
+		mu.Lock()
+		offset = d
+		mu.Unlock()
+	}
+
+	s := NewServer()
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return base.Add(offset)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	go s.Serve(l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	a := login(ctx, t, l.Addr().String(), "alice")
+	defer a.Close()
+	b := login(ctx, t, l.Addr().String(), "bob")
+	defer b.Close()
+
+	cs := startGame(ctx, t, a, b)
+	time.Sleep(100 * time.Millisecond)
+
+	// a line which is not a move does not restart the time of the move
+	setOffset(5 * time.Second)
+	if err := cs[board.Sente].send("junk"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	setOffset(12 * time.Second)
+	move(ctx, t, cs, "7g7f")
+	es := next(ctx, t, cs)
+	if s := es[board.Gote].Step; s == nil || s.ThinkingSec != 12 {
+		t.Errorf("unexpected move: %v", s)
+	}
+}
@@ -0,0 +1,190 @@
+package csa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/clock"
+)
+
+// Summary is the Game_Summary sent by the server before a game.
+type Summary struct {
+	ID       string
+	Names    [2]string
+	YourTurn board.Side
+	ToMove   board.Side
+	MaxMoves int
+
+	TimeUnit         time.Duration
+	TimeControl      *clock.TimeControl
+	LeastTimePerMove time.Duration
+
+	// Position is the lines of the initial position, without the moves.
+	Position []string
+	// Moves are the moves already played, like "+7776FU,T12".
+	Moves []string
+}
+
+func parseTimeUnit(s string) (time.Duration, error) {
+	for _, u := range []struct {
+		suffix string
+		d      time.Duration
+	}{
+		{"msec", time.Millisecond},
+		{"sec", time.Second},
+		{"min", time.Minute},
+	} {
+		if strings.HasSuffix(s, u.suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix))
+			if err != nil {
+				return 0, fmt.Errorf("invalid time unit: %q", s)
+			}
+			return time.Duration(n) * u.d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time unit: %q", s)
+}
+
+func parseSide(s string) (board.Side, error) {
+	switch s {
+	case "+":
+		return board.Sente, nil
+	case "-":
+		return board.Gote, nil
+	default:
+		return board.Sente, fmt.Errorf("invalid turn: %q", s)
+	}
+}
+
+func isMove(line string) bool {
+	return len(line) >= 7 && (line[0] == '+' || line[0] == '-')
+}
+
+// parseSummary parses the lines between BEGIN Game_Summary and END Game_Summary.
+func parseSummary(lines []string) (*Summary, error) {
+	s := &Summary{
+		TimeUnit: time.Second,
+	}
+	var total, byoyomi, increment, least int
+	section := ""
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "BEGIN "):
+			section = strings.TrimPrefix(line, "BEGIN ")
+			continue
+		case strings.HasPrefix(line, "END "):
+			section = ""
+			continue
+		}
+
+		if section == "Position" {
+			if isMove(line) {
+				s.Moves = append(s.Moves, line)
+			} else {
+				s.Position = append(s.Position, line)
+			}
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		key, value := line[:i], line[i+1:]
+
+		var err error
+		switch key {
+		case "Game_ID":
+			s.ID = value
+		case "Name+":
+			s.Names[board.Sente] = value
+		case "Name-":
+			s.Names[board.Gote] = value
+		case "Your_Turn":
+			s.YourTurn, err = parseSide(value)
+		case "To_Move":
+			s.ToMove, err = parseSide(value)
+		case "Max_Moves":
+			s.MaxMoves, err = strconv.Atoi(value)
+		case "Time_Unit":
+			s.TimeUnit, err = parseTimeUnit(value)
+		case "Total_Time":
+			total, err = strconv.Atoi(value)
+		case "Byoyomi":
+			byoyomi, err = strconv.Atoi(value)
+		case "Increment":
+			increment, err = strconv.Atoi(value)
+		case "Least_Time_Per_Move":
+			least, err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %q", key, value)
+		}
+	}
+
+	s.TimeControl = &clock.TimeControl{
+		Main:      time.Duration(total) * s.TimeUnit,
+		Byoyomi:   time.Duration(byoyomi) * s.TimeUnit,
+		Increment: time.Duration(increment) * s.TimeUnit,
+	}
+	s.LeastTimePerMove = time.Duration(least) * s.TimeUnit
+
+	return s, nil
+}
+
+// Lines formats the summary as sent by the server, excluding BEGIN and END Game_Summary.
+func (s *Summary) Lines() []string {
+	sign := func(side board.Side) string {
+		if side == board.Sente {
+			return "+"
+		}
+		return "-"
+	}
+	unit := func(d time.Duration) int64 {
+		if s.TimeUnit == 0 {
+			return int64(d / time.Second)
+		}
+		return int64(d / s.TimeUnit)
+	}
+
+	ret := []string{
+		"Protocol_Version:1.2",
+		"Protocol_Mode:Server",
+		"Format:Shogi 1.0",
+		"Declaration:Jishogi 1.1",
+		"Game_ID:" + s.ID,
+		"Name+:" + s.Names[board.Sente],
+		"Name-:" + s.Names[board.Gote],
+		"Your_Turn:" + sign(s.YourTurn),
+		"Rematch_On_Draw:NO",
+		"To_Move:" + sign(s.ToMove),
+		fmt.Sprintf("Max_Moves:%d", s.MaxMoves),
+		"BEGIN Time",
+	}
+	switch s.TimeUnit {
+	case 0, time.Second:
+		ret = append(ret, "Time_Unit:1sec")
+	case time.Minute:
+		ret = append(ret, "Time_Unit:1min")
+	default:
+		ret = append(ret, fmt.Sprintf("Time_Unit:%dmsec", s.TimeUnit/time.Millisecond))
+	}
+	if tc := s.TimeControl; tc != nil {
+		ret = append(ret, fmt.Sprintf("Total_Time:%d", unit(tc.Main)))
+		if tc.Increment != 0 {
+			ret = append(ret, fmt.Sprintf("Increment:%d", unit(tc.Increment)))
+		} else {
+			ret = append(ret, fmt.Sprintf("Byoyomi:%d", unit(tc.Byoyomi)))
+		}
+	}
+	if s.LeastTimePerMove != 0 {
+		ret = append(ret, fmt.Sprintf("Least_Time_Per_Move:%d", unit(s.LeastTimePerMove)))
+	}
+	ret = append(ret, "END Time", "BEGIN Position")
+	ret = append(ret, s.Position...)
+	ret = append(ret, s.Moves...)
+	return append(ret, "END Position")
+}
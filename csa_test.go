@@ -0,0 +1,131 @@
+package kif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestWriteCSA(t *testing.T) {
+	k := &ptypes.Kif{
+		Headers: []*ptypes.Header{
+			{Name: "先手", Value: "alice"},
+			{Name: "後手", Value: "bob"},
+			{Name: "持ち時間", Value: "10分"},
+		},
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}, ThinkingSec: 5},
+			{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}, Notes: []string{"comment"}},
+			{Seq: 3, Dst: &ptypes.Pos{X: 2, Y: 2}, Piece: ptypes.Piece_KAKU, Modifier: ptypes.Modifier_PROMOTE, Src: &ptypes.Pos{X: 8, Y: 8}},
+			{Seq: 4, Piece: ptypes.Piece_GIN, Src: &ptypes.Pos{X: 3, Y: 1}},
+			{Seq: 5, FinishedStatus: ptypes.FinishedStatus_SURRENDER, ThinkingSec: 2},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(SetFormat(Format_CSA), WriteEncodingUTF8())
+	if err := w.Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"V2.2",
+		"N+alice",
+		"N-bob",
+		"'持ち時間:10分",
+		"P1-KY-KE-GI-KI-OU-KI-GI-KE-KY",
+		"P2 * -HI *  *  *  *  * -KA * ",
+		"P3-FU-FU-FU-FU-FU-FU-FU-FU-FU",
+		"P4 *  *  *  *  *  *  *  *  * ",
+		"P5 *  *  *  *  *  *  *  *  * ",
+		"P6 *  *  *  *  *  *  *  *  * ",
+		"P7+FU+FU+FU+FU+FU+FU+FU+FU+FU",
+		"P8 * +KA *  *  *  *  * +HI * ",
+		"P9+KY+KE+GI+KI+OU+KI+GI+KE+KY",
+		"P+",
+		"P-",
+		"+",
+		"+7776FU",
+		"T5",
+		"-3334FU",
+		"T0",
+		"'*comment",
+		"+8822UM",
+		"T0",
+		"-3122GI",
+		"T0",
+		"%TORYO",
+		"T2",
+		"",
+	}, "\n")
+	if a := buf.String(); a != expected {
+		t.Errorf("expected:\n%v\nactual:\n%v", expected, a)
+	}
+}
+
+func TestParseCSA(t *testing.T) {
+	in := strings.Join([]string{
+		"V2.2",
+		"N+alice",
+		"N-bob",
+		"$EVENT:test",
+		"'持ち時間:10分",
+		"PI82HI22KA",
+		"-",
+		"-3334FU,T3",
+		"+7776FU",
+		"T5.5",
+		"'*comment",
+		"-5142OU",
+		"T1",
+		"%TORYO",
+		"T2",
+		"",
+	}, "\r\n")
+	k, err := ParseCSA(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var headers []string
+	for _, h := range k.Headers {
+		headers = append(headers, h.Name+":"+h.Value)
+	}
+	if exp, a := "手合割:二枚落ち,下手:alice,上手:bob,棋戦:test,持ち時間:10分", strings.Join(headers, ","); a != exp {
+		t.Errorf("headers: expected=%v actual=%v", exp, a)
+	}
+
+	if l := len(k.Steps); l != 4 {
+		t.Fatalf("steps: %v", k.Steps)
+	}
+	if s := k.Steps[1]; s.ThinkingSec != 5 || s.ThinkingNanos != 5e8 || len(s.Notes) != 1 {
+		t.Errorf("unexpected step: %v", s)
+	}
+	if s := k.Steps[2]; s.Piece != ptypes.Piece_GYOKU || s.Src.X != 5 {
+		t.Errorf("unexpected step: %v", s)
+	}
+	if s := k.Steps[3]; s.Seq != 4 || s.FinishedStatus != ptypes.FinishedStatus_SURRENDER || s.ElapsedSec != 7 {
+		t.Errorf("unexpected step: %v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(SetFormat(Format_CSA), WriteEncodingUTF8()).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "V3.0\n") || !strings.Contains(out, "\nT5.5\n") {
+		t.Errorf("fractional time:\n%s", out)
+	}
+	k2, err := ParseCSA(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+	if len(k2.Steps) != 4 || len(k2.Headers) != len(k.Headers) {
+		t.Errorf("round trip mismatch: %v", k2)
+	}
+	if d := ThinkingDuration(k2.Steps[1]); d != 5500*time.Millisecond {
+		t.Errorf("thinking time: %v", d)
+	}
+}
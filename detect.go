@@ -0,0 +1,222 @@
+package kif
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/text/encoding/japanese"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+type InputFormat int
+
+const (
+	InputFormat_UNKNOWN InputFormat = iota
+	InputFormat_KIF
+	InputFormat_KI2
+	InputFormat_CSA
+	InputFormat_JKF
+	InputFormat_SFEN
+	// ptypes.Kif in the protobuf JSON mapping
+	InputFormat_JSON
+	// ptypes.Kif in the protobuf wire format
+	InputFormat_PROTO
+)
+
+var inputFormatNames = []string{
+	"unknown",
+	"kif",
+	"ki2",
+	"csa",
+	"jkf",
+	"sfen",
+	"json",
+	"pb",
+}
+
+func (f InputFormat) String() string {
+	if f < 0 || int(f) >= len(inputFormatNames) {
+		return fmt.Sprintf("InputFormat(%d)", int(f))
+	}
+	return inputFormatNames[f]
+}
+
+var extFormats = map[string]InputFormat{
+	".kif":  InputFormat_KIF,
+	".kifu": InputFormat_KIF,
+	".ki2":  InputFormat_KI2,
+	".ki2u": InputFormat_KI2,
+	".csa":  InputFormat_CSA,
+	".jkf":  InputFormat_JKF,
+	".sfen": InputFormat_SFEN,
+	".usi":  InputFormat_SFEN,
+	".json": InputFormat_JSON,
+	".pb":   InputFormat_PROTO,
+}
+
+var (
+	csaMoveLine  = regexp.MustCompile(`^[+-]\d{4}[A-Z]{2}`)
+	kifMoveLine  = regexp.MustCompile(`^\s*\d+\s+\S`)
+	sfenPosition = regexp.MustCompile(`^(position\s+)?(startpos|sfen\s)|^[1-9+A-Za-z]+(/[1-9+A-Za-z]+){8}\s+[bw]\s`)
+)
+
+// decodeText returns the text of a game in UTF-8 or Shift_JIS. The end of the
+// input may be a truncated character.
+func decodeText(bs []byte) string {
+	bs = bytes.TrimPrefix(bs, []byte("\xEF\xBB\xBF"))
+	for i := 0; i < utf8.UTFMax && i <= len(bs); i++ {
+		if utf8.Valid(bs[:len(bs)-i]) {
+			return string(bs[:len(bs)-i])
+		}
+	}
+	s, _ := japanese.ShiftJIS.NewDecoder().Bytes(bs)
+	return string(s)
+}
+
+func isBinary(bs []byte) bool {
+	for _, b := range bs {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContent returns the format found in the beginning of the input, and
+// whether it is conclusive.
+func detectContent(head []byte) (InputFormat, bool) {
+	// the field tags of headers and steps
+	if len(head) != 0 && (head[0] == 0x0a || head[0] == 0x12) && isBinary(head) {
+		return InputFormat_PROTO, true
+	}
+
+	text := strings.TrimSpace(decodeText(head))
+	if strings.HasPrefix(text, "{") {
+		if strings.Contains(text, `"headers"`) || strings.Contains(text, `"steps"`) {
+			return InputFormat_JSON, true
+		}
+		return InputFormat_JKF, true
+	}
+
+	headers := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case line == "" || line[0] == '#' || line[0] == '*':
+		case strings.HasPrefix(line, "手数----指手"):
+			return InputFormat_KIF, true
+		case strings.HasPrefix(line, "V2") || strings.HasPrefix(line, "V3") || strings.HasPrefix(line, "PI") ||
+			strings.HasPrefix(line, "P1") || csaMoveLine.MatchString(line):
+			return InputFormat_CSA, true
+		case sfenPosition.MatchString(line):
+			return InputFormat_SFEN, true
+		case strings.HasPrefix(line, "▲") || strings.HasPrefix(line, "△") ||
+			strings.HasPrefix(line, "☗") || strings.HasPrefix(line, "☖"):
+			return InputFormat_KI2, true
+		case kifMoveLine.MatchString(line):
+			return InputFormat_KIF, true
+		case strings.Contains(line, "："):
+			headers = true
+		}
+	}
+	if headers {
+		return InputFormat_KIF, false
+	}
+	return InputFormat_UNKNOWN, false
+}
+
+// Detect returns the format of a game from the beginning of the content and the
+// file name, which is used when the content is not conclusive. name may be empty.
+func Detect(head []byte, name string) InputFormat {
+	f, ok := detectContent(head)
+	if ok {
+		return f
+	}
+	if ext, found := extFormats[strings.ToLower(filepath.Ext(name))]; found {
+		return ext
+	}
+	return f
+}
+
+type anyReader struct {
+	name   string
+	format InputFormat
+}
+
+type ReadOption func(*anyReader)
+
+// ReadFilename sets the file name used to detect the format.
+func ReadFilename(name string) ReadOption {
+	return func(r *anyReader) {
+		r.name = name
+	}
+}
+
+// ReadFormat sets the format instead of detecting it.
+func ReadFormat(format InputFormat) ReadOption {
+	return func(r *anyReader) {
+		r.format = format
+	}
+}
+
+// ReadAny reads a game in any supported format. The format is detected from
+// the content and the file name, and text is read as UTF-8 if it is valid
+// UTF-8, and as Shift_JIS otherwise.
+func ReadAny(in io.Reader, ops ...ReadOption) (*ptypes.Kif, error) {
+	r := &anyReader{}
+	for _, f := range ops {
+		f(r)
+	}
+
+	bs, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	format := r.format
+	if format == InputFormat_UNKNOWN {
+		format = Detect(bs, r.name)
+	}
+
+	parser := NewParser()
+	if utf8.Valid(bs) {
+		parser = NewParser(ParseEncodingUTF8())
+	}
+
+	switch format {
+	case InputFormat_KIF:
+		return parser.Parse(bytes.NewReader(bs))
+	case InputFormat_KI2:
+		return parser.ParseKI2(bytes.NewReader(bs))
+	case InputFormat_CSA:
+		return ParseCSA(bytes.NewReader(bs))
+	case InputFormat_JKF:
+		return ParseJKF(bytes.NewReader(bs))
+	case InputFormat_SFEN:
+		return ParseSFEN(bytes.NewReader(bs))
+	case InputFormat_JSON:
+		k := &ptypes.Kif{}
+		u := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+		if err := u.Unmarshal(bytes.NewReader(bs), k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	case InputFormat_PROTO:
+		k := &ptypes.Kif{}
+		if err := proto.Unmarshal(bs, k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unknown format")
+	}
+}
@@ -0,0 +1,82 @@
+package kif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/text/encoding/japanese"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestDetect(t *testing.T) {
+	sjisKIF, _ := japanese.ShiftJIS.NewEncoder().String(variationKIF)
+	pb, _ := proto.Marshal(&ptypes.Kif{
+		Headers: []*ptypes.Header{{Name: "手合割", Value: "平手"}},
+	})
+
+	for _, c := range []struct {
+		head, name string
+		expected   InputFormat
+	}{
+		{variationKIF, "", InputFormat_KIF},
+		{sjisKIF[:50], "", InputFormat_KIF},
+		{"\xEF\xBB\xBF" + variationKIF, "a.ki2", InputFormat_KIF},
+		{testKI2, "", InputFormat_KI2},
+		{"先手：A\n後手：B\n", "a.ki2", InputFormat_KI2},
+		{"先手：A\n後手：B\n", "", InputFormat_KIF},
+		{"V2.2\nN+A\n", "", InputFormat_CSA},
+		{"'comment\nPI\n+\n+7776FU\n", "", InputFormat_CSA},
+		{`{"header":{},"moves":[{}]}`, "", InputFormat_JKF},
+		{`{"headers":[],"steps":[]}`, "", InputFormat_JSON},
+		{string(pb), "", InputFormat_PROTO},
+		{"position startpos moves 7g7f", "", InputFormat_SFEN},
+		{"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1\n", "", InputFormat_SFEN},
+		{"", "a.CSA", InputFormat_CSA},
+		{"hello", "", InputFormat_UNKNOWN},
+	} {
+		if a := Detect([]byte(c.head), c.name); a != c.expected {
+			t.Errorf("%q %q: expected=%v actual=%v", c.head, c.name, c.expected, a)
+		}
+	}
+}
+
+func TestReadAny(t *testing.T) {
+	var jkf, csa bytes.Buffer
+	k, err := ReadAny(strings.NewReader(variationKIF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewWriter(SetFormat(Format_JKF)).Write(&jkf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewWriter(SetFormat(Format_CSA)).Write(&csa, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sjisKI2, _ := japanese.ShiftJIS.NewEncoder().String(testKI2)
+
+	for _, c := range []struct {
+		in    string
+		steps int
+	}{
+		{jkf.String(), 4},
+		{csa.String(), 4},
+		{sjisKI2, 8},
+		{"position startpos moves 7g7f 3c3d", 2},
+	} {
+		k, err := ReadAny(strings.NewReader(c.in))
+		if err != nil {
+			t.Errorf("unexpected error: %v\n%s", err, c.in)
+			continue
+		}
+		if l := len(k.Steps); l != c.steps {
+			t.Errorf("steps: expected=%v actual=%v\n%s", c.steps, l, c.in)
+		}
+	}
+
+	if _, err := ReadAny(strings.NewReader("hello")); err == nil {
+		t.Errorf("expected error")
+	}
+}
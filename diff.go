@@ -0,0 +1,232 @@
+package kif
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type HeaderChange struct {
+	Name string `json:"name"`
+	// Old is empty for added headers, and New for removed headers.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Divergence is the first step where the main lines differ. A move is empty
+// if the line has ended. If the initial positions differ, Seq is 0 and the
+// moves are the SFEN of the positions.
+type Divergence struct {
+	Seq int32  `json:"seq"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+type TimeChange struct {
+	Seq int32 `json:"seq"`
+	// Old and New are the thinking times in seconds.
+	Old float64 `json:"old"`
+	New float64 `json:"new"`
+}
+
+type CommentChange struct {
+	Seq     int32    `json:"seq"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Difference is the changes from a game to another. The times and comments
+// are compared on the main lines until they diverge.
+type Difference struct {
+	Headers    []*HeaderChange  `json:"headers,omitempty"`
+	Divergence *Divergence      `json:"divergence,omitempty"`
+	Times      []*TimeChange    `json:"times,omitempty"`
+	Comments   []*CommentChange `json:"comments,omitempty"`
+}
+
+func (d *Difference) Empty() bool {
+	return len(d.Headers) == 0 && d.Divergence == nil && len(d.Times) == 0 && len(d.Comments) == 0
+}
+
+func diffHeaders(a, b []*ptypes.Header) []*HeaderChange {
+	values := func(hs []*ptypes.Header) ([]string, map[string]string) {
+		var names []string
+		m := make(map[string]string)
+		for _, h := range hs {
+			if _, ok := m[h.Name]; !ok {
+				names = append(names, h.Name)
+				m[h.Name] = h.Value
+			}
+		}
+		return names, m
+	}
+	namesA, va := values(a)
+	namesB, vb := values(b)
+
+	var ret []*HeaderChange
+	for _, name := range namesA {
+		if nv, ok := vb[name]; !ok || nv != va[name] {
+			ret = append(ret, &HeaderChange{Name: name, Old: va[name], New: nv})
+		}
+	}
+	for _, name := range namesB {
+		if _, ok := va[name]; !ok {
+			ret = append(ret, &HeaderChange{Name: name, New: vb[name]})
+		}
+	}
+	return ret
+}
+
+// diffStrings returns the strings only in b and only in a.
+func diffStrings(a, b []string) ([]string, []string) {
+	in := func(ss []string, s string) bool {
+		for _, x := range ss {
+			if x == s {
+				return true
+			}
+		}
+		return false
+	}
+	var added, removed []string
+	for _, s := range b {
+		if !in(a, s) {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !in(b, s) {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func (d *Difference) diffStep(a, b *ptypes.Step) {
+	if ta, tb := ThinkingDuration(a), ThinkingDuration(b); ta != tb {
+		d.Times = append(d.Times, &TimeChange{Seq: a.Seq, Old: ta.Seconds(), New: tb.Seconds()})
+	}
+	if added, removed := diffStrings(a.Notes, b.Notes); len(added) != 0 || len(removed) != 0 {
+		d.Comments = append(d.Comments, &CommentChange{Seq: a.Seq, Added: added, Removed: removed})
+	}
+}
+
+// Diff compares the games a and b.
+func Diff(a, b *ptypes.Kif) (*Difference, error) {
+	ret := &Difference{Headers: diffHeaders(a.Headers, b.Headers)}
+
+	posA, err := board.FromKif(a)
+	if err != nil {
+		return nil, err
+	}
+	posB, err := board.FromKif(b)
+	if err != nil {
+		return nil, err
+	}
+	if posA.Key() != posB.Key() {
+		ret.Divergence = &Divergence{Seq: 0, Old: posA.SFEN(), New: posB.SFEN()}
+		return ret, nil
+	}
+
+	as, bs := a.Steps, b.Steps
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		if i >= len(as) || i >= len(bs) {
+			div := &Divergence{}
+			if i < len(as) {
+				div.Seq, div.Old = as[i].Seq, PrintMove(as[i])
+			} else {
+				div.Seq, div.New = bs[i].Seq, PrintMove(bs[i])
+			}
+			ret.Divergence = div
+			break
+		}
+
+		sa, sb := as[i], bs[i]
+		same := sa.Seq == sb.Seq && sa.FinishedStatus == sb.FinishedStatus
+		finished := sa.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED
+		if same && !finished {
+			ma, err := move(posA, sa)
+			if err != nil {
+				return nil, err
+			}
+			mb, err := move(posA, sb)
+			if err != nil {
+				return nil, err
+			}
+			same = sameMove(ma, mb)
+		}
+		if !same {
+			ret.Divergence = &Divergence{Seq: sa.Seq, Old: PrintMove(sa), New: PrintMove(sb)}
+			break
+		}
+
+		ret.diffStep(sa, sb)
+		if finished {
+			break
+		}
+		if err := posA.Apply(sa); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func printSeconds(sec float64) string {
+	return strings.TrimSpace(PrintThinking(int32(sec)))
+}
+
+// WriteText writes the difference in lines for humans: the headers, and the
+// changes of the steps in order.
+func (d *Difference) WriteText(w io.Writer) error {
+	var lines []string
+	for _, h := range d.Headers {
+		if h.Old != "" {
+			lines = append(lines, fmt.Sprintf("-%s：%s", h.Name, h.Old))
+		}
+		if h.New != "" {
+			lines = append(lines, fmt.Sprintf("+%s：%s", h.Name, h.New))
+		}
+	}
+
+	type stepLine struct {
+		seq  int32
+		line string
+	}
+	var steps []*stepLine
+	for _, t := range d.Times {
+		steps = append(steps, &stepLine{t.Seq, fmt.Sprintf("time %s -> %s", printSeconds(t.Old), printSeconds(t.New))})
+	}
+	for _, c := range d.Comments {
+		for _, s := range c.Removed {
+			steps = append(steps, &stepLine{c.Seq, "-*" + s})
+		}
+		for _, s := range c.Added {
+			steps = append(steps, &stepLine{c.Seq, "+*" + s})
+		}
+	}
+	if v := d.Divergence; v != nil {
+		from, to := v.Old, v.New
+		if from == "" {
+			from = "(end)"
+		}
+		if to == "" {
+			to = "(end)"
+		}
+		steps = append(steps, &stepLine{v.Seq, fmt.Sprintf("diverged %s -> %s", from, to)})
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].seq < steps[j].seq })
+	for _, s := range steps {
+		lines = append(lines, fmt.Sprintf("%d: %s", s.seq, s.line))
+	}
+
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
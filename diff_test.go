@@ -0,0 +1,78 @@
+package kif
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := parseUTF8(t, `先手：A
+後手：B
+場所：東京
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:10/00:00:10)
+*old
+   2 ３四歩(33)   ( 0:20/00:00:20)
+   3 ２六歩(27)   ( 0:05/00:00:15)
+   4 投了   ( 0:00/00:00:20)
+`)
+	b := parseUTF8(t, `先手：A
+後手：C
+棋戦：test
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:10/00:00:10)
+*new
+   2 ３四歩(33)   ( 0:25/00:00:25)
+   3 ２六歩(27)   ( 0:05/00:00:15)
+   4 中断   ( 0:00/00:00:25)
+`)
+
+	d, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `-後手：B
++後手：C
+-場所：東京
++棋戦：test
+1: -*old
+1: +*new
+2: time 0:20 -> 0:25
+4: diverged △投了 -> △中断
+`
+	if s := buf.String(); s != expected {
+		t.Errorf("expected=%v\nactual=%v", expected, s)
+	}
+
+	if d.Divergence.Seq != 4 || len(d.Times) != 1 || d.Times[0].New != 25 {
+		t.Errorf("unexpected difference: %+v", d)
+	}
+
+	same, err := Diff(a, a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !same.Empty() {
+		t.Errorf("expected no difference: %+v", same)
+	}
+}
+
+func TestDiff_Truncated(t *testing.T) {
+	a := parseUTF8(t, variationKIF)
+	b := parseUTF8(t, `手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ３四歩(33)   ( 0:00/00:00:00)
+`)
+	d, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := d.Divergence; v == nil || v.Seq != 3 || v.Old == "" || v.New != "" {
+		t.Errorf("divergence: %+v", v)
+	}
+}
@@ -0,0 +1,265 @@
+package kif
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// The editing functions change the main line of a game in place, keeping
+// the invariants of a record: the steps are numbered contiguously, a
+// finishing step is only at the end of a line, and the destination of a move
+// is omitted (同) exactly when it is the destination of the previous move.
+// All the lines are validated against the board, and k is not modified on
+// errors.
+
+func isFinished(s *ptypes.Step) bool {
+	return s.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED
+}
+
+// resolveLine fills the destinations of 同 moves, so that the moves keep
+// their squares when the previous moves are edited.
+func resolveLine(b *board.Board, steps []*ptypes.Step) error {
+	for _, s := range steps {
+		for _, v := range s.Variations {
+			if err := resolveLine(b.Clone(), v.Steps); err != nil {
+				return err
+			}
+		}
+		if isFinished(s) {
+			return nil
+		}
+		if err := b.Apply(s); err != nil {
+			return errors.Wrapf(err, "seq=%v", s.Seq)
+		}
+		s.Dst = proto.Clone(b.Last.Dst).(*ptypes.Pos)
+	}
+	return nil
+}
+
+// normalizeLine renumbers and validates the steps, and omits the
+// destinations of 同 moves.
+func normalizeLine(b *board.Board, steps []*ptypes.Step) error {
+	for i, s := range steps {
+		s.Seq = int32(b.Ply) + 1
+		for _, v := range s.Variations {
+			if len(v.Steps) == 0 {
+				return fmt.Errorf("seq=%v: empty variation", s.Seq)
+			}
+			if err := normalizeLine(b.Clone(), v.Steps); err != nil {
+				return err
+			}
+		}
+		if isFinished(s) {
+			if i != len(steps)-1 {
+				return fmt.Errorf("seq=%v: %v is not at the end", s.Seq, s.FinishedStatus)
+			}
+			return nil
+		}
+
+		if err := b.Validate(s); err != nil {
+			return errors.Wrapf(err, "seq=%v", s.Seq)
+		}
+		var prev *ptypes.Pos
+		if b.Last != nil {
+			prev = b.Last.Dst
+		}
+		if err := b.Apply(s); err != nil {
+			return errors.Wrapf(err, "seq=%v", s.Seq)
+		}
+		if prev != nil && proto.Equal(prev, b.Last.Dst) {
+			s.Dst = nil
+		} else {
+			s.Dst = proto.Clone(b.Last.Dst).(*ptypes.Pos)
+		}
+	}
+	return nil
+}
+
+// edit applies f to the main line of a copy of k with the destinations
+// resolved, and replaces the steps of k with the normalized result. b is the
+// initial position.
+func edit(k *ptypes.Kif, f func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error)) error {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return err
+	}
+	steps := cloneSteps(k.Steps)
+	if err := resolveLine(b.Clone(), steps); err != nil {
+		return err
+	}
+	steps, err = f(b, steps)
+	if err != nil {
+		return err
+	}
+	if err := normalizeLine(b.Clone(), steps); err != nil {
+		return err
+	}
+	k.Steps = steps
+	return nil
+}
+
+// findStep returns the index of the main line step seq, and the position
+// before it.
+func findStep(b *board.Board, steps []*ptypes.Step, seq int32) (int, *board.Board, error) {
+	b = b.Clone()
+	for i, s := range steps {
+		if s.Seq == seq {
+			return i, b, nil
+		}
+		if isFinished(s) {
+			break
+		}
+		if err := b.Apply(s); err != nil {
+			return 0, nil, errors.Wrapf(err, "seq=%v", s.Seq)
+		}
+	}
+	return 0, nil, fmt.Errorf("no step: seq=%v", seq)
+}
+
+// TruncateAt removes the step seq and the following steps from the main line.
+func TruncateAt(k *ptypes.Kif, seq int32) error {
+	return edit(k, func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error) {
+		i, _, err := findStep(b, steps, seq)
+		if err != nil {
+			return nil, err
+		}
+		return steps[:i], nil
+	})
+}
+
+// ReplaceMove replaces the move seq of the main line with step, keeping its
+// variations. The following steps must be still legal.
+func ReplaceMove(k *ptypes.Kif, seq int32, step *ptypes.Step) error {
+	if isFinished(step) {
+		return fmt.Errorf("not a move: %v", step.FinishedStatus)
+	}
+	return edit(k, func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error) {
+		i, _, err := findStep(b, steps, seq)
+		if err != nil {
+			return nil, err
+		}
+		old := steps[i]
+		if isFinished(old) {
+			return nil, fmt.Errorf("seq=%v: not a move: %v", seq, old.FinishedStatus)
+		}
+		s := proto.Clone(step).(*ptypes.Step)
+		s.Variations = old.Variations
+		steps[i] = s
+		return steps, nil
+	})
+}
+
+// InsertVariation adds the line of steps as an alternative to the step seq
+// of the main line.
+func InsertVariation(k *ptypes.Kif, seq int32, line []*ptypes.Step) error {
+	if len(line) == 0 {
+		return fmt.Errorf("empty variation")
+	}
+	return edit(k, func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error) {
+		i, pos, err := findStep(b, steps, seq)
+		if err != nil {
+			return nil, err
+		}
+
+		line := cloneSteps(line)
+		first := line[0]
+		alts := []*ptypes.Step{steps[i]}
+		for _, v := range steps[i].Variations {
+			alts = append(alts, v.Steps[0])
+		}
+		for _, a := range alts {
+			if isFinished(a) || isFinished(first) {
+				if a.FinishedStatus == first.FinishedStatus {
+					return nil, fmt.Errorf("seq=%v: the step exists", seq)
+				}
+				continue
+			}
+			ma, err := move(pos, a)
+			if err != nil {
+				return nil, err
+			}
+			mf, err := move(pos, first)
+			if err != nil {
+				return nil, err
+			}
+			if sameMove(ma, mf) {
+				return nil, fmt.Errorf("seq=%v: the move exists: %s", seq, PrintMove(first))
+			}
+		}
+
+		steps[i].Variations = append(steps[i].Variations, &ptypes.Variation{Steps: line})
+		return steps, nil
+	})
+}
+
+// PromoteVariationToMain swaps the n-th variation of the step seq and the
+// main line from seq. The old main line becomes the first variation.
+func PromoteVariationToMain(k *ptypes.Kif, seq int32, n int) error {
+	return edit(k, func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error) {
+		i, _, err := findStep(b, steps, seq)
+		if err != nil {
+			return nil, err
+		}
+		old := steps[i]
+		if n < 0 || n >= len(old.Variations) {
+			return nil, fmt.Errorf("seq=%v: no variation %d", seq, n)
+		}
+
+		line := old.Variations[n].Steps
+		vars := []*ptypes.Variation{{Steps: steps[i:]}}
+		vars = append(vars, old.Variations[:n]...)
+		vars = append(vars, old.Variations[n+1:]...)
+		vars = append(vars, line[0].Variations...)
+		old.Variations = nil
+		line[0].Variations = vars
+
+		return append(steps[:i:i], line...), nil
+	})
+}
+
+// SetResult replaces the finishing step of the main line with status, or
+// removes it if status is NOT_FINISHED. CHECKMATE and NYUGYOKU_WIN are
+// checked on the final position.
+func SetResult(k *ptypes.Kif, status ptypes.FinishedStatus_Id) error {
+	return edit(k, func(b *board.Board, steps []*ptypes.Step) ([]*ptypes.Step, error) {
+		var last *ptypes.Step
+		if l := len(steps); l != 0 && isFinished(steps[l-1]) {
+			last = steps[l-1]
+			steps = steps[:l-1]
+		}
+		if status == ptypes.FinishedStatus_NOT_FINISHED {
+			return steps, nil
+		}
+
+		pos := b.Clone()
+		for _, s := range steps {
+			if err := pos.Apply(s); err != nil {
+				return nil, errors.Wrapf(err, "seq=%v", s.Seq)
+			}
+		}
+		switch status {
+		case ptypes.FinishedStatus_CHECKMATE:
+			if !pos.Checkmated() {
+				return nil, fmt.Errorf("%v is not checkmated", pos.Turn)
+			}
+		case ptypes.FinishedStatus_NYUGYOKU_WIN:
+			if !pos.CanDeclareWin(pos.Turn) {
+				return nil, fmt.Errorf("%v cannot declare win", pos.Turn)
+			}
+		}
+
+		if last == nil {
+			last = &ptypes.Step{}
+		}
+		last.FinishedStatus = status
+		last.Piece = ptypes.Piece_NULL
+		last.Modifier = ptypes.Modifier_NULL
+		last.Src, last.Dst = nil, nil
+		return append(steps, last), nil
+	})
+}
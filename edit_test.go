@@ -0,0 +1,161 @@
+package kif
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func checkSeq(t *testing.T, steps []*ptypes.Step, start int32) {
+	t.Helper()
+	for i, s := range steps {
+		if s.Seq != start+int32(i) {
+			t.Errorf("seq: expected=%v actual=%v", start+int32(i), s.Seq)
+		}
+		for _, v := range s.Variations {
+			checkSeq(t, v.Steps, s.Seq)
+		}
+	}
+}
+
+func TestTruncateAt(t *testing.T) {
+	k := parseUTF8(t, variationKIF)
+	if err := TruncateAt(k, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k.Steps); l != 2 {
+		t.Fatalf("steps: expected=2 actual=%v", l)
+	}
+	if l := len(k.Steps[1].Variations); l != 1 {
+		t.Errorf("variations of 2: %v", k.Steps[1].Variations)
+	}
+
+	if err := TruncateAt(k, 5); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestPromoteVariationToMain(t *testing.T) {
+	k := parseUTF8(t, variationKIF)
+	if err := PromoteVariationToMain(k, 3, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k.Steps); l != 5 {
+		t.Fatalf("steps: expected=5 actual=%v", l)
+	}
+	checkSeq(t, k.Steps, 1)
+
+	if s := k.Steps[3]; s.Dst != nil || s.Piece != ptypes.Piece_GIN {
+		t.Errorf("step 4: %v", s)
+	}
+	if v := k.Steps[3].Variations; len(v) != 1 || v[0].Steps[0].Dst != nil {
+		t.Errorf("variations of 4: %v", v)
+	}
+	v := k.Steps[2].Variations
+	if len(v) != 1 || len(v[0].Steps) != 2 || v[0].Steps[1].FinishedStatus != ptypes.FinishedStatus_SURRENDER {
+		t.Fatalf("variations of 3: %v", v)
+	}
+
+	if err := PromoteVariationToMain(k, 3, 1); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestReplaceMove(t *testing.T) {
+	k := parseUTF8(t, variationKIF)
+	orig := proto.Clone(k).(*ptypes.Kif)
+
+	// 2六歩 → 5六歩
+	err := ReplaceMove(k, 3, &ptypes.Step{
+		Src: &ptypes.Pos{X: 5, Y: 7}, Dst: &ptypes.Pos{X: 5, Y: 6}, Piece: ptypes.Piece_FU,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := k.Steps[2]; s.Dst.X != 5 || len(s.Variations) != 1 {
+		t.Errorf("step 3: %v", s)
+	}
+
+	// 7六歩 → 5六歩 makes 2二角成 illegal
+	k = proto.Clone(orig).(*ptypes.Kif)
+	if err := PromoteVariationToMain(k, 3, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := proto.Clone(k).(*ptypes.Kif)
+	err = ReplaceMove(k, 1, &ptypes.Step{
+		Src: &ptypes.Pos{X: 5, Y: 7}, Dst: &ptypes.Pos{X: 5, Y: 6}, Piece: ptypes.Piece_FU,
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !proto.Equal(k, before) {
+		t.Errorf("modified on error")
+	}
+}
+
+func TestInsertVariation(t *testing.T) {
+	k := parseUTF8(t, variationKIF)
+	if err := PromoteVariationToMain(k, 3, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := InsertVariation(k, 4, []*ptypes.Step{
+		{Src: &ptypes.Pos{X: 4, Y: 1}, Dst: &ptypes.Pos{X: 2, Y: 2}, Piece: ptypes.Piece_KIN},
+	})
+	if err == nil {
+		t.Fatalf("expected error: illegal move")
+	}
+
+	err = InsertVariation(k, 4, []*ptypes.Step{
+		{Src: &ptypes.Pos{X: 3, Y: 1}, Dst: &ptypes.Pos{X: 2, Y: 2}, Piece: ptypes.Piece_GIN},
+	})
+	if err == nil {
+		t.Errorf("expected error: the move exists")
+	}
+
+	err = InsertVariation(k, 4, []*ptypes.Step{
+		{Src: &ptypes.Pos{X: 2, Y: 1}, Dst: &ptypes.Pos{X: 3, Y: 3}, Piece: ptypes.Piece_KEI},
+		{FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v := k.Steps[3].Variations
+	if len(v) != 2 || v[1].Steps[0].Seq != 4 || v[1].Steps[1].Seq != 5 {
+		t.Errorf("variations of 4: %v", v)
+	}
+	checkSeq(t, k.Steps, 1)
+}
+
+func TestSetResult(t *testing.T) {
+	k := parseUTF8(t, variationKIF)
+	if err := SetResult(k, ptypes.FinishedStatus_CHECKMATE); err == nil {
+		t.Errorf("expected error")
+	}
+
+	if err := SetResult(k, ptypes.FinishedStatus_SUSPEND); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k.Steps); l != 4 || k.Steps[3].FinishedStatus != ptypes.FinishedStatus_SUSPEND {
+		t.Errorf("steps: %v", k.Steps)
+	}
+
+	if err := SetResult(k, ptypes.FinishedStatus_NOT_FINISHED); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k.Steps); l != 3 {
+		t.Errorf("steps: %v", k.Steps)
+	}
+
+	if err := TruncateAt(k, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetResult(k, ptypes.FinishedStatus_SURRENDER); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := k.Steps[1]; s.Seq != 2 || s.FinishedStatus != ptypes.FinishedStatus_SURRENDER {
+		t.Errorf("step 2: %v", s)
+	}
+}
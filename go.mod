@@ -5,5 +5,7 @@ go 1.13
 require (
 	github.com/golang/protobuf v1.3.2
 	github.com/pkg/errors v0.8.1
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/text v0.3.2
+	google.golang.org/grpc v1.26.0
 )
@@ -0,0 +1,343 @@
+package kif
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type htmlNode struct {
+	Move   string   `json:"move"`
+	Time   string   `json:"time,omitempty"`
+	Notes  []string `json:"notes,omitempty"`
+	SFEN   string   `json:"sfen"`
+	Last   []int32  `json:"last,omitempty"`
+	Parent int      `json:"parent"`
+	Next   []int    `json:"next"`
+}
+
+type htmlGame struct {
+	Headers []*ptypes.Header `json:"headers"`
+	Nodes   []*htmlNode      `json:"nodes"`
+}
+
+func (g *htmlGame) add(parent int, n *htmlNode) int {
+	id := len(g.Nodes)
+	n.Parent = parent
+	n.Next = []int{}
+	g.Nodes = append(g.Nodes, n)
+	if parent >= 0 {
+		p := g.Nodes[parent]
+		p.Next = append(p.Next, id)
+	}
+	return id
+}
+
+func (g *htmlGame) addLine(parent int, b *board.Board, steps []*ptypes.Step) error {
+	for _, step := range steps {
+		before := b.Clone()
+		n := &htmlNode{
+			Move:  PrintMove(step),
+			Time:  PrintThinking(step.ThinkingSec) + "/" + PrintElapsed(step.ElapsedSec),
+			Notes: step.Notes,
+		}
+		if step.FinishedStatus == ptypes.FinishedStatus_NOT_FINISHED {
+			if err := b.Apply(step); err != nil {
+				return errors.Wrapf(err, "seq=%v", step.Seq)
+			}
+			n.Last = []int32{b.Last.Dst.X, b.Last.Dst.Y}
+			if step.Dst == nil {
+				n.Move = PrintPhase(step) + "同　" + PrintPiece(step.Piece) + PrintModifier(step.Modifier) + printSrc(step.Src)
+			}
+		}
+		n.SFEN = b.SFEN()
+
+		id := g.add(parent, n)
+		for _, v := range step.Variations {
+			if err := g.addLine(parent, before.Clone(), v.Steps); err != nil {
+				return err
+			}
+		}
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		parent = id
+	}
+
+	return nil
+}
+
+func newHTMLGame(k *ptypes.Kif) (*htmlGame, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &htmlGame{
+		Headers: k.Headers,
+	}
+	g.add(-1, &htmlNode{
+		Move: "開始局面",
+		SFEN: b.SFEN(),
+	})
+	if err := g.addLine(0, b, k.Steps); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func htmlTitle(k *ptypes.Kif) string {
+	var sente, gote string
+	for _, h := range k.Headers {
+		switch h.Name {
+		case "表題", "棋戦":
+			if h.Value != "" {
+				return h.Value
+			}
+		case "先手", "下手":
+			sente = h.Value
+		case "後手", "上手":
+			gote = h.Value
+		}
+	}
+	if sente == "" && gote == "" {
+		return "棋譜"
+	}
+	return "▲" + sente + " △" + gote
+}
+
+func writeHTML(out io.Writer, k *ptypes.Kif) error {
+	g, err := newHTMLGame(k)
+	if err != nil {
+		return err
+	}
+
+	return htmlTemplate.Execute(out, struct {
+		Title string
+		Game  *htmlGame
+	}{
+		Title: htmlTitle(k),
+		Game:  g,
+	})
+}
+
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+h1 { font-size: 1.2em; }
+#headers th { text-align: left; padding-right: 1em; font-weight: normal; color: #555; }
+#main { display: flex; flex-wrap: wrap; gap: 1.5em; align-items: flex-start; }
+#board table { border-collapse: collapse; }
+#board td.sq { width: 2em; height: 2em; border: 1px solid #333; background: #f3d9a4; text-align: center; font-size: 1.3em; font-family: serif; }
+#board td.last { background: #e8b05c; }
+#board th { font-weight: normal; font-size: 0.8em; color: #555; }
+.gote { display: inline-block; transform: rotate(180deg); }
+.prom { color: #c03030; }
+.hand { margin: 0.3em 0; font-family: serif; }
+#controls button { font-size: 1em; min-width: 2.5em; }
+#moves { height: 24em; width: 14em; overflow-y: auto; border: 1px solid #ccc; padding: 0.2em; }
+#moves div { cursor: pointer; padding: 0 0.3em; }
+#moves div.curr { background: #e8b05c; }
+#moves div.branch::after { content: " +"; color: #c03030; }
+#variations button { display: block; margin: 0.2em 0; }
+#notes { white-space: pre-wrap; min-height: 3em; border: 1px solid #ccc; padding: 0.3em; max-width: 36em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table id="headers"></table>
+<div id="main">
+<div>
+<div class="hand" id="gote-hand"></div>
+<div id="board"></div>
+<div class="hand" id="sente-hand"></div>
+<div id="controls">
+<button id="first">|&lt;</button><button id="prev">&lt;</button><button id="next">&gt;</button><button id="last">&gt;|</button>
+</div>
+</div>
+<div id="moves"></div>
+<div>
+<div id="variations"></div>
+<div id="notes"></div>
+</div>
+</div>
+<script>
+(function() {
+  var game = {{.Game}};
+  var names = {
+    "K": "玉", "R": "飛", "B": "角", "G": "金", "S": "銀", "N": "桂", "L": "香", "P": "歩",
+    "+R": "龍", "+B": "馬", "+S": "全", "+N": "圭", "+L": "杏", "+P": "と"
+  };
+  var files = ["", "１", "２", "３", "４", "５", "６", "７", "８", "９"];
+  var ranks = ["", "一", "二", "三", "四", "五", "六", "七", "八", "九"];
+  var nums = ["", "", "二", "三", "四", "五", "六", "七", "八", "九", "十",
+    "十一", "十二", "十三", "十四", "十五", "十六", "十七", "十八"];
+  var handOrder = ["R", "B", "G", "S", "N", "L", "P"];
+  var curr = 0;
+
+  function $(id) { return document.getElementById(id); }
+
+  function escape(s) {
+    return String(s).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+  }
+
+  function parseSFEN(sfen) {
+    var f = sfen.split(" ");
+    var rows = f[0].split("/");
+    var squares = {};
+    for (var y = 1; y <= 9; y++) {
+      var x = 9, prom = "", row = rows[y - 1];
+      for (var i = 0; i < row.length; i++) {
+        var c = row.charAt(i);
+        if (c === "+") { prom = "+"; continue; }
+        if (c >= "1" && c <= "9") { x -= parseInt(c, 10); continue; }
+        var up = c.toUpperCase();
+        squares[x + "," + y] = { piece: prom + up, gote: c !== up };
+        prom = "";
+        x--;
+      }
+    }
+    var hands = [{}, {}], n = 0;
+    if (f[2] !== "-") {
+      for (var j = 0; j < f[2].length; j++) {
+        var h = f[2].charAt(j);
+        if (h >= "0" && h <= "9") { n = n * 10 + parseInt(h, 10); continue; }
+        var hu = h.toUpperCase();
+        hands[h === hu ? 0 : 1][hu] = n || 1;
+        n = 0;
+      }
+    }
+    return { squares: squares, turn: f[1], hands: hands };
+  }
+
+  function handText(hand) {
+    var s = [];
+    for (var i = 0; i < handOrder.length; i++) {
+      var n = hand[handOrder[i]];
+      if (n) { s.push(names[handOrder[i]] + nums[n]); }
+    }
+    return s.length ? s.join(" ") : "なし";
+  }
+
+  function renderBoard(node) {
+    var pos = parseSFEN(node.sfen);
+    var html = "<table><tr>";
+    for (var x = 9; x >= 1; x--) { html += "<th>" + files[x] + "</th>"; }
+    html += "<th></th></tr>";
+    for (var y = 1; y <= 9; y++) {
+      html += "<tr>";
+      for (var x2 = 9; x2 >= 1; x2--) {
+        var sq = pos.squares[x2 + "," + y];
+        var last = node.last && node.last[0] === x2 && node.last[1] === y;
+        html += "<td class=\"sq" + (last ? " last" : "") + "\">";
+        if (sq) {
+          var cls = (sq.gote ? "gote" : "") + (sq.piece.charAt(0) === "+" ? " prom" : "");
+          html += "<span class=\"" + cls + "\">" + names[sq.piece] + "</span>";
+        }
+        html += "</td>";
+      }
+      html += "<th>" + ranks[y] + "</th></tr>";
+    }
+    html += "</table>";
+    $("board").innerHTML = html;
+    $("gote-hand").textContent = "☖持駒：" + handText(pos.hands[1]);
+    $("sente-hand").textContent = "☗持駒：" + handText(pos.hands[0]);
+  }
+
+  function line() {
+    var path = [];
+    for (var id = curr; id >= 0; id = game.nodes[id].parent) { path.unshift(id); }
+    for (var n = game.nodes[curr]; n.next.length; n = game.nodes[n.next[0]]) { path.push(n.next[0]); }
+    return path;
+  }
+
+  function renderMoves() {
+    var path = line(), html = "";
+    for (var i = 0; i < path.length; i++) {
+      var n = game.nodes[path[i]];
+      var branch = n.parent >= 0 && game.nodes[n.parent].next.length > 1;
+      var cls = (path[i] === curr ? "curr" : "") + (branch ? " branch" : "");
+      html += "<div class=\"" + cls + "\" data-id=\"" + path[i] + "\">" +
+        (i ? i + " " : "") + escape(n.move) + "</div>";
+    }
+    var moves = $("moves");
+    moves.innerHTML = html;
+    var c = moves.querySelector(".curr");
+    if (c) { c.scrollIntoView({ block: "nearest" }); }
+  }
+
+  function renderVariations(node) {
+    var html = "";
+    if (node.next.length > 1) {
+      html = "変化：";
+      for (var i = 0; i < node.next.length; i++) {
+        html += "<button data-id=\"" + node.next[i] + "\">" + escape(game.nodes[node.next[i]].move) + "</button>";
+      }
+    }
+    $("variations").innerHTML = html;
+  }
+
+  function render() {
+    var node = game.nodes[curr];
+    renderBoard(node);
+    renderMoves();
+    renderVariations(node);
+    $("notes").textContent = (node.notes || []).join("\n");
+  }
+
+  function go(id) {
+    if (id >= 0 && id < game.nodes.length) { curr = id; render(); }
+  }
+
+  function first() { go(0); }
+  function prev() { go(game.nodes[curr].parent); }
+  function next() {
+    var n = game.nodes[curr];
+    if (n.next.length) { go(n.next[0]); }
+  }
+  function last() {
+    var n = game.nodes[curr];
+    while (n.next.length) { curr = n.next[0]; n = game.nodes[curr]; }
+    render();
+  }
+
+  var headers = "";
+  for (var i = 0; i < (game.headers || []).length; i++) {
+    headers += "<tr><th>" + escape(game.headers[i].name) + "</th><td>" + escape(game.headers[i].value || "") + "</td></tr>";
+  }
+  $("headers").innerHTML = headers;
+
+  $("first").onclick = first;
+  $("prev").onclick = prev;
+  $("next").onclick = next;
+  $("last").onclick = last;
+  var onSelect = function(e) {
+    var id = e.target.getAttribute("data-id");
+    if (id !== null) { go(parseInt(id, 10)); }
+  };
+  $("moves").onclick = onSelect;
+  $("variations").onclick = onSelect;
+  document.onkeydown = function(e) {
+    switch (e.key) {
+    case "ArrowLeft": prev(); break;
+    case "ArrowRight": next(); break;
+    case "Home": first(); break;
+    case "End": last(); break;
+    }
+  };
+
+  render();
+})();
+</script>
+</body>
+</html>
+`))
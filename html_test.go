@@ -0,0 +1,34 @@
+package kif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriter_Write_html(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(variationKIF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g, err := newHTMLGame(k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// initial position, 4 main moves and 5 variation moves
+	if l := len(g.Nodes); l != 10 {
+		t.Errorf("nodes: expected=10 actual=%v", l)
+	}
+	if n := g.Nodes[1]; len(n.Next) != 2 {
+		t.Errorf("branches after 1: expected=2 actual=%v", n.Next)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(SetFormat(Format_HTML)).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := buf.String(); !strings.Contains(s, `"move":"△同　銀(31)"`) {
+		t.Errorf("game data not found in:\n%s", s)
+	}
+}
@@ -0,0 +1,329 @@
+package kif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// JSON Kifu Format (https://github.com/na2hiro/json-kifu-format)
+
+type jkfPos struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+}
+
+type jkfMove struct {
+	Color   int     `json:"color"`
+	From    *jkfPos `json:"from,omitempty"`
+	To      *jkfPos `json:"to,omitempty"`
+	Piece   string  `json:"piece"`
+	Same    bool    `json:"same,omitempty"`
+	Promote *bool   `json:"promote,omitempty"`
+	Capture string  `json:"capture,omitempty"`
+}
+
+type jkfNow struct {
+	M int32 `json:"m"`
+	S int32 `json:"s"`
+}
+
+type jkfTotal struct {
+	H int32 `json:"h"`
+	M int32 `json:"m"`
+	S int32 `json:"s"`
+}
+
+type jkfTime struct {
+	Now   jkfNow   `json:"now"`
+	Total jkfTotal `json:"total"`
+}
+
+type jkfMoveFormat struct {
+	Comments []string           `json:"comments,omitempty"`
+	Move     *jkfMove           `json:"move,omitempty"`
+	Time     *jkfTime           `json:"time,omitempty"`
+	Special  string             `json:"special,omitempty"`
+	Forks    [][]*jkfMoveFormat `json:"forks,omitempty"`
+}
+
+type jkfInitial struct {
+	Preset string `json:"preset"`
+}
+
+type jkfKifu struct {
+	Header  map[string]string `json:"header"`
+	Initial *jkfInitial       `json:"initial,omitempty"`
+	Moves   []*jkfMoveFormat  `json:"moves"`
+}
+
+var jkfPresets = map[string]string{
+	"平手":    "HIRATE",
+	"香落ち":   "KY",
+	"右香落ち":  "KY_R",
+	"角落ち":   "KA",
+	"飛車落ち":  "HI",
+	"飛香落ち":  "HIKY",
+	"二枚落ち":  "2",
+	"三枚落ち":  "3",
+	"四枚落ち":  "4",
+	"五枚落ち":  "5",
+	"左五枚落ち": "5_L",
+	"六枚落ち":  "6",
+	"左七枚落ち": "7_L",
+	"右七枚落ち": "7_R",
+	"八枚落ち":  "8",
+	"十枚落ち":  "10",
+}
+
+func newJKFMove(b *board.Board, step *ptypes.Step) (*jkfMoveFormat, error) {
+	m := &jkfMoveFormat{
+		Comments: step.Notes,
+		Time: &jkfTime{
+			Now: jkfNow{M: step.ThinkingSec / 60, S: step.ThinkingSec % 60},
+			Total: jkfTotal{
+				H: step.ElapsedSec / 3600,
+				M: step.ElapsedSec / 60 % 60,
+				S: step.ElapsedSec % 60,
+			},
+		},
+	}
+	if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+		m.Special = specialMove(step.FinishedStatus, b.Turn)
+		return m, nil
+	}
+
+	dst := step.Dst
+	if dst == nil && b.Last != nil {
+		dst = b.Last.Dst
+	}
+	if dst == nil {
+		return nil, fmt.Errorf("no previous move for 同")
+	}
+	mv := &jkfMove{
+		Color: int(b.Turn),
+		To:    &jkfPos{X: dst.X, Y: dst.Y},
+		Piece: board.CSAName(step.Piece),
+		Same:  b.Last != nil && b.Last.Dst.X == dst.X && b.Last.Dst.Y == dst.Y,
+	}
+	if step.Modifier != ptypes.Modifier_PUTTED && step.Src != nil {
+		mv.From = &jkfPos{X: step.Src.X, Y: step.Src.Y}
+		if mv.Piece == "" {
+			mv.Piece = board.CSAName(b.At(step.Src.X, step.Src.Y).Piece)
+		}
+		if board.CanPromote(step.Piece) {
+			promote := step.Modifier == ptypes.Modifier_PROMOTE
+			mv.Promote = &promote
+		}
+	}
+	if sq := b.At(dst.X, dst.Y); !sq.Empty() {
+		mv.Capture = board.CSAName(sq.Piece)
+	}
+	m.Move = mv
+
+	return m, nil
+}
+
+func jkfMoves(b *board.Board, steps []*ptypes.Step) ([]*jkfMoveFormat, error) {
+	var ret []*jkfMoveFormat
+	for _, step := range steps {
+		m, err := newJKFMove(b, step)
+		if err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		for _, v := range step.Variations {
+			fork, err := jkfMoves(b.Clone(), v.Steps)
+			if err != nil {
+				return nil, err
+			}
+			m.Forks = append(m.Forks, fork)
+		}
+		ret = append(ret, m)
+
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+	}
+	return ret, nil
+}
+
+func writeJKF(out io.Writer, k *ptypes.Kif) error {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return err
+	}
+
+	j := &jkfKifu{
+		Header: map[string]string{},
+		Moves:  []*jkfMoveFormat{{}},
+	}
+	for _, h := range k.Headers {
+		if h.Name == "手合割" {
+			if p, ok := jkfPresets[h.Value]; ok {
+				j.Initial = &jkfInitial{Preset: p}
+			}
+		}
+		j.Header[h.Name] = h.Value
+	}
+
+	moves, err := jkfMoves(b, k.Steps)
+	if err != nil {
+		return err
+	}
+	j.Moves = append(j.Moves, moves...)
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(j)
+}
+
+var jkfSpecials = map[string]ptypes.FinishedStatus_Id{
+	"CHUDAN":     ptypes.FinishedStatus_SUSPEND,
+	"TORYO":      ptypes.FinishedStatus_SURRENDER,
+	"JISHOGI":    ptypes.FinishedStatus_DRAW,
+	"HIKIWAKE":   ptypes.FinishedStatus_DRAW,
+	"SENNICHITE": ptypes.FinishedStatus_REPETITION_DRAW,
+	"TSUMI":      ptypes.FinishedStatus_CHECKMATE,
+	"TIME_UP":    ptypes.FinishedStatus_OVER_TIME_LIMIT,
+	"KACHI":      ptypes.FinishedStatus_NYUGYOKU_WIN,
+}
+
+// specialStatus is the inverse of specialMove.
+func specialStatus(name string, turn board.Side) (ptypes.FinishedStatus_Id, bool) {
+	if s, ok := jkfSpecials[name]; ok {
+		return s, true
+	}
+	var fouled board.Side
+	switch name {
+	case "+ILLEGAL_ACTION":
+		fouled = board.Sente
+	case "-ILLEGAL_ACTION":
+		fouled = board.Gote
+	case "ILLEGAL_MOVE":
+		fouled = turn
+	default:
+		return ptypes.FinishedStatus_NOT_FINISHED, false
+	}
+	if fouled == turn {
+		return ptypes.FinishedStatus_FOUL_LOSS, true
+	}
+	return ptypes.FinishedStatus_FOUL_WIN, true
+}
+
+func jkfSteps(b *board.Board, moves []*jkfMoveFormat) ([]*ptypes.Step, error) {
+	var ret []*ptypes.Step
+	for _, m := range moves {
+		step := &ptypes.Step{
+			Seq:   b.Ply + 1,
+			Notes: m.Comments,
+		}
+		if t := m.Time; t != nil {
+			step.ThinkingSec = t.Now.M*60 + t.Now.S
+			step.ElapsedSec = t.Total.H*3600 + t.Total.M*60 + t.Total.S
+		}
+
+		switch {
+		case m.Special != "":
+			s, ok := specialStatus(m.Special, b.Turn)
+			if !ok {
+				return nil, fmt.Errorf("unknown special: %q", m.Special)
+			}
+			step.FinishedStatus = s
+		case m.Move != nil:
+			mv := m.Move
+			step.Piece = board.PieceFromCSA(mv.Piece)
+			if step.Piece == ptypes.Piece_NULL {
+				return nil, fmt.Errorf("unknown piece: %q", mv.Piece)
+			}
+			if mv.To != nil {
+				step.Dst = &ptypes.Pos{X: mv.To.X, Y: mv.To.Y}
+			}
+			if mv.From != nil {
+				step.Src = &ptypes.Pos{X: mv.From.X, Y: mv.From.Y}
+				if mv.Promote != nil && *mv.Promote {
+					step.Modifier = ptypes.Modifier_PROMOTE
+				}
+			} else {
+				step.Modifier = ptypes.Modifier_PUTTED
+			}
+		default:
+			return nil, fmt.Errorf("seq=%v: empty move", step.Seq)
+		}
+
+		for _, fork := range m.Forks {
+			steps, err := jkfSteps(b.Clone(), fork)
+			if err != nil {
+				return nil, err
+			}
+			step.Variations = append(step.Variations, &ptypes.Variation{Steps: steps})
+		}
+		ret = append(ret, step)
+
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+	}
+	return ret, nil
+}
+
+// ParseJKF reads a game in JSON Kifu Format. Only preset initial positions are supported.
+func ParseJKF(in io.Reader) (*ptypes.Kif, error) {
+	j := &jkfKifu{}
+	if err := json.NewDecoder(in).Decode(j); err != nil {
+		return nil, err
+	}
+
+	k := &ptypes.Kif{}
+	var names []string
+	for name := range j.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		k.Headers = append(k.Headers, &ptypes.Header{Name: name, Value: j.Header[name]})
+	}
+
+	if j.Initial != nil && j.Initial.Preset != "HIRATE" {
+		var handicap string
+		for name, p := range jkfPresets {
+			if p == j.Initial.Preset {
+				handicap = name
+			}
+		}
+		if handicap == "" {
+			return nil, fmt.Errorf("unsupported initial position: %q", j.Initial.Preset)
+		}
+		if _, ok := j.Header["手合割"]; !ok {
+			k.Headers = append(k.Headers, &ptypes.Header{Name: "手合割", Value: handicap})
+		}
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+	moves := j.Moves
+	// the first element only holds comments before the game
+	if len(moves) != 0 && moves[0].Move == nil && moves[0].Special == "" {
+		moves = moves[1:]
+	}
+	steps, err := jkfSteps(b, moves)
+	if err != nil {
+		return nil, err
+	}
+	k.Steps = steps
+
+	return k, nil
+}
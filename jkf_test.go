@@ -0,0 +1,61 @@
+package kif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestJKF(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(variationKIF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := NewWriter(SetFormat(Format_JKF))
+	var buf bytes.Buffer
+	if err := w.Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, s := range []string{`"preset": "HIRATE"`, `"special": "TORYO"`, `"forks"`, `"same": true`, `"capture": "KA"`} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in:\n%s", s, out)
+		}
+	}
+
+	k2, err := ParseJKF(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k2.Steps[2].Variations[0].Steps[1].Variations); l != 1 {
+		t.Errorf("nested variation not read: %v", l)
+	}
+	if s := k2.Steps[3]; s.FinishedStatus != ptypes.FinishedStatus_SURRENDER {
+		t.Errorf("unexpected last step: %v", s)
+	}
+
+	var buf2 bytes.Buffer
+	if err := w.Write(&buf2, k2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a := buf2.String(); a != out {
+		t.Errorf("round trip mismatch:\nexpected:\n%s\nactual:\n%s", out, a)
+	}
+}
+
+func TestParseJKF_handicap(t *testing.T) {
+	in := `{"header":{},"initial":{"preset":"KA"},"moves":[{},{"move":{"color":1,"from":{"x":5,"y":1},"to":{"x":4,"y":2},"piece":"OU"}}]}`
+	k, err := ParseJKF(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(k.Headers) != 1 || k.Headers[0].Value != "角落ち" {
+		t.Errorf("unexpected headers: %v", k.Headers)
+	}
+	if len(k.Steps) != 1 || k.Steps[0].Piece != ptypes.Piece_GYOKU {
+		t.Errorf("unexpected steps: %v", k.Steps)
+	}
+}
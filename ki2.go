@@ -0,0 +1,331 @@
+package kif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// ki2Modifiers are the words after the piece of a KI2 move.
+var ki2Modifiers = []string{"不成", "成", "打", "右", "左", "直", "上", "行", "入", "引", "寄"}
+
+type ki2Move struct {
+	same     bool
+	dst      *ptypes.Pos
+	piece    ptypes.Piece_Id
+	promote  bool
+	drop     bool
+	vertical rune // 上, 引, 寄 or 直
+	side     rune // 右 or 左
+}
+
+func parseKI2Move(s string) (*ki2Move, error) {
+	p := newStepParser(s)
+	m := &ki2Move{}
+
+	step := &ptypes.Step{}
+	if err := p.readString("同"); err == nil {
+		m.same = true
+		p.skip(step)
+	} else {
+		if err := p.readDst(step); err != nil {
+			// some files use ASCII digits for the file
+			p.reset()
+			x, err := p.readRunes([]rune(" 123456789"))
+			if err != nil {
+				return nil, err
+			}
+			y, err := p.readRunes(ystr)
+			if err != nil {
+				return nil, err
+			}
+			step.Dst = &ptypes.Pos{X: int32(x), Y: int32(y)}
+		}
+		m.dst = step.Dst
+	}
+
+	if err := p.readPiece(step); err != nil {
+		return nil, err
+	}
+	m.piece = step.Piece
+
+	for {
+		i, err := p.readStrings(ki2Modifiers)
+		if err == EOS {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch mod := ki2Modifiers[i]; mod {
+		case "不成":
+		case "成":
+			m.promote = true
+		case "打":
+			m.drop = true
+		case "右", "左":
+			m.side = []rune(mod)[0]
+		case "行", "入":
+			m.vertical = '上'
+		default:
+			m.vertical = []rune(mod)[0]
+		}
+	}
+
+	return m, nil
+}
+
+// forward returns the distance the move goes forward for the side.
+func forward(side board.Side, s *ptypes.Step) int32 {
+	if side == board.Sente {
+		return s.Src.Y - s.Dst.Y
+	}
+	return s.Dst.Y - s.Src.Y
+}
+
+// resolve returns the legal move of the position which the KI2 move denotes.
+func (m *ki2Move) resolve(b *board.Board) (*ptypes.Step, error) {
+	dst := m.dst
+	if m.same {
+		if b.Last == nil || b.Last.Dst == nil {
+			return nil, fmt.Errorf("no previous move for 同")
+		}
+		dst = b.Last.Dst
+	}
+
+	var moves, drops []*ptypes.Step
+	for _, s := range b.LegalMoves() {
+		if s.Dst.X != dst.X || s.Dst.Y != dst.Y || s.Piece != m.piece {
+			continue
+		}
+		if s.Modifier == ptypes.Modifier_PUTTED {
+			drops = append(drops, s)
+		} else if (s.Modifier == ptypes.Modifier_PROMOTE) == m.promote {
+			moves = append(moves, s)
+		}
+	}
+	// 打 is omitted unless a piece on the board can move there too.
+	if m.drop || len(moves) == 0 {
+		moves = drops
+	}
+
+	var cands []*ptypes.Step
+	for _, s := range moves {
+		if s.Src == nil {
+			cands = append(cands, s)
+			continue
+		}
+		f := forward(b.Turn, s)
+		switch m.vertical {
+		case '上':
+			if f <= 0 {
+				continue
+			}
+		case '引':
+			if f >= 0 {
+				continue
+			}
+		case '寄':
+			if f != 0 {
+				continue
+			}
+		case '直':
+			if f != 1 || s.Src.X != s.Dst.X {
+				continue
+			}
+		}
+		cands = append(cands, s)
+	}
+
+	if m.side != 0 && len(cands) > 1 {
+		// the right of Sente is the smaller file, and the left of Gote.
+		smaller := (m.side == '右') == (b.Turn == board.Sente)
+		best := cands[0]
+		for _, s := range cands[1:] {
+			if (s.Src.X < best.Src.X) == smaller {
+				best = s
+			}
+		}
+		cands = []*ptypes.Step{best}
+	}
+
+	switch len(cands) {
+	case 0:
+		return nil, fmt.Errorf("no legal move")
+	case 1:
+		step := cands[0]
+		if m.same {
+			step.Dst = nil
+		}
+		return step, nil
+	default:
+		return nil, fmt.Errorf("ambiguous move")
+	}
+}
+
+// ki2Result returns the final step of the "まで" line.
+func ki2Result(line string, turn board.Side) ptypes.FinishedStatus_Id {
+	var winner board.Side
+	if strings.Contains(line, "先手の") || strings.Contains(line, "下手の") {
+		winner = board.Sente
+	} else {
+		winner = board.Gote
+	}
+
+	switch {
+	case strings.Contains(line, "中断"):
+		return ptypes.FinishedStatus_SUSPEND
+	case strings.Contains(line, "千日手"):
+		return ptypes.FinishedStatus_REPETITION_DRAW
+	case strings.Contains(line, "持将棋"):
+		return ptypes.FinishedStatus_DRAW
+	case strings.Contains(line, "詰み"):
+		return ptypes.FinishedStatus_CHECKMATE
+	case strings.Contains(line, "切れ"):
+		return ptypes.FinishedStatus_OVER_TIME_LIMIT
+	case strings.Contains(line, "入玉"):
+		return ptypes.FinishedStatus_NYUGYOKU_WIN
+	case strings.Contains(line, "反則"):
+		if winner == turn {
+			return ptypes.FinishedStatus_FOUL_WIN
+		}
+		return ptypes.FinishedStatus_FOUL_LOSS
+	case strings.Contains(line, "勝ち"):
+		return ptypes.FinishedStatus_SURRENDER
+	default:
+		return ptypes.FinishedStatus_NOT_FINISHED
+	}
+}
+
+func splitKI2Moves(line string) []string {
+	var ret []string
+	for _, r := range line {
+		switch r {
+		case '▲', '△', '☗', '☖':
+			ret = append(ret, "")
+		case ' ', '　', '\t':
+		default:
+			if len(ret) != 0 {
+				ret[len(ret)-1] += string(r)
+			}
+		}
+	}
+	return ret
+}
+
+// ParseKI2 reads a game in KI2 format, where moves are written without source
+// squares or times, several in a line.
+func (p *Parser) ParseKI2(in io.Reader) (*ptypes.Kif, error) {
+	br := bufio.NewReader(p.transformReader(in))
+	if err := dropBOM(br); err != nil && err != io.EOF {
+		return nil, err
+	}
+	r := newLineReader(br)
+
+	ret := &ptypes.Kif{}
+	lines := []*[]*ptypes.Step{&ret.Steps}
+	// the positions before the steps, to start variations from
+	boards := map[*ptypes.Step]*board.Board{}
+	var b *board.Board
+	var prevStep *ptypes.Step
+	// notes before the first step of a line, which go to the step
+	var pending []string
+
+	count := 0
+	for {
+		count++
+
+		line, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, " 　\t")
+
+		switch {
+		case len(line) == 0 || line[0] == '#':
+			continue
+		case b == nil && line[0] != '*' && strings.Contains(line, "：") && !strings.ContainsAny(line, "▲△☗☖"):
+			header := strings.SplitN(line, "：", 2)
+			ret.Headers = append(ret.Headers, &ptypes.Header{Name: header[0], Value: header[1]})
+			continue
+		}
+
+		if b == nil {
+			b, err = board.FromKif(ret)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if seq, ok := parseVariationLine(line); ok {
+			if len(pending) != 0 {
+				return nil, errors.Errorf("line=%v comment without steps: %v", count, pending[0])
+			}
+			i, step := findBranch(lines, seq)
+			if step == nil {
+				return nil, errors.Errorf("line=%v branch not found: %v", count, line)
+			}
+			v := &ptypes.Variation{}
+			step.Variations = append(step.Variations, v)
+			lines = append(lines[:i+1], &v.Steps)
+			b = boards[step].Clone()
+			prevStep = nil
+			continue
+		}
+
+		if line[0] == '*' {
+			if prevStep == nil {
+				pending = append(pending, line[1:])
+			} else {
+				prevStep.Notes = append(prevStep.Notes, line[1:])
+			}
+			continue
+		}
+
+		curr := lines[len(lines)-1]
+		if strings.HasPrefix(line, "まで") {
+			if s := ki2Result(line, b.Turn); s != ptypes.FinishedStatus_NOT_FINISHED {
+				prevStep = &ptypes.Step{Seq: b.Ply + 1, FinishedStatus: s, Notes: pending}
+				pending = nil
+				*curr = append(*curr, prevStep)
+			}
+			continue
+		}
+
+		moves := splitKI2Moves(line)
+		if len(moves) == 0 {
+			return nil, errors.Errorf("line=%v unknown line: %v", count, line)
+		}
+		for _, mv := range moves {
+			m, err := parseKI2Move(mv)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line=%v %v", count, mv)
+			}
+			step, err := m.resolve(b)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line=%v %v", count, mv)
+			}
+			boards[step] = b.Clone()
+			if err := b.Apply(step); err != nil {
+				return nil, errors.Wrapf(err, "line=%v %v", count, mv)
+			}
+			step.Notes = append(pending, step.Notes...)
+			pending = nil
+
+			*curr = append(*curr, step)
+			prevStep = step
+		}
+	}
+	if len(pending) != 0 {
+		return nil, errors.Errorf("line=%v comment without steps: %v", count, pending[0])
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,142 @@
+package kif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+const testKI2 = `手合割：平手
+先手：A
+後手：B
+
+▲７六歩    △３四歩    ▲２二角成  △同　銀
+*コメント
+▲５八金右  △５二金右  ▲４五角
+まで7手で先手の勝ち
+
+変化：5手
+▲６八銀    △３三銀 
+`
+
+func TestParser_ParseKI2(t *testing.T) {
+	illegal := strings.Replace(testKI2, "△３三銀", "△同　角", 1)
+	if _, err := NewParser(ParseEncodingUTF8()).ParseKI2(strings.NewReader(illegal)); err == nil {
+		t.Fatalf("illegal variation is not detected")
+	}
+
+	k, err := NewParser(ParseEncodingUTF8()).ParseKI2(strings.NewReader(testKI2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(k.Headers) != 3 {
+		t.Errorf("unexpected headers: %v", k.Headers)
+	}
+	if l := len(k.Steps); l != 8 {
+		t.Fatalf("steps: expected=8 actual=%v", l)
+	}
+	for i, exp := range []string{
+		"▲７六歩(77)",
+		"△３四歩(33)",
+		"▲２二角成(88)",
+		"△銀(31)",
+		"▲５八金(49)",
+		"△５二金(61)",
+		"▲４五角打",
+		"△投了",
+	} {
+		if a := PrintMove(k.Steps[i]); a != exp {
+			t.Errorf("step %d: expected=%v actual=%v", i+1, exp, a)
+		}
+	}
+	if n := k.Steps[3].Notes; len(n) != 1 || n[0] != "コメント" {
+		t.Errorf("unexpected notes: %v", n)
+	}
+
+	vs := k.Steps[4].Variations
+	if len(vs) != 1 || len(vs[0].Steps) != 2 {
+		t.Fatalf("unexpected variations: %v", vs)
+	}
+	if a := PrintMove(vs[0].Steps[1]); a != "△３三銀(22)" {
+		t.Errorf("unexpected variation: %v", a)
+	}
+
+	if errs := Validate(k); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestParser_ParseKI2_comment(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).ParseKI2(strings.NewReader(`手合割：平手
+*序盤の解説
+▲７六歩    △３四歩
+
+変化：2手
+*変化の解説
+△８四歩
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := len(k.Steps); l != 2 {
+		t.Fatalf("steps: expected=2 actual=%v", l)
+	}
+	if n := k.Steps[0].Notes; len(n) != 1 || n[0] != "序盤の解説" {
+		t.Errorf("notes of 1: %v", n)
+	}
+	v := k.Steps[1].Variations
+	if len(v) != 1 || len(v[0].Steps) != 1 {
+		t.Fatalf("variations of 2: %v", v)
+	}
+	if n := v[0].Steps[0].Notes; len(n) != 1 || n[0] != "変化の解説" {
+		t.Errorf("notes of the variation: %v", n)
+	}
+	if n := k.Steps[1].Notes; len(n) != 0 {
+		t.Errorf("notes of 2: %v", n)
+	}
+
+	for _, in := range []string{
+		"▲７六歩    △３四歩\n\n変化：2手\n*変化の解説\n",
+		"▲７六歩    △３四歩\n\n変化：2手\n*変化の解説\n\n変化：2手\n△８四歩\n",
+	} {
+		if _, err := NewParser(ParseEncodingUTF8()).ParseKI2(strings.NewReader(in)); err == nil {
+			t.Errorf("expected error: %q", in)
+		}
+	}
+}
+
+func TestKI2Move_resolve(t *testing.T) {
+	// golds on 4九 and 6九, silvers on 3九 and 7九
+	for _, c := range []struct {
+		in  string
+		src *ptypes.Pos
+	}{
+		{"５八金右", &ptypes.Pos{X: 4, Y: 9}},
+		{"５八金左", &ptypes.Pos{X: 6, Y: 9}},
+		{"５八金", nil},
+		{"４八銀", &ptypes.Pos{X: 3, Y: 9}},
+		{"７八銀直", &ptypes.Pos{X: 7, Y: 9}},
+		{"６八銀直", nil},
+	} {
+		m, err := parseKI2Move(c.in)
+		if err != nil {
+			t.Fatalf("%s: %v", c.in, err)
+		}
+		s, err := m.resolve(board.New())
+		if c.src == nil {
+			if err == nil {
+				t.Errorf("%s: expected error, got %v", c.in, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", c.in, err)
+		}
+		if s.Src.X != c.src.X || s.Src.Y != c.src.Y {
+			t.Errorf("%s: expected=%v actual=%v", c.in, c.src, s.Src)
+		}
+	}
+}
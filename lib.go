@@ -54,7 +54,16 @@ func (h headerSlice) Less(i, j int) bool {
 
 func (h headerSlice) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
 
+func normalizeSteps(steps []*ptypes.Step) {
+	sort.Sort(stepSlice(steps))
+	for _, s := range steps {
+		for _, v := range s.Variations {
+			normalizeSteps(v.Steps)
+		}
+	}
+}
+
 func Normalize(k *ptypes.Kif) {
 	sort.Sort(headerSlice(k.Headers))
-	sort.Sort(stepSlice(k.Steps))
+	normalizeSteps(k.Steps)
 }
@@ -0,0 +1,286 @@
+package match
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/clock"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/usi"
+)
+
+// Engine is the part of *usi.Engine used for playing games.
+type Engine interface {
+	NewGame() error
+	SetPositionSFEN(sfen string, moves ...string) error
+	Go(ctx context.Context, params *usi.GoParams, onInfo func(*usi.Info)) (*usi.Result, error)
+	GameOver(result string) error
+}
+
+type Player struct {
+	Name   string
+	Engine Engine
+}
+
+type Result struct {
+	Kif *ptypes.Kif
+	// Winner is meaningless when Draw is true.
+	Winner board.Side
+	Draw   bool
+}
+
+type runner struct {
+	handicap string
+	tc       *clock.TimeControl
+	params   usi.GoParams
+	maxMoves int32
+	margin   time.Duration
+	onMove   func(*ptypes.Step)
+	now      func() time.Time
+}
+
+type PlayOption func(*runner)
+
+// PlayHandicap sets the 手合割 of the game. The default is 平手.
+func PlayHandicap(name string) PlayOption {
+	return func(r *runner) {
+		r.handicap = name
+	}
+}
+
+// PlayTimeControl enforces the time control. A player running out of time loses.
+func PlayTimeControl(tc *clock.TimeControl) PlayOption {
+	return func(r *runner) {
+		r.tc = tc
+	}
+}
+
+// PlayGoParams sets the search limits used without a time control.
+// The default is 1s per move.
+func PlayGoParams(params usi.GoParams) PlayOption {
+	return func(r *runner) {
+		r.params = params
+	}
+}
+
+// PlayMaxMoves ends the game as a draw after n moves. The default is 256.
+func PlayMaxMoves(n int32) PlayOption {
+	return func(r *runner) {
+		r.maxMoves = n
+	}
+}
+
+// PlayMargin sets how long to wait for bestmove after the time is up before sending stop.
+func PlayMargin(d time.Duration) PlayOption {
+	return func(r *runner) {
+		r.margin = d
+	}
+}
+
+func PlayOnMove(f func(*ptypes.Step)) PlayOption {
+	return func(r *runner) {
+		r.onMove = f
+	}
+}
+
+func (r *runner) goParams(c *clock.Clock) *usi.GoParams {
+	if c == nil {
+		params := r.params
+		return &params
+	}
+	return &usi.GoParams{
+		BTime:   c.Remaining(board.Sente),
+		WTime:   c.Remaining(board.Gote),
+		Byoyomi: r.tc.Byoyomi,
+		BInc:    r.tc.Increment,
+		WInc:    r.tc.Increment,
+	}
+}
+
+func addHeader(k *ptypes.Kif, name, value string) {
+	k.Headers = append(k.Headers, &ptypes.Header{Name: name, Value: value})
+}
+
+// game is the state of a game in progress.
+type game struct {
+	b     *board.Board
+	k     *ptypes.Kif
+	moves []string
+	rep   *board.Repetition
+}
+
+// finish appends the final step. The side to move wins by FOUL_WIN and
+// NYUGYOKU_WIN, and loses otherwise.
+func (g *game) finish(status ptypes.FinishedStatus_Id, thinking time.Duration) *Result {
+	step := &ptypes.Step{
+		Seq:            g.b.Ply + 1,
+		FinishedStatus: status,
+	}
+	kif.SetThinkingDuration(step, thinking)
+	g.k.Steps = append(g.k.Steps, step)
+	kif.RecomputeElapsed(g.k)
+
+	ret := &Result{Kif: g.k}
+	switch status {
+	case ptypes.FinishedStatus_DRAW, ptypes.FinishedStatus_REPETITION_DRAW:
+		ret.Draw = true
+	case ptypes.FinishedStatus_FOUL_WIN, ptypes.FinishedStatus_NYUGYOKU_WIN:
+		ret.Winner = g.b.Turn
+	default:
+		ret.Winner = g.b.Turn.Opponent()
+	}
+	return ret
+}
+
+var repetitionStatus = map[board.RepetitionResult]ptypes.FinishedStatus_Id{
+	board.Repetition_DRAW:              ptypes.FinishedStatus_REPETITION_DRAW,
+	board.Repetition_CHECK_BY_MOVER:    ptypes.FinishedStatus_FOUL_WIN,
+	board.Repetition_CHECK_BY_OPPONENT: ptypes.FinishedStatus_FOUL_LOSS,
+}
+
+// Play plays a game between two engines and records it.
+// Illegal moves, perpetual check and false declarations lose by foul.
+func Play(ctx context.Context, sente, gote *Player, ops ...PlayOption) (*Result, error) {
+	r := &runner{
+		handicap: "平手",
+		maxMoves: 256,
+		margin:   time.Second,
+		onMove:   func(*ptypes.Step) {},
+		now:      time.Now,
+	}
+	for _, f := range ops {
+		f(r)
+	}
+	if r.tc == nil && r.params.Depth == 0 && r.params.MoveTime == 0 && r.params.Nodes == 0 {
+		r.params.MoveTime = time.Second
+	}
+
+	b, err := board.NewHandicap(r.handicap)
+	if err != nil {
+		return nil, err
+	}
+	sfen := b.SFEN()
+
+	k := &ptypes.Kif{}
+	kif.SetStartTime(k, r.now())
+	addHeader(k, "手合割", r.handicap)
+	if r.handicap == "平手" {
+		addHeader(k, "先手", sente.Name)
+		addHeader(k, "後手", gote.Name)
+	} else {
+		addHeader(k, "下手", sente.Name)
+		addHeader(k, "上手", gote.Name)
+	}
+	if r.tc != nil {
+		addHeader(k, "持ち時間", r.tc.String())
+	}
+
+	players := [2]*Player{sente, gote}
+	for _, p := range players {
+		if err := p.Engine.NewGame(); err != nil {
+			return nil, errors.Wrap(err, p.Name)
+		}
+	}
+
+	var c *clock.Clock
+	if r.tc != nil {
+		c = clock.New(r.tc)
+	}
+
+	g := &game{
+		b:   b,
+		k:   k,
+		rep: board.NewRepetition(b),
+	}
+
+	var ret *Result
+	for ret == nil {
+		if r.maxMoves > 0 && b.Ply >= r.maxMoves {
+			ret = g.finish(ptypes.FinishedStatus_DRAW, 0)
+			break
+		}
+
+		side := b.Turn
+		p := players[side]
+		if err := p.Engine.SetPositionSFEN(sfen, g.moves...); err != nil {
+			return nil, errors.Wrap(err, p.Name)
+		}
+
+		goCtx, cancel := ctx, func() {}
+		if c != nil {
+			goCtx, cancel = context.WithTimeout(ctx, c.Available(side)+r.margin)
+		}
+		start := r.now()
+		res, err := p.Engine.Go(goCtx, r.goParams(c), nil)
+		d := r.now().Sub(start)
+		cancel()
+		if err == context.DeadlineExceeded && c != nil && ctx.Err() == nil {
+			// the engine did not stop in time
+			ret = g.finish(ptypes.FinishedStatus_OVER_TIME_LIMIT, d)
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, p.Name)
+		}
+
+		if c != nil && !c.Consume(side, d) {
+			ret = g.finish(ptypes.FinishedStatus_OVER_TIME_LIMIT, d)
+			break
+		}
+
+		switch res.BestMove {
+		case usi.MoveResign:
+			ret = g.finish(ptypes.FinishedStatus_SURRENDER, d)
+			continue
+		case usi.MoveWin:
+			if b.CanDeclareWin(side) {
+				ret = g.finish(ptypes.FinishedStatus_NYUGYOKU_WIN, d)
+			} else {
+				ret = g.finish(ptypes.FinishedStatus_FOUL_LOSS, d)
+			}
+			continue
+		}
+
+		step, err := b.StepFromUSI(res.BestMove)
+		if err == nil {
+			err = b.Validate(step)
+		}
+		if err != nil {
+			ret = g.finish(ptypes.FinishedStatus_FOUL_LOSS, d)
+			continue
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, err
+		}
+		kif.SetThinkingDuration(step, d)
+		k.Steps = append(k.Steps, step)
+		g.moves = append(g.moves, res.BestMove)
+		r.onMove(step)
+
+		if rep := g.rep.Add(b); rep != board.Repetition_NONE {
+			ret = g.finish(repetitionStatus[rep], 0)
+		} else if b.Checkmated() {
+			ret = g.finish(ptypes.FinishedStatus_CHECKMATE, 0)
+		}
+	}
+	kif.SetEndTime(k, r.now())
+
+	for i, p := range players {
+		result := "lose"
+		switch {
+		case ret.Draw:
+			result = "draw"
+		case ret.Winner == board.Side(i):
+			result = "win"
+		}
+		if err := p.Engine.GameOver(result); err != nil {
+			return nil, errors.Wrap(err, p.Name)
+		}
+	}
+
+	return ret, nil
+}
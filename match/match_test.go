@@ -0,0 +1,166 @@
+package match
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/clock"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/usi"
+)
+
+// fakeEngine plays the moves in order, taking think on every move.
+type fakeEngine struct {
+	moves    []string
+	think    time.Duration
+	now      *time.Time
+	params   []*usi.GoParams
+	gameOver string
+}
+
+func (e *fakeEngine) NewGame() error { return nil }
+
+func (e *fakeEngine) SetPositionSFEN(sfen string, moves ...string) error { return nil }
+
+func (e *fakeEngine) Go(ctx context.Context, params *usi.GoParams, onInfo func(*usi.Info)) (*usi.Result, error) {
+	e.params = append(e.params, params)
+	*e.now = e.now.Add(e.think)
+	m := e.moves[0]
+	e.moves = e.moves[1:]
+	return &usi.Result{BestMove: m}, nil
+}
+
+func (e *fakeEngine) GameOver(result string) error {
+	e.gameOver = result
+	return nil
+}
+
+func play(t *testing.T, sente, gote *fakeEngine, ops ...PlayOption) *Result {
+	t.Helper()
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	sente.now, gote.now = &now, &now
+	ops = append(ops, func(r *runner) {
+		r.now = func() time.Time { return now }
+	})
+
+	r, err := Play(context.Background(),
+		&Player{Name: "black", Engine: sente},
+		&Player{Name: "white", Engine: gote},
+		ops...,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return r
+}
+
+func header(k *ptypes.Kif, name string) string {
+	for _, h := range k.Headers {
+		if h.Name == name {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+func lastStatus(r *Result) ptypes.FinishedStatus_Id {
+	return r.Kif.Steps[len(r.Kif.Steps)-1].FinishedStatus
+}
+
+func TestPlay_Resign(t *testing.T) {
+	sente := &fakeEngine{moves: []string{"7g7f", "resign"}, think: 3 * time.Second}
+	gote := &fakeEngine{moves: []string{"3c3d"}, think: 2 * time.Second}
+	r := play(t, sente, gote)
+
+	if r.Draw || r.Winner != board.Gote {
+		t.Errorf("unexpected result: draw=%v winner=%v", r.Draw, r.Winner)
+	}
+	if s := lastStatus(r); s != ptypes.FinishedStatus_SURRENDER {
+		t.Errorf("expected=SURRENDER actual=%v", s)
+	}
+	if sente.gameOver != "lose" || gote.gameOver != "win" {
+		t.Errorf("gameover: sente=%v gote=%v", sente.gameOver, gote.gameOver)
+	}
+
+	k := r.Kif
+	if h := header(k, "先手"); h != "black" {
+		t.Errorf("先手: %v", h)
+	}
+	if h := header(k, "後手"); h != "white" {
+		t.Errorf("後手: %v", h)
+	}
+	if h := header(k, "開始日時"); h != "2020/01/02 03:04:05" {
+		t.Errorf("開始日時: %v", h)
+	}
+	if h := header(k, "終了日時"); h != "2020/01/02 03:04:13" {
+		t.Errorf("終了日時: %v", h)
+	}
+	if s := k.Steps[2]; s.Seq != 3 || s.ThinkingSec != 3 || s.ElapsedSec != 6 {
+		t.Errorf("unexpected last step: %v", s)
+	}
+}
+
+func TestPlay_Illegal(t *testing.T) {
+	sente := &fakeEngine{moves: []string{"7g7e"}}
+	gote := &fakeEngine{}
+	r := play(t, sente, gote)
+
+	if s := lastStatus(r); s != ptypes.FinishedStatus_FOUL_LOSS || r.Winner != board.Gote {
+		t.Errorf("unexpected result: %v winner=%v", s, r.Winner)
+	}
+}
+
+func TestPlay_Repetition(t *testing.T) {
+	sente := &fakeEngine{}
+	gote := &fakeEngine{}
+	for i := 0; i < 3; i++ {
+		sente.moves = append(sente.moves, "5i5h", "5h5i")
+		gote.moves = append(gote.moves, "5a5b", "5b5a")
+	}
+	r := play(t, sente, gote)
+
+	if s := lastStatus(r); s != ptypes.FinishedStatus_REPETITION_DRAW || !r.Draw {
+		t.Errorf("unexpected result: %v draw=%v", s, r.Draw)
+	}
+	if n := len(r.Kif.Steps); n != 13 {
+		t.Errorf("steps: expected=13 actual=%v", n)
+	}
+	if sente.gameOver != "draw" {
+		t.Errorf("gameover: %v", sente.gameOver)
+	}
+}
+
+func TestPlay_TimeControl(t *testing.T) {
+	tc := &clock.TimeControl{Main: time.Minute, Byoyomi: 10 * time.Second}
+	sente := &fakeEngine{moves: []string{"7g7f", "2g2f"}, think: 50 * time.Second}
+	gote := &fakeEngine{moves: []string{"3c3d"}, think: time.Second}
+	r := play(t, sente, gote, PlayTimeControl(tc))
+
+	if s := lastStatus(r); s != ptypes.FinishedStatus_OVER_TIME_LIMIT || r.Winner != board.Gote {
+		t.Errorf("unexpected result: %v winner=%v", s, r.Winner)
+	}
+	if h := header(r.Kif, "持ち時間"); h != tc.String() {
+		t.Errorf("持ち時間: %v", h)
+	}
+	if p := sente.params[1]; p.BTime != 10*time.Second || p.WTime != 59*time.Second || p.Byoyomi != 10*time.Second {
+		t.Errorf("unexpected go params: %+v", p)
+	}
+}
+
+func TestPlay_Handicap(t *testing.T) {
+	sente := &fakeEngine{moves: []string{"resign"}}
+	gote := &fakeEngine{moves: []string{"5a4b"}}
+	r := play(t, sente, gote, PlayHandicap("角落ち"))
+
+	if h := header(r.Kif, "上手"); h != "white" {
+		t.Errorf("上手: %v", h)
+	}
+	if s := r.Kif.Steps[0]; s.Piece != ptypes.Piece_GYOKU {
+		t.Errorf("unexpected first move: %v", s)
+	}
+	if r.Winner != board.Gote {
+		t.Errorf("winner: %v", r.Winner)
+	}
+}
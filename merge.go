@@ -0,0 +1,222 @@
+package kif
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// MergeError is the error of Merge when the main lines have different moves.
+type MergeError struct {
+	Seq int32
+	// A and B are the moves of the records, or their finishing steps.
+	A, B string
+}
+
+func (e *MergeError) Error() string {
+	return fmt.Sprintf("seq=%d: the records differ: %s and %s", e.Seq, e.A, e.B)
+}
+
+type HeaderConflict struct {
+	Name string
+	// A is the value kept, and B is the value of the second record.
+	A, B string
+}
+
+func (c *HeaderConflict) String() string {
+	return fmt.Sprintf("%s: %q and %q", c.Name, c.A, c.B)
+}
+
+type merger struct {
+	// recompute is set when a has got times of b.
+	recompute bool
+}
+
+// move returns the step resolved on the position.
+func move(b *board.Board, s *ptypes.Step) (*ptypes.Step, error) {
+	b = b.Clone()
+	if err := b.Apply(s); err != nil {
+		return nil, errors.Wrapf(err, "seq=%v", s.Seq)
+	}
+	return b.Last, nil
+}
+
+func sameMove(a, b *ptypes.Step) bool {
+	return a.Piece == b.Piece && a.Modifier == b.Modifier &&
+		proto.Equal(a.Src, b.Src) && proto.Equal(a.Dst, b.Dst)
+}
+
+func cloneSteps(steps []*ptypes.Step) []*ptypes.Step {
+	ret := make([]*ptypes.Step, len(steps))
+	for i, s := range steps {
+		ret[i] = proto.Clone(s).(*ptypes.Step)
+	}
+	return ret
+}
+
+// mergeInfo adds the notes, and the times and the evaluation which a does
+// not have, of b to a.
+func (m *merger) mergeInfo(a, b *ptypes.Step) {
+	notes := make(map[string]bool)
+	for _, n := range a.Notes {
+		notes[n] = true
+	}
+	for _, n := range b.Notes {
+		if !notes[n] {
+			a.Notes = append(a.Notes, n)
+		}
+	}
+
+	if a.ThinkingSec == 0 && a.ThinkingNanos == 0 && (b.ThinkingSec != 0 || b.ThinkingNanos != 0) {
+		a.ThinkingSec, a.ThinkingNanos = b.ThinkingSec, b.ThinkingNanos
+		m.recompute = true
+	}
+	if a.Evaluation == nil && b.Evaluation != nil {
+		a.Evaluation = proto.Clone(b.Evaluation).(*ptypes.Evaluation)
+	}
+}
+
+// addVariation adds the line as an alternative to the step a, merging it with
+// the variation starting with the same move.
+func (m *merger) addVariation(pos *board.Board, a *ptypes.Step, line []*ptypes.Step) error {
+	if len(line) == 0 {
+		return nil
+	}
+	first, err := move(pos, line[0])
+	if err != nil {
+		return err
+	}
+	for _, v := range a.Variations {
+		if len(v.Steps) == 0 {
+			continue
+		}
+		vfirst, err := move(pos, v.Steps[0])
+		if err != nil {
+			return err
+		}
+		if sameMove(first, vfirst) {
+			v.Steps, err = m.mergeSteps(pos.Clone(), v.Steps, line, false)
+			return err
+		}
+	}
+	a.Variations = append(a.Variations, &ptypes.Variation{Steps: cloneSteps(line)})
+	return nil
+}
+
+// mergeSteps merges the line bs into the line as from the position. If the
+// lines differ, it fails when strict is set, and the rest of bs is added as a
+// variation otherwise.
+func (m *merger) mergeSteps(pos *board.Board, as, bs []*ptypes.Step, strict bool) ([]*ptypes.Step, error) {
+	i := 0
+	for ; i < len(as) && i < len(bs); i++ {
+		a, b := as[i], bs[i]
+		if a.Seq != b.Seq {
+			return nil, errors.Errorf("seq=%v: the move numbers differ: %v and %v", a.Seq, a.Seq, b.Seq)
+		}
+
+		finishedA := a.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED
+		finishedB := b.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED
+		if finishedA || finishedB {
+			if a.FinishedStatus == b.FinishedStatus {
+				m.mergeInfo(a, b)
+				return as, nil
+			}
+			if strict {
+				return nil, &MergeError{Seq: a.Seq, A: PrintMove(a), B: PrintMove(b)}
+			}
+			// a finishing step has no variations
+			return as, nil
+		}
+
+		ma, err := move(pos, a)
+		if err != nil {
+			return nil, err
+		}
+		mb, err := move(pos, b)
+		if err != nil {
+			return nil, err
+		}
+		if !sameMove(ma, mb) {
+			if strict {
+				return nil, &MergeError{Seq: a.Seq, A: PrintMove(a), B: PrintMove(b)}
+			}
+			return as, m.addVariation(pos, a, bs[i:])
+		}
+
+		m.mergeInfo(a, b)
+		for _, v := range b.Variations {
+			if err := m.addVariation(pos, a, v.Steps); err != nil {
+				return nil, err
+			}
+		}
+		if err := pos.Apply(a); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", a.Seq)
+		}
+	}
+
+	// b is longer
+	if rest := bs[i:]; len(rest) != 0 {
+		m.recompute = true
+		as = append(as, cloneSteps(rest)...)
+	}
+	return as, nil
+}
+
+// Merge merges two records of the same game into a new one. The moves are
+// aligned by Seq and compared on the board. The headers are the union of
+// them, where the values of a are kept on conflicts, the notes of the steps
+// are combined, the times and evaluations which a does not have are taken
+// from b, and the variations are merged. The main line of one record may be
+// shorter than the other. Merge fails with *MergeError if the main lines
+// have different moves or results.
+func Merge(a, b *ptypes.Kif) (*ptypes.Kif, []*HeaderConflict, error) {
+	posA, err := board.FromKif(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	posB, err := board.FromKif(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if posA.Key() != posB.Key() {
+		return nil, nil, errors.New("the initial positions differ")
+	}
+
+	ret := proto.Clone(a).(*ptypes.Kif)
+
+	var conflicts []*HeaderConflict
+	headers := make(map[string]*ptypes.Header)
+	for _, h := range ret.Headers {
+		if _, ok := headers[h.Name]; !ok {
+			headers[h.Name] = h
+		}
+	}
+	for _, h := range b.Headers {
+		kept, ok := headers[h.Name]
+		switch {
+		case !ok:
+			kept = &ptypes.Header{Name: h.Name, Value: h.Value}
+			headers[h.Name] = kept
+			ret.Headers = append(ret.Headers, kept)
+		case kept.Value == "":
+			kept.Value = h.Value
+		case h.Value != "" && h.Value != kept.Value:
+			conflicts = append(conflicts, &HeaderConflict{Name: h.Name, A: kept.Value, B: h.Value})
+		}
+	}
+
+	m := &merger{}
+	ret.Steps, err = m.mergeSteps(posA, ret.Steps, b.Steps, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.recompute {
+		RecomputeElapsed(ret)
+	}
+
+	return ret, conflicts, nil
+}
@@ -0,0 +1,135 @@
+package kif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func parseUTF8(t *testing.T, s string) *ptypes.Kif {
+	t.Helper()
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return k
+}
+
+func TestMerge(t *testing.T) {
+	timed := parseUTF8(t, `先手：A
+後手：B
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:10/00:00:10)
+   2 ３四歩(33)   ( 0:20/00:00:20)
+   3 ２六歩(27)   ( 0:05/00:00:15)
+`)
+	commented := parseUTF8(t, `先手：A
+後手：C
+棋戦：test
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+*first
+   2 ３四歩(33)   ( 0:00/00:00:00)+
+   3 ２六歩(27)   ( 0:00/00:00:00)
+   4 ４四歩(43)   ( 0:00/00:00:00)
+   5 投了   ( 0:00/00:00:00)
+
+変化：2手
+   2 ８四歩(83)   ( 0:00/00:00:00)
+`)
+
+	k, conflicts, err := Merge(timed, commented)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(conflicts) != 1 || conflicts[0].Name != "後手" || conflicts[0].A != "B" || conflicts[0].B != "C" {
+		t.Errorf("conflicts: %v", conflicts)
+	}
+	if len(k.Headers) != 3 || k.Headers[2].Name != "棋戦" {
+		t.Errorf("headers: %v", k.Headers)
+	}
+
+	if l := len(k.Steps); l != 5 {
+		t.Fatalf("steps: expected=5 actual=%v", l)
+	}
+	if s := k.Steps[0]; s.ThinkingSec != 10 || len(s.Notes) != 1 || s.Notes[0] != "first" {
+		t.Errorf("step 1: %v", s)
+	}
+	if s := k.Steps[2]; s.ThinkingSec != 5 || s.ElapsedSec != 15 {
+		t.Errorf("step 3: %v", s)
+	}
+	if v := k.Steps[1].Variations; len(v) != 1 || v[0].Steps[0].Dst.X != 8 {
+		t.Errorf("variations: %v", v)
+	}
+	if s := k.Steps[4]; s.FinishedStatus != ptypes.FinishedStatus_SURRENDER {
+		t.Errorf("step 5: %v", s)
+	}
+
+	// the inputs are not modified
+	if len(timed.Steps) != 3 || len(timed.Steps[0].Notes) != 0 {
+		t.Errorf("input modified: %v", timed.Steps)
+	}
+}
+
+func TestMerge_Variations(t *testing.T) {
+	a := parseUTF8(t, variationKIF)
+	b := parseUTF8(t, `手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ３四歩(33)   ( 0:00/00:00:00)+
+   3 ２六歩(27)   ( 0:00/00:00:00)
+
+変化：2手
+   2 ８四歩(83)   ( 0:00/00:00:00)
+   3 ２六歩(27)   ( 0:00/00:00:00)
+`)
+
+	k, _, err := Merge(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v := k.Steps[1].Variations
+	if len(v) != 1 || len(v[0].Steps) != 2 {
+		t.Fatalf("variations of 2: %v", v)
+	}
+
+	// a different continuation of the variation becomes a variation of it
+	b.Steps[1].Variations[0].Steps[1] = &ptypes.Step{
+		Seq: 3, Src: &ptypes.Pos{X: 5, Y: 7}, Dst: &ptypes.Pos{X: 5, Y: 6}, Piece: ptypes.Piece_FU,
+	}
+	k, _, err = Merge(k, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v = k.Steps[1].Variations
+	if len(v) != 1 || len(v[0].Steps) != 2 || len(v[0].Steps[1].Variations) != 1 {
+		t.Fatalf("variations of 2: %v", v)
+	}
+}
+
+func TestMerge_Diverged(t *testing.T) {
+	a := parseUTF8(t, variationKIF)
+	b := parseUTF8(t, `手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ８四歩(83)   ( 0:00/00:00:00)
+`)
+	_, _, err := Merge(a, b)
+	merr, ok := err.(*MergeError)
+	if !ok {
+		t.Fatalf("expected MergeError: %v", err)
+	}
+	if merr.Seq != 2 {
+		t.Errorf("seq: expected=2 actual=%v", merr.Seq)
+	}
+
+	b = parseUTF8(t, `手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ３四歩(33)   ( 0:00/00:00:00)
+   3 ２六歩(27)   ( 0:00/00:00:00)
+   4 中断   ( 0:00/00:00:00)
+`)
+	if _, _, err := Merge(a, b); err == nil {
+		t.Errorf("different results must fail")
+	}
+}
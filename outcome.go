@@ -0,0 +1,86 @@
+package kif
+
+import (
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// Outcome is the result of a game.
+type Outcome int
+
+const (
+	// Outcome_UNFINISHED means the game has no result, or was suspended.
+	Outcome_UNFINISHED Outcome = iota
+	Outcome_SENTE_WIN
+	Outcome_GOTE_WIN
+	Outcome_DRAW
+)
+
+var outcomeNames = []string{
+	"unfinished",
+	"sente",
+	"gote",
+	"draw",
+}
+
+func (o Outcome) String() string {
+	if o < 0 || int(o) >= len(outcomeNames) {
+		return "unknown"
+	}
+	return outcomeNames[o]
+}
+
+// Winner returns the side which won, and false for draws and unfinished games.
+func (o Outcome) Winner() (board.Side, bool) {
+	switch o {
+	case Outcome_SENTE_WIN:
+		return board.Sente, true
+	case Outcome_GOTE_WIN:
+		return board.Gote, true
+	default:
+		return board.Sente, false
+	}
+}
+
+func sideWins(side board.Side) Outcome {
+	if side == board.Sente {
+		return Outcome_SENTE_WIN
+	}
+	return Outcome_GOTE_WIN
+}
+
+// GetOutcome returns the result of the main line from its final step. The side
+// to move wins by FOUL_WIN and NYUGYOKU_WIN, and loses by the others. In
+// handicap games Gote (上手) moves first.
+func GetOutcome(k *ptypes.Kif) (Outcome, error) {
+	var last *ptypes.Step
+	for _, s := range k.GetSteps() {
+		if s.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			last = s
+			break
+		}
+	}
+	if last == nil {
+		return Outcome_UNFINISHED, nil
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return Outcome_UNFINISHED, err
+	}
+	turn := b.Turn
+	if (last.Seq-1)%2 == 1 {
+		turn = turn.Opponent()
+	}
+
+	switch last.FinishedStatus {
+	case ptypes.FinishedStatus_SUSPEND:
+		return Outcome_UNFINISHED, nil
+	case ptypes.FinishedStatus_DRAW, ptypes.FinishedStatus_REPETITION_DRAW:
+		return Outcome_DRAW, nil
+	case ptypes.FinishedStatus_FOUL_WIN, ptypes.FinishedStatus_NYUGYOKU_WIN:
+		return sideWins(turn), nil
+	default:
+		return sideWins(turn.Opponent()), nil
+	}
+}
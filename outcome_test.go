@@ -0,0 +1,44 @@
+package kif
+
+import (
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestGetOutcome(t *testing.T) {
+	finished := func(seq int32, status ptypes.FinishedStatus_Id, headers ...*ptypes.Header) *ptypes.Kif {
+		return &ptypes.Kif{
+			Headers: headers,
+			Steps:   []*ptypes.Step{{Seq: seq, FinishedStatus: status}},
+		}
+	}
+	handicap := &ptypes.Header{Name: "手合割", Value: "角落ち"}
+
+	tests := []struct {
+		name     string
+		k        *ptypes.Kif
+		expected Outcome
+	}{
+		{"no steps", &ptypes.Kif{}, Outcome_UNFINISHED},
+		{"sente resigns", finished(1, ptypes.FinishedStatus_SURRENDER), Outcome_GOTE_WIN},
+		{"gote resigns", finished(2, ptypes.FinishedStatus_SURRENDER), Outcome_SENTE_WIN},
+		{"gote checkmated", finished(4, ptypes.FinishedStatus_CHECKMATE), Outcome_SENTE_WIN},
+		{"foul win", finished(3, ptypes.FinishedStatus_FOUL_WIN), Outcome_SENTE_WIN},
+		{"nyugyoku", finished(4, ptypes.FinishedStatus_NYUGYOKU_WIN), Outcome_GOTE_WIN},
+		{"repetition", finished(9, ptypes.FinishedStatus_REPETITION_DRAW), Outcome_DRAW},
+		{"suspended", finished(9, ptypes.FinishedStatus_SUSPEND), Outcome_UNFINISHED},
+		{"handicap shitate resigns", finished(2, ptypes.FinishedStatus_SURRENDER, handicap), Outcome_GOTE_WIN},
+		{"handicap uwate resigns", finished(3, ptypes.FinishedStatus_SURRENDER, handicap), Outcome_SENTE_WIN},
+	}
+	for _, test := range tests {
+		o, err := GetOutcome(test.k)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+		if o != test.expected {
+			t.Errorf("%v: expected=%v actual=%v", test.name, test.expected, o)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package kif
 import (
 	"bufio"
 	"io"
+	"strconv"
 	"strings"
 
 	"golang.org/x/text/encoding/japanese"
@@ -62,17 +63,15 @@ func dropBOM(r *bufio.Reader) error {
 }
 
 type Parser struct {
-	transformReader func(io.Reader) io.Reader
+	transformReader  func(io.Reader) io.Reader
+	recomputeElapsed bool
 }
 
 type ParseOption func(*Parser)
 
-var (
-	sjisDecoder = japanese.ShiftJIS.NewDecoder()
-	sjisReader  = func(r io.Reader) io.Reader {
-		return transform.NewReader(r, sjisDecoder)
-	}
-)
+var sjisReader = func(r io.Reader) io.Reader {
+	return transform.NewReader(r, japanese.ShiftJIS.NewDecoder())
+}
 
 func ParseEncodingSJIS() ParseOption {
 	return func(p *Parser) {
@@ -88,6 +87,14 @@ func ParseEncodingUTF8() ParseOption {
 	}
 }
 
+// ParseRecomputeElapsed replaces the elapsed times in the file with
+// the totals of the thinking times.
+func ParseRecomputeElapsed() ParseOption {
+	return func(p *Parser) {
+		p.recomputeElapsed = true
+	}
+}
+
 func NewParser(ops ...ParseOption) *Parser {
 	p := &Parser{
 		transformReader: sjisReader,
@@ -99,6 +106,38 @@ func NewParser(ops ...ParseOption) *Parser {
 	return p
 }
 
+const variationPrefix = "変化："
+
+func parseVariationLine(line string) (int32, bool) {
+	if !strings.HasPrefix(line, variationPrefix) {
+		return 0, false
+	}
+	s := strings.TrimSpace(strings.TrimPrefix(line, variationPrefix))
+	s = strings.TrimSuffix(s, "手")
+	i, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(i), true
+}
+
+// findBranch returns the most recent line containing the step
+// the variation starting at seq branches from.
+func findBranch(lines []*[]*ptypes.Step, seq int32) (int, *ptypes.Step) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		steps := *lines[i]
+		if len(steps) == 0 || (i != 0 && steps[0].Seq >= seq) {
+			continue
+		}
+		for _, s := range steps {
+			if s.Seq == seq {
+				return i, s
+			}
+		}
+	}
+	return -1, nil
+}
+
 func (p *Parser) Parse(in io.Reader) (*ptypes.Kif, error) {
 	var count int
 	br := bufio.NewReader(p.transformReader(in))
@@ -138,7 +177,10 @@ func (p *Parser) Parse(in io.Reader) (*ptypes.Kif, error) {
 	}
 	r.Read()
 
+	lines := []*[]*ptypes.Step{&ret.Steps}
 	var prevStep *ptypes.Step
+	// notes before the first step of a line, which go to the step
+	var pending []string
 	for {
 		count++
 
@@ -153,8 +195,27 @@ func (p *Parser) Parse(in io.Reader) (*ptypes.Kif, error) {
 			continue
 		}
 
+		if seq, ok := parseVariationLine(line); ok {
+			if len(pending) != 0 {
+				return nil, errors.Errorf("line=%v comment without steps: %v", count, pending[0])
+			}
+			i, step := findBranch(lines, seq)
+			if step == nil {
+				return nil, errors.Errorf("line=%v branch not found: %v", count, line)
+			}
+			v := &ptypes.Variation{}
+			step.Variations = append(step.Variations, v)
+			lines = append(lines[:i+1], &v.Steps)
+			prevStep = nil
+			continue
+		}
+
 		if line[0] == '*' {
-			prevStep.Notes = append(prevStep.Notes, line[1:])
+			if prevStep == nil {
+				pending = append(pending, line[1:])
+			} else {
+				prevStep.Notes = append(prevStep.Notes, line[1:])
+			}
 			continue
 		}
 		if prevStep.GetFinishedStatus() != ptypes.FinishedStatus_NOT_FINISHED {
@@ -167,9 +228,20 @@ func (p *Parser) Parse(in io.Reader) (*ptypes.Kif, error) {
 			return nil, errors.Wrapf(err, "line=%v %v", count, line)
 		}
 
-		ret.Steps = append(ret.Steps, step)
+		step.Notes = append(pending, step.Notes...)
+		pending = nil
+
+		curr := lines[len(lines)-1]
+		*curr = append(*curr, step)
 		prevStep = step
 	}
+	if len(pending) != 0 {
+		return nil, errors.Errorf("line=%v comment without steps: %v", count, pending[0])
+	}
+
+	if p.recomputeElapsed {
+		RecomputeElapsed(ret)
+	}
 
 	return ret, nil
 }
@@ -1,5 +1,91 @@
 package kif
 
 import (
-	_ "testing"
+	"bytes"
+	"strings"
+	"testing"
 )
+
+const variationKIF = `手合割：平手
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ３四歩(33)   ( 0:00/00:00:00)+
+   3 ２六歩(27)   ( 0:00/00:00:00)+
+   4 投了   ( 0:00/00:00:00)
+
+変化：3手
+   3 ２二角成(88)   ( 0:00/00:00:00)
+   4 同　銀(31)   ( 0:00/00:00:00)+
+   5 ４五角打   ( 0:00/00:00:00)
+
+変化：4手
+   4 同　飛(82)   ( 0:00/00:00:00)
+
+変化：2手
+   2 ８四歩(83)   ( 0:00/00:00:00)
+`
+
+func TestParser_Parse_variations(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(variationKIF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if l := len(k.Steps); l != 4 {
+		t.Fatalf("main line: expected=4 actual=%v", l)
+	}
+	if l := len(k.Steps[1].Variations); l != 1 {
+		t.Fatalf("variations of 2: expected=1 actual=%v", l)
+	}
+	v3 := k.Steps[2].Variations
+	if len(v3) != 1 || len(v3[0].Steps) != 3 {
+		t.Fatalf("unexpected variations of 3: %v", v3)
+	}
+	v4 := v3[0].Steps[1].Variations
+	if len(v4) != 1 || v4[0].Steps[0].Src.X != 8 {
+		t.Fatalf("unexpected variations of 4: %v", v4)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(WriteEncodingUTF8()).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	i3, i4, i2 := strings.Index(out, "変化：3手"), strings.Index(out, "変化：4手"), strings.Index(out, "変化：2手")
+	if !(0 < i3 && i3 < i4 && i4 < i2) {
+		t.Errorf("unexpected order of variations:\n%s", out)
+	}
+}
+
+func TestParser_Parse_variationComment(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(`手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)+
+
+変化：1手
+*note
+   1 ２六歩(27)   ( 0:00/00:00:00)
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v := k.Steps[0].Variations
+	if len(v) != 1 || len(v[0].Steps) != 1 {
+		t.Fatalf("variations of 1: %v", v)
+	}
+	if n := v[0].Steps[0].Notes; len(n) != 1 || n[0] != "note" {
+		t.Errorf("notes: %v", n)
+	}
+	if n := k.Steps[0].Notes; len(n) != 0 {
+		t.Errorf("notes of the main line: %v", n)
+	}
+
+	_, err = NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(`手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)+
+
+変化：1手
+*note
+`))
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -2,6 +2,8 @@ package kif
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/yunomu/kif/ptypes"
@@ -114,29 +116,39 @@ func PrintMove(s *ptypes.Step) string {
 		return PrintPhase(s) + PrintFinishedStatus(s.FinishedStatus)
 	}
 
-	var src string
-	if s.Src != nil {
-		src = fmt.Sprintf("(%d%d)", s.Src.X, s.Src.Y)
-	}
-
 	return fmt.Sprintf("%s%s%s%s%s",
 		PrintPhase(s),
 		PrintPos(s.Dst),
 		PrintPiece(s.Piece),
 		PrintModifier(s.Modifier),
-		src,
+		printSrc(s.Src),
 	)
 }
 
+// printSrc returns the source square of a move in parentheses, or an empty
+// string for drops.
+func printSrc(p *ptypes.Pos) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("(%d%d)", p.X, p.Y)
+}
+
 func PrintThinking(sec int32) string {
+	if sec < 0 {
+		sec = 0
+	}
 	m := sec / 60
 	s := sec % 60
 	return fmt.Sprintf("%2d:%02d", m, s)
 }
 
 func PrintElapsed(sec int32) string {
+	if sec < 0 {
+		sec = 0
+	}
 	h := sec / 3600
-	m := sec / 60
+	m := sec / 60 % 60
 	s := sec % 60
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
 }
@@ -145,6 +157,7 @@ const (
 	timeFormat    = "2006/01/02 15:04:05"
 	startTimeName = "開始日時"
 	endTimeName   = "終了日時"
+	dateName      = "対局日"
 )
 
 func setTime(k *ptypes.Kif, name string, t time.Time) {
@@ -172,3 +185,45 @@ func SetStartTime(k *ptypes.Kif, t time.Time) {
 func SetEndTime(k *ptypes.Kif, t time.Time) {
 	setTime(k, endTimeName, t)
 }
+
+var (
+	weekdayRe   = regexp.MustCompile(`\s*[(（][^)）]*[)）]\s*`)
+	timeFormats = []string{
+		timeFormat,
+		"2006/01/02 15:04",
+		"2006/01/02",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"2006年01月02日 15:04:05",
+		"2006年01月02日",
+	}
+)
+
+// StartTime returns the time of 開始日時, or the date of 対局日 if the game has
+// no 開始日時. The day of the week in parentheses is ignored.
+func StartTime(k *ptypes.Kif) (time.Time, error) {
+	var value string
+	for _, name := range []string{startTimeName, dateName} {
+		for _, h := range k.GetHeaders() {
+			if h.Name == name && strings.TrimSpace(h.Value) != "" {
+				value = h.Value
+				break
+			}
+		}
+		if value != "" {
+			break
+		}
+	}
+	if value == "" {
+		return time.Time{}, fmt.Errorf("no %s", startTimeName)
+	}
+
+	v := strings.TrimSpace(weekdayRe.ReplaceAllString(value, " "))
+	for _, f := range timeFormats {
+		if t, err := time.ParseInLocation(f, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s: %q", startTimeName, value)
+}
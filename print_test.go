@@ -58,3 +58,46 @@ func TestKif_SetStartTime(t *testing.T) {
 		t.Errorf("header num: expected=3 actual=%d", len(kif.Headers))
 	}
 }
+
+func TestStartTime(t *testing.T) {
+	for _, c := range []struct {
+		headers  []*ptypes.Header
+		expected string
+	}{
+		{[]*ptypes.Header{{Name: "開始日時", Value: "2020/01/02 03:04:05"}}, "2020-01-02 03:04:05"},
+		{[]*ptypes.Header{{Name: "開始日時", Value: "2019/07/27(土) 10:00"}}, "2019-07-27 10:00:00"},
+		{[]*ptypes.Header{{Name: "対局日", Value: "2019年07月27日（土）"}}, "2019-07-27 00:00:00"},
+	} {
+		tm, err := StartTime(&ptypes.Kif{Headers: c.headers})
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.headers, err)
+			continue
+		}
+		if s := tm.Format("2006-01-02 15:04:05"); s != c.expected {
+			t.Errorf("expected=%v actual=%v", c.expected, s)
+		}
+	}
+
+	if _, err := StartTime(&ptypes.Kif{Headers: []*ptypes.Header{{Name: "開始日時", Value: "yesterday"}}}); err == nil {
+		t.Errorf("expected error")
+	}
+	if _, err := StartTime(&ptypes.Kif{}); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestPrintElapsed(t *testing.T) {
+	for _, c := range []struct {
+		sec      int32
+		expected string
+	}{
+		{0, "00:00:00"},
+		{3600 + 5*60, "01:05:00"},
+		{100*3600 + 59*60 + 59, "100:59:59"},
+		{-1, "00:00:00"},
+	} {
+		if a := PrintElapsed(c.sec); a != c.expected {
+			t.Errorf("sec=%v expected=%v actual=%v", c.sec, c.expected, a)
+		}
+	}
+}
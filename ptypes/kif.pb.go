@@ -349,18 +349,23 @@ func (m *Modifier) XXX_DiscardUnknown() {
 var xxx_messageInfo_Modifier proto.InternalMessageInfo
 
 type Step struct {
-	Seq                  int32             `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
-	Dst                  *Pos              `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
-	FinishedStatus       FinishedStatus_Id `protobuf:"varint,3,opt,name=finished_status,json=finishedStatus,proto3,enum=yunomu.kif.FinishedStatus_Id" json:"finished_status,omitempty"`
-	Piece                Piece_Id          `protobuf:"varint,4,opt,name=piece,proto3,enum=yunomu.kif.Piece_Id" json:"piece,omitempty"`
-	Modifier             Modifier_Id       `protobuf:"varint,5,opt,name=modifier,proto3,enum=yunomu.kif.Modifier_Id" json:"modifier,omitempty"`
-	Src                  *Pos              `protobuf:"bytes,6,opt,name=src,proto3" json:"src,omitempty"`
-	ThinkingSec          int32             `protobuf:"varint,7,opt,name=thinking_sec,json=thinkingSec,proto3" json:"thinking_sec,omitempty"`
-	ElapsedSec           int32             `protobuf:"varint,8,opt,name=elapsed_sec,json=elapsedSec,proto3" json:"elapsed_sec,omitempty"`
-	Notes                []string          `protobuf:"bytes,9,rep,name=notes,proto3" json:"notes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Seq            int32             `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Dst            *Pos              `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
+	FinishedStatus FinishedStatus_Id `protobuf:"varint,3,opt,name=finished_status,json=finishedStatus,proto3,enum=yunomu.kif.FinishedStatus_Id" json:"finished_status,omitempty"`
+	Piece          Piece_Id          `protobuf:"varint,4,opt,name=piece,proto3,enum=yunomu.kif.Piece_Id" json:"piece,omitempty"`
+	Modifier       Modifier_Id       `protobuf:"varint,5,opt,name=modifier,proto3,enum=yunomu.kif.Modifier_Id" json:"modifier,omitempty"`
+	Src            *Pos              `protobuf:"bytes,6,opt,name=src,proto3" json:"src,omitempty"`
+	ThinkingSec    int32             `protobuf:"varint,7,opt,name=thinking_sec,json=thinkingSec,proto3" json:"thinking_sec,omitempty"`
+	ElapsedSec     int32             `protobuf:"varint,8,opt,name=elapsed_sec,json=elapsedSec,proto3" json:"elapsed_sec,omitempty"`
+	Notes          []string          `protobuf:"bytes,9,rep,name=notes,proto3" json:"notes,omitempty"`
+	Variations     []*Variation      `protobuf:"bytes,10,rep,name=variations,proto3" json:"variations,omitempty"`
+	// sub-second parts of thinking_sec and elapsed_sec
+	ThinkingNanos        int32       `protobuf:"varint,11,opt,name=thinking_nanos,json=thinkingNanos,proto3" json:"thinking_nanos,omitempty"`
+	ElapsedNanos         int32       `protobuf:"varint,12,opt,name=elapsed_nanos,json=elapsedNanos,proto3" json:"elapsed_nanos,omitempty"`
+	Evaluation           *Evaluation `protobuf:"bytes,13,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *Step) Reset()         { *m = Step{} }
@@ -451,6 +456,147 @@ func (m *Step) GetNotes() []string {
 	return nil
 }
 
+func (m *Step) GetVariations() []*Variation {
+	if m != nil {
+		return m.Variations
+	}
+	return nil
+}
+
+func (m *Step) GetThinkingNanos() int32 {
+	if m != nil {
+		return m.ThinkingNanos
+	}
+	return 0
+}
+
+func (m *Step) GetElapsedNanos() int32 {
+	if m != nil {
+		return m.ElapsedNanos
+	}
+	return 0
+}
+
+func (m *Step) GetEvaluation() *Evaluation {
+	if m != nil {
+		return m.Evaluation
+	}
+	return nil
+}
+
+// Engine evaluation of the position after the step, from Sente's point of view.
+type Evaluation struct {
+	ScoreCp int32 `protobuf:"varint,1,opt,name=score_cp,json=scoreCp,proto3" json:"score_cp,omitempty"`
+	// plies to mate, negative when Sente is mated
+	Mate   int32 `protobuf:"varint,2,opt,name=mate,proto3" json:"mate,omitempty"`
+	IsMate bool  `protobuf:"varint,3,opt,name=is_mate,json=isMate,proto3" json:"is_mate,omitempty"`
+	Depth  int32 `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`
+	// principal variation in USI notation
+	Pv                   []string `protobuf:"bytes,5,rep,name=pv,proto3" json:"pv,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Evaluation) Reset()         { *m = Evaluation{} }
+func (m *Evaluation) String() string { return proto.CompactTextString(m) }
+func (*Evaluation) ProtoMessage()    {}
+func (*Evaluation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4b6a2a381ab6f000, []int{6}
+}
+
+func (m *Evaluation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Evaluation.Unmarshal(m, b)
+}
+func (m *Evaluation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Evaluation.Marshal(b, m, deterministic)
+}
+func (m *Evaluation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Evaluation.Merge(m, src)
+}
+func (m *Evaluation) XXX_Size() int {
+	return xxx_messageInfo_Evaluation.Size(m)
+}
+func (m *Evaluation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Evaluation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Evaluation proto.InternalMessageInfo
+
+func (m *Evaluation) GetScoreCp() int32 {
+	if m != nil {
+		return m.ScoreCp
+	}
+	return 0
+}
+
+func (m *Evaluation) GetMate() int32 {
+	if m != nil {
+		return m.Mate
+	}
+	return 0
+}
+
+func (m *Evaluation) GetIsMate() bool {
+	if m != nil {
+		return m.IsMate
+	}
+	return false
+}
+
+func (m *Evaluation) GetDepth() int32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *Evaluation) GetPv() []string {
+	if m != nil {
+		return m.Pv
+	}
+	return nil
+}
+
+type Variation struct {
+	Steps                []*Step  `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Variation) Reset()         { *m = Variation{} }
+func (m *Variation) String() string { return proto.CompactTextString(m) }
+func (*Variation) ProtoMessage()    {}
+func (*Variation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4b6a2a381ab6f000, []int{7}
+}
+
+func (m *Variation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Variation.Unmarshal(m, b)
+}
+func (m *Variation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Variation.Marshal(b, m, deterministic)
+}
+func (m *Variation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Variation.Merge(m, src)
+}
+func (m *Variation) XXX_Size() int {
+	return xxx_messageInfo_Variation.Size(m)
+}
+func (m *Variation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Variation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Variation proto.InternalMessageInfo
+
+func (m *Variation) GetSteps() []*Step {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
 type Kif struct {
 	Headers              []*Header `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
 	Steps                []*Step   `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"`
@@ -463,7 +609,7 @@ func (m *Kif) Reset()         { *m = Kif{} }
 func (m *Kif) String() string { return proto.CompactTextString(m) }
 func (*Kif) ProtoMessage()    {}
 func (*Kif) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4b6a2a381ab6f000, []int{6}
+	return fileDescriptor_4b6a2a381ab6f000, []int{8}
 }
 
 func (m *Kif) XXX_Unmarshal(b []byte) error {
@@ -508,51 +654,63 @@ func init() {
 	proto.RegisterType((*Piece)(nil), "yunomu.kif.Piece")
 	proto.RegisterType((*Modifier)(nil), "yunomu.kif.Modifier")
 	proto.RegisterType((*Step)(nil), "yunomu.kif.Step")
+	proto.RegisterType((*Evaluation)(nil), "yunomu.kif.Evaluation")
+	proto.RegisterType((*Variation)(nil), "yunomu.kif.Variation")
 	proto.RegisterType((*Kif)(nil), "yunomu.kif.Kif")
 }
 
 func init() { proto.RegisterFile("ptypes/kif.proto", fileDescriptor_4b6a2a381ab6f000) }
 
 var fileDescriptor_4b6a2a381ab6f000 = []byte{
-	// 635 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0xdf, 0x6e, 0xda, 0x4a,
-	0x10, 0xc6, 0xe3, 0xbf, 0x98, 0x31, 0x21, 0xab, 0x3d, 0x91, 0x8e, 0x6f, 0x8e, 0x0e, 0xf1, 0x45,
-	0x1b, 0x55, 0x15, 0x95, 0xc8, 0x13, 0xd0, 0xb0, 0x84, 0x15, 0x60, 0x5b, 0x6b, 0xbb, 0x11, 0xed,
-	0x85, 0x45, 0x61, 0x69, 0xac, 0x24, 0xd8, 0x65, 0x4d, 0x15, 0xde, 0xa6, 0x17, 0xbd, 0xea, 0x0b,
-	0xf4, 0x79, 0xfa, 0x26, 0xd5, 0xae, 0x49, 0x4a, 0x2a, 0xf5, 0x8a, 0x99, 0x6f, 0x7e, 0xcb, 0xce,
-	0xec, 0xe7, 0x01, 0x54, 0x56, 0xbb, 0x92, 0x8b, 0x37, 0xb7, 0xf9, 0xaa, 0x5b, 0x6e, 0x8a, 0xaa,
-	0xc0, 0xb0, 0xdb, 0xae, 0x8b, 0xfb, 0x6d, 0xf7, 0x36, 0x5f, 0xf9, 0x3d, 0xb0, 0x47, 0x7c, 0xbe,
-	0xe4, 0x1b, 0x8c, 0xc1, 0x5c, 0xcf, 0xef, 0xb9, 0xa7, 0x75, 0xb4, 0xf3, 0x26, 0x53, 0x31, 0x3e,
-	0x05, 0xeb, 0xcb, 0xfc, 0x6e, 0xcb, 0x3d, 0x5d, 0x89, 0x75, 0xe2, 0x9f, 0x81, 0x11, 0x15, 0x02,
-	0xb7, 0x40, 0x7b, 0x50, 0xb4, 0xc5, 0xb4, 0x07, 0x99, 0xed, 0x14, 0x66, 0x31, 0x6d, 0xe7, 0xff,
-	0xd0, 0xa0, 0x3d, 0xcc, 0xd7, 0xb9, 0xb8, 0xe1, 0xcb, 0xb8, 0x9a, 0x57, 0x5b, 0xe1, 0x7f, 0xd3,
-	0x40, 0xa7, 0x4b, 0x8c, 0xa0, 0x15, 0x84, 0x49, 0x36, 0xa4, 0x01, 0x8d, 0x47, 0x64, 0x80, 0x8e,
-	0xb0, 0x0b, 0x8d, 0x38, 0x8d, 0x23, 0x12, 0x0c, 0x90, 0x86, 0x8f, 0xa1, 0x19, 0xa7, 0x8c, 0x91,
-	0x60, 0x40, 0x18, 0xd2, 0xb1, 0x03, 0xe6, 0x80, 0xf5, 0xaf, 0x91, 0x81, 0xff, 0x81, 0x13, 0x46,
-	0x22, 0x92, 0xd0, 0x84, 0x86, 0x41, 0xa6, 0x44, 0x53, 0xd2, 0x97, 0x23, 0x72, 0x39, 0x9e, 0xf6,
-	0x13, 0x82, 0x2c, 0xc9, 0x84, 0xef, 0x08, 0xcb, 0x12, 0x3a, 0x25, 0xd9, 0x84, 0x4e, 0x69, 0x82,
-	0x6c, 0xc9, 0x0c, 0xc3, 0x74, 0x92, 0x4d, 0xc2, 0x38, 0x46, 0x0d, 0xdc, 0x02, 0x47, 0xa5, 0xd7,
-	0x34, 0x40, 0x8e, 0xea, 0x66, 0x96, 0x5e, 0xcd, 0xc2, 0x71, 0xaa, 0x94, 0xa6, 0xff, 0x5d, 0x03,
-	0x2b, 0xca, 0xf9, 0x82, 0xfb, 0x5f, 0xeb, 0x86, 0x1d, 0x30, 0x83, 0x74, 0x32, 0x41, 0x47, 0xb8,
-	0x09, 0x96, 0x22, 0x91, 0x26, 0xc3, 0x11, 0x8d, 0x47, 0x7d, 0xa4, 0xe3, 0x06, 0x18, 0x6c, 0x96,
-	0x22, 0x43, 0x82, 0xe3, 0xfe, 0x38, 0x45, 0xa6, 0x94, 0xd2, 0x69, 0x1f, 0x59, 0x32, 0x18, 0xd3,
-	0x00, 0xd9, 0x32, 0xb8, 0xa2, 0x41, 0x7d, 0x7d, 0xd0, 0x67, 0x34, 0xbb, 0x52, 0xd7, 0xcb, 0x3a,
-	0xa1, 0xa8, 0xf9, 0x24, 0xcb, 0x0c, 0xd4, 0x3f, 0xcd, 0xc2, 0x14, 0xb9, 0xb2, 0xf9, 0x5a, 0x97,
-	0x69, 0x0b, 0xdb, 0xa0, 0x0f, 0x53, 0x74, 0x2c, 0x7f, 0x93, 0x10, 0xb5, 0xfd, 0x0b, 0x70, 0xa6,
-	0xc5, 0x32, 0x5f, 0xe5, 0x7c, 0xe3, 0xbf, 0xfc, 0xa3, 0x5b, 0x17, 0x1a, 0x11, 0x0b, 0xa7, 0x61,
-	0x42, 0x90, 0x86, 0x01, 0xec, 0x28, 0x4d, 0x12, 0x32, 0x40, 0xba, 0xff, 0x53, 0x07, 0x33, 0xae,
-	0x78, 0x89, 0x11, 0x18, 0x82, 0x7f, 0xde, 0x5b, 0x28, 0x43, 0x7c, 0x06, 0xc6, 0x52, 0x54, 0xca,
-	0x46, 0xb7, 0x77, 0xd2, 0xfd, 0xfd, 0x9d, 0x74, 0xa3, 0x42, 0x30, 0x59, 0xc3, 0x43, 0x38, 0x59,
-	0xed, 0x8d, 0xcd, 0x84, 0x72, 0xd6, 0x33, 0x3a, 0xda, 0x79, 0xbb, 0xf7, 0xdf, 0x21, 0xfe, 0xdc,
-	0xfb, 0x2e, 0x5d, 0xb2, 0xf6, 0xea, 0x99, 0x84, 0x5f, 0x81, 0x55, 0xca, 0x67, 0xf6, 0x4c, 0x75,
-	0xfa, 0xf4, 0xd9, 0x65, 0xb2, 0x20, 0x0f, 0xd5, 0x08, 0xbe, 0x00, 0xe7, 0x7e, 0x3f, 0xa6, 0x67,
-	0x29, 0xfc, 0xdf, 0x43, 0xfc, 0xf1, 0x09, 0xe4, 0x89, 0x27, 0x50, 0xce, 0x22, 0x36, 0x0b, 0xcf,
-	0xfe, 0xcb, 0x2c, 0x62, 0xb3, 0xc0, 0x67, 0xd0, 0xaa, 0x6e, 0xf2, 0xf5, 0x6d, 0xbe, 0xfe, 0x94,
-	0x09, 0xbe, 0xf0, 0x1a, 0xea, 0x25, 0xdc, 0x47, 0x2d, 0xe6, 0x0b, 0xfc, 0x3f, 0xb8, 0xfc, 0x6e,
-	0x5e, 0x0a, 0x39, 0x2d, 0x5f, 0x78, 0x8e, 0x22, 0x60, 0x2f, 0x49, 0xe0, 0x14, 0xac, 0x75, 0x51,
-	0x71, 0xe1, 0x35, 0x3b, 0x86, 0x5c, 0x11, 0x95, 0xf8, 0x1f, 0xc0, 0x18, 0xe7, 0x2b, 0xfc, 0x1a,
-	0x1a, 0x37, 0x6a, 0xbb, 0x84, 0xa7, 0x75, 0x8c, 0x73, 0xb7, 0x87, 0x0f, 0xfb, 0xa8, 0x17, 0x8f,
-	0x3d, 0x22, 0xf8, 0x05, 0x58, 0xa2, 0xe2, 0xa5, 0xf0, 0x74, 0xc5, 0xa2, 0x43, 0x56, 0x1a, 0xc6,
-	0xea, 0xf2, 0x5b, 0xe7, 0xbd, 0x5d, 0xef, 0xf4, 0x47, 0x5b, 0x2d, 0xf4, 0xc5, 0xaf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xbf, 0xba, 0x15, 0x9e, 0xe4, 0x03, 0x00, 0x00,
+	// 785 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0xcd, 0x8e, 0xe3, 0x44,
+	0x10, 0xc7, 0xd7, 0xdf, 0x4e, 0x39, 0xc9, 0xb4, 0x9a, 0x81, 0x35, 0x07, 0xc4, 0x8c, 0x11, 0x30,
+	0x42, 0x28, 0x48, 0x33, 0x82, 0x7b, 0x98, 0x38, 0x13, 0x2b, 0x89, 0x1d, 0xb5, 0xed, 0x5d, 0x0d,
+	0x1c, 0x2c, 0xe3, 0x74, 0x88, 0x35, 0x1b, 0xdb, 0xa4, 0x9d, 0x68, 0xc3, 0xb3, 0x70, 0xe0, 0xc0,
+	0x89, 0x17, 0xe0, 0xf5, 0x50, 0xb7, 0x93, 0x8c, 0x07, 0x89, 0x3d, 0xb9, 0xea, 0x5f, 0xbf, 0xee,
+	0xae, 0xae, 0x6a, 0x17, 0xa0, 0xaa, 0x3e, 0x54, 0x94, 0x7d, 0xf7, 0x94, 0xaf, 0x06, 0xd5, 0xb6,
+	0xac, 0x4b, 0x0c, 0x87, 0x5d, 0x51, 0x6e, 0x76, 0x83, 0xa7, 0x7c, 0xe5, 0xdc, 0x82, 0x3e, 0xa1,
+	0xe9, 0x92, 0x6e, 0x31, 0x06, 0xb5, 0x48, 0x37, 0xd4, 0x96, 0xae, 0xa4, 0x9b, 0x0e, 0x11, 0x36,
+	0xbe, 0x04, 0x6d, 0x9f, 0xbe, 0xdb, 0x51, 0x5b, 0x16, 0x62, 0xe3, 0x38, 0xd7, 0xa0, 0x2c, 0x4a,
+	0x86, 0xbb, 0x20, 0xbd, 0x17, 0xb4, 0x46, 0xa4, 0xf7, 0xdc, 0x3b, 0x08, 0x4c, 0x23, 0xd2, 0xc1,
+	0xf9, 0x47, 0x82, 0xfe, 0x38, 0x2f, 0x72, 0xb6, 0xa6, 0xcb, 0xb0, 0x4e, 0xeb, 0x1d, 0x73, 0xfe,
+	0x92, 0x40, 0xf6, 0x96, 0x18, 0x41, 0xd7, 0x0f, 0xa2, 0x64, 0xec, 0xf9, 0x5e, 0x38, 0x71, 0x47,
+	0xe8, 0x15, 0xb6, 0xc0, 0x08, 0xe3, 0x70, 0xe1, 0xfa, 0x23, 0x24, 0xe1, 0x1e, 0x74, 0xc2, 0x98,
+	0x10, 0xd7, 0x1f, 0xb9, 0x04, 0xc9, 0xd8, 0x04, 0x75, 0x44, 0x86, 0x6f, 0x91, 0x82, 0x3f, 0x82,
+	0x0b, 0xe2, 0x2e, 0xdc, 0xc8, 0x8b, 0xbc, 0xc0, 0x4f, 0x84, 0xa8, 0x72, 0xfa, 0x7e, 0xe2, 0xde,
+	0x4f, 0xe7, 0xc3, 0xc8, 0x45, 0x1a, 0x67, 0x82, 0x37, 0x2e, 0x49, 0x22, 0x6f, 0xee, 0x26, 0x33,
+	0x6f, 0xee, 0x45, 0x48, 0xe7, 0xcc, 0x38, 0x88, 0x67, 0xc9, 0x2c, 0x08, 0x43, 0x64, 0xe0, 0x2e,
+	0x98, 0xc2, 0x7d, 0xeb, 0xf9, 0xc8, 0x14, 0xd9, 0x3c, 0xc6, 0x0f, 0x8f, 0xc1, 0x34, 0x16, 0x4a,
+	0xc7, 0xf9, 0x5b, 0x02, 0x6d, 0x91, 0xd3, 0x8c, 0x3a, 0x7f, 0x36, 0x09, 0x9b, 0xa0, 0xfa, 0xf1,
+	0x6c, 0x86, 0x5e, 0xe1, 0x0e, 0x68, 0x82, 0x44, 0x12, 0x37, 0x27, 0x5e, 0x38, 0x19, 0x22, 0x19,
+	0x1b, 0xa0, 0x90, 0xc7, 0x18, 0x29, 0x1c, 0x9c, 0x0e, 0xa7, 0x31, 0x52, 0xb9, 0x14, 0xcf, 0x87,
+	0x48, 0xe3, 0xc6, 0xd4, 0xf3, 0x91, 0xce, 0x8d, 0x07, 0xcf, 0x6f, 0x8e, 0xf7, 0x87, 0xc4, 0x4b,
+	0x1e, 0xc4, 0xf1, 0x3c, 0xee, 0x7a, 0xa8, 0x73, 0x96, 0xb9, 0x07, 0x62, 0xa7, 0xc7, 0x20, 0x46,
+	0x16, 0x4f, 0xbe, 0xd1, 0xb9, 0xdb, 0xc5, 0x3a, 0xc8, 0xe3, 0x18, 0xf5, 0xf8, 0x37, 0x0a, 0x50,
+	0xdf, 0xb9, 0x03, 0x73, 0x5e, 0x2e, 0xf3, 0x55, 0x4e, 0xb7, 0xce, 0xd7, 0xff, 0xc9, 0xd6, 0x02,
+	0x63, 0x41, 0x82, 0x79, 0x10, 0xb9, 0x48, 0xc2, 0x00, 0xfa, 0x22, 0x8e, 0x22, 0x77, 0x84, 0x64,
+	0xe7, 0x0f, 0x15, 0xd4, 0xb0, 0xa6, 0x15, 0x46, 0xa0, 0x30, 0xfa, 0xdb, 0xb1, 0x85, 0xdc, 0xc4,
+	0xd7, 0xa0, 0x2c, 0x59, 0x2d, 0xda, 0x68, 0xdd, 0x5e, 0x0c, 0x9e, 0xdf, 0xc9, 0x60, 0x51, 0x32,
+	0xc2, 0x63, 0x78, 0x0c, 0x17, 0xab, 0x63, 0x63, 0x13, 0x26, 0x3a, 0x6b, 0x2b, 0x57, 0xd2, 0x4d,
+	0xff, 0xf6, 0xb3, 0x36, 0xfe, 0xb2, 0xf7, 0x03, 0x6f, 0x49, 0xfa, 0xab, 0x17, 0x12, 0xfe, 0x06,
+	0xb4, 0x8a, 0x97, 0xd9, 0x56, 0xc5, 0xea, 0xcb, 0x17, 0x87, 0xf1, 0x00, 0x5f, 0xd4, 0x20, 0xf8,
+	0x0e, 0xcc, 0xcd, 0xf1, 0x9a, 0xb6, 0x26, 0xf0, 0xd7, 0x6d, 0xfc, 0x54, 0x02, 0xbe, 0xe2, 0x0c,
+	0xf2, 0xbb, 0xb0, 0x6d, 0x66, 0xeb, 0xff, 0x73, 0x17, 0xb6, 0xcd, 0xf0, 0x35, 0x74, 0xeb, 0x75,
+	0x5e, 0x3c, 0xe5, 0xc5, 0xaf, 0x09, 0xa3, 0x99, 0x6d, 0x88, 0x4a, 0x58, 0x27, 0x2d, 0xa4, 0x19,
+	0xfe, 0x1c, 0x2c, 0xfa, 0x2e, 0xad, 0x18, 0xbf, 0x2d, 0xcd, 0x6c, 0x53, 0x10, 0x70, 0x94, 0x38,
+	0x70, 0x09, 0x5a, 0x51, 0xd6, 0x94, 0xd9, 0x9d, 0x2b, 0x85, 0xff, 0x22, 0xc2, 0xc1, 0xdf, 0x03,
+	0xec, 0xd3, 0x6d, 0x9e, 0xd6, 0x79, 0x59, 0x30, 0x1b, 0xae, 0x94, 0x1b, 0xeb, 0xf6, 0xe3, 0x76,
+	0x0e, 0x6f, 0x4e, 0x51, 0xd2, 0x02, 0xf1, 0x97, 0xd0, 0x3f, 0x27, 0x54, 0xa4, 0x45, 0xc9, 0x6c,
+	0x4b, 0x1c, 0xd8, 0x3b, 0xa9, 0x3e, 0x17, 0xf1, 0x17, 0xd0, 0x3b, 0x25, 0xd5, 0x50, 0x5d, 0x41,
+	0x75, 0x8f, 0x62, 0x03, 0xfd, 0x00, 0x40, 0xf9, 0xff, 0x2a, 0xb6, 0xb6, 0x7b, 0xa2, 0x0c, 0x9f,
+	0xb4, 0x53, 0x70, 0xcf, 0x51, 0xd2, 0x22, 0x9d, 0xdf, 0x01, 0x9e, 0x23, 0xf8, 0x53, 0x30, 0x59,
+	0x56, 0x6e, 0x69, 0x92, 0x55, 0xc7, 0x87, 0x62, 0x08, 0xff, 0xbe, 0xe2, 0x03, 0x63, 0x93, 0xd6,
+	0xf4, 0xf8, 0xd3, 0x0b, 0x1b, 0xbf, 0x06, 0x23, 0x67, 0x89, 0x90, 0xf9, 0xab, 0x30, 0x89, 0x9e,
+	0xb3, 0x39, 0x0f, 0x5c, 0x82, 0xb6, 0xa4, 0x55, 0xbd, 0x16, 0xed, 0xd6, 0x48, 0xe3, 0xe0, 0x3e,
+	0xc8, 0xd5, 0xde, 0xd6, 0x44, 0xe5, 0xe4, 0x6a, 0xef, 0xdc, 0x41, 0xe7, 0x5c, 0x18, 0xfc, 0x15,
+	0x68, 0xac, 0xa6, 0x15, 0xb3, 0x25, 0x51, 0x3e, 0xd4, 0xce, 0x9d, 0xbf, 0x5f, 0xd2, 0x84, 0x9d,
+	0x9f, 0x41, 0x99, 0xe6, 0x2b, 0xfc, 0x2d, 0x18, 0x6b, 0x31, 0xc9, 0x4e, 0x0b, 0x70, 0x7b, 0x41,
+	0x33, 0xe4, 0xc8, 0x09, 0x79, 0xde, 0x5c, 0xfe, 0xe0, 0xe6, 0x3f, 0x9a, 0x3f, 0xe9, 0xcd, 0xfc,
+	0xfc, 0x45, 0x17, 0xc3, 0xf3, 0xee, 0xdf, 0x01, 0x00, 0x57, 0xb7, 0x16, 0x8a, 0x50, 0x05, 0x00,
+	0x00,
 }
@@ -0,0 +1,946 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ptypes/service.proto
+
+package ptypes
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Format_Id int32
+
+const (
+	Format_KIF  Format_Id = 0
+	Format_CSA  Format_Id = 1
+	Format_JKF  Format_Id = 2
+	Format_SFEN Format_Id = 3
+	Format_HTML Format_Id = 4
+)
+
+var Format_Id_name = map[int32]string{
+	0: "KIF",
+	1: "CSA",
+	2: "JKF",
+	3: "SFEN",
+	4: "HTML",
+}
+
+var Format_Id_value = map[string]int32{
+	"KIF":  0,
+	"CSA":  1,
+	"JKF":  2,
+	"SFEN": 3,
+	"HTML": 4,
+}
+
+func (x Format_Id) String() string {
+	return proto.EnumName(Format_Id_name, int32(x))
+}
+
+func (Format_Id) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{0, 0}
+}
+
+type Encoding_Id int32
+
+const (
+	Encoding_SHIFT_JIS Encoding_Id = 0
+	Encoding_UTF8      Encoding_Id = 1
+)
+
+var Encoding_Id_name = map[int32]string{
+	0: "SHIFT_JIS",
+	1: "UTF8",
+}
+
+var Encoding_Id_value = map[string]int32{
+	"SHIFT_JIS": 0,
+	"UTF8":      1,
+}
+
+func (x Encoding_Id) String() string {
+	return proto.EnumName(Encoding_Id_name, int32(x))
+}
+
+func (Encoding_Id) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{1, 0}
+}
+
+type ImageFormat_Id int32
+
+const (
+	ImageFormat_SVG  ImageFormat_Id = 0
+	ImageFormat_PNG  ImageFormat_Id = 1
+	ImageFormat_TEXT ImageFormat_Id = 2
+)
+
+var ImageFormat_Id_name = map[int32]string{
+	0: "SVG",
+	1: "PNG",
+	2: "TEXT",
+}
+
+var ImageFormat_Id_value = map[string]int32{
+	"SVG":  0,
+	"PNG":  1,
+	"TEXT": 2,
+}
+
+func (x ImageFormat_Id) String() string {
+	return proto.EnumName(ImageFormat_Id_name, int32(x))
+}
+
+func (ImageFormat_Id) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{2, 0}
+}
+
+type Format struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Format) Reset()         { *m = Format{} }
+func (m *Format) String() string { return proto.CompactTextString(m) }
+func (*Format) ProtoMessage()    {}
+func (*Format) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{0}
+}
+
+func (m *Format) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Format.Unmarshal(m, b)
+}
+func (m *Format) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Format.Marshal(b, m, deterministic)
+}
+func (m *Format) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Format.Merge(m, src)
+}
+func (m *Format) XXX_Size() int {
+	return xxx_messageInfo_Format.Size(m)
+}
+func (m *Format) XXX_DiscardUnknown() {
+	xxx_messageInfo_Format.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Format proto.InternalMessageInfo
+
+type Encoding struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Encoding) Reset()         { *m = Encoding{} }
+func (m *Encoding) String() string { return proto.CompactTextString(m) }
+func (*Encoding) ProtoMessage()    {}
+func (*Encoding) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{1}
+}
+
+func (m *Encoding) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Encoding.Unmarshal(m, b)
+}
+func (m *Encoding) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Encoding.Marshal(b, m, deterministic)
+}
+func (m *Encoding) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Encoding.Merge(m, src)
+}
+func (m *Encoding) XXX_Size() int {
+	return xxx_messageInfo_Encoding.Size(m)
+}
+func (m *Encoding) XXX_DiscardUnknown() {
+	xxx_messageInfo_Encoding.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Encoding proto.InternalMessageInfo
+
+type ImageFormat struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImageFormat) Reset()         { *m = ImageFormat{} }
+func (m *ImageFormat) String() string { return proto.CompactTextString(m) }
+func (*ImageFormat) ProtoMessage()    {}
+func (*ImageFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{2}
+}
+
+func (m *ImageFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageFormat.Unmarshal(m, b)
+}
+func (m *ImageFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageFormat.Marshal(b, m, deterministic)
+}
+func (m *ImageFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageFormat.Merge(m, src)
+}
+func (m *ImageFormat) XXX_Size() int {
+	return xxx_messageInfo_ImageFormat.Size(m)
+}
+func (m *ImageFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageFormat proto.InternalMessageInfo
+
+type ParseRequest struct {
+	Data                 []byte      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Format               Format_Id   `protobuf:"varint,2,opt,name=format,proto3,enum=yunomu.kif.Format_Id" json:"format,omitempty"`
+	Encoding             Encoding_Id `protobuf:"varint,3,opt,name=encoding,proto3,enum=yunomu.kif.Encoding_Id" json:"encoding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ParseRequest) Reset()         { *m = ParseRequest{} }
+func (m *ParseRequest) String() string { return proto.CompactTextString(m) }
+func (*ParseRequest) ProtoMessage()    {}
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{3}
+}
+
+func (m *ParseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseRequest.Unmarshal(m, b)
+}
+func (m *ParseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseRequest.Marshal(b, m, deterministic)
+}
+func (m *ParseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseRequest.Merge(m, src)
+}
+func (m *ParseRequest) XXX_Size() int {
+	return xxx_messageInfo_ParseRequest.Size(m)
+}
+func (m *ParseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseRequest proto.InternalMessageInfo
+
+func (m *ParseRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ParseRequest) GetFormat() Format_Id {
+	if m != nil {
+		return m.Format
+	}
+	return Format_KIF
+}
+
+func (m *ParseRequest) GetEncoding() Encoding_Id {
+	if m != nil {
+		return m.Encoding
+	}
+	return Encoding_SHIFT_JIS
+}
+
+type ParseResponse struct {
+	Kif                  *Kif     `protobuf:"bytes,1,opt,name=kif,proto3" json:"kif,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ParseResponse) Reset()         { *m = ParseResponse{} }
+func (m *ParseResponse) String() string { return proto.CompactTextString(m) }
+func (*ParseResponse) ProtoMessage()    {}
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{4}
+}
+
+func (m *ParseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseResponse.Unmarshal(m, b)
+}
+func (m *ParseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseResponse.Marshal(b, m, deterministic)
+}
+func (m *ParseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseResponse.Merge(m, src)
+}
+func (m *ParseResponse) XXX_Size() int {
+	return xxx_messageInfo_ParseResponse.Size(m)
+}
+func (m *ParseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseResponse proto.InternalMessageInfo
+
+func (m *ParseResponse) GetKif() *Kif {
+	if m != nil {
+		return m.Kif
+	}
+	return nil
+}
+
+type ConvertRequest struct {
+	Data                 []byte      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Format               Format_Id   `protobuf:"varint,2,opt,name=format,proto3,enum=yunomu.kif.Format_Id" json:"format,omitempty"`
+	Encoding             Encoding_Id `protobuf:"varint,3,opt,name=encoding,proto3,enum=yunomu.kif.Encoding_Id" json:"encoding,omitempty"`
+	OutputFormat         Format_Id   `protobuf:"varint,4,opt,name=output_format,json=outputFormat,proto3,enum=yunomu.kif.Format_Id" json:"output_format,omitempty"`
+	OutputEncoding       Encoding_Id `protobuf:"varint,5,opt,name=output_encoding,json=outputEncoding,proto3,enum=yunomu.kif.Encoding_Id" json:"output_encoding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ConvertRequest) Reset()         { *m = ConvertRequest{} }
+func (m *ConvertRequest) String() string { return proto.CompactTextString(m) }
+func (*ConvertRequest) ProtoMessage()    {}
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{5}
+}
+
+func (m *ConvertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertRequest.Unmarshal(m, b)
+}
+func (m *ConvertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertRequest.Marshal(b, m, deterministic)
+}
+func (m *ConvertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertRequest.Merge(m, src)
+}
+func (m *ConvertRequest) XXX_Size() int {
+	return xxx_messageInfo_ConvertRequest.Size(m)
+}
+func (m *ConvertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertRequest proto.InternalMessageInfo
+
+func (m *ConvertRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ConvertRequest) GetFormat() Format_Id {
+	if m != nil {
+		return m.Format
+	}
+	return Format_KIF
+}
+
+func (m *ConvertRequest) GetEncoding() Encoding_Id {
+	if m != nil {
+		return m.Encoding
+	}
+	return Encoding_SHIFT_JIS
+}
+
+func (m *ConvertRequest) GetOutputFormat() Format_Id {
+	if m != nil {
+		return m.OutputFormat
+	}
+	return Format_KIF
+}
+
+func (m *ConvertRequest) GetOutputEncoding() Encoding_Id {
+	if m != nil {
+		return m.OutputEncoding
+	}
+	return Encoding_SHIFT_JIS
+}
+
+type ConvertResponse struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType          string   `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvertResponse) Reset()         { *m = ConvertResponse{} }
+func (m *ConvertResponse) String() string { return proto.CompactTextString(m) }
+func (*ConvertResponse) ProtoMessage()    {}
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{6}
+}
+
+func (m *ConvertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertResponse.Unmarshal(m, b)
+}
+func (m *ConvertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertResponse.Marshal(b, m, deterministic)
+}
+func (m *ConvertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertResponse.Merge(m, src)
+}
+func (m *ConvertResponse) XXX_Size() int {
+	return xxx_messageInfo_ConvertResponse.Size(m)
+}
+func (m *ConvertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertResponse proto.InternalMessageInfo
+
+func (m *ConvertResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ConvertResponse) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+type ValidateRequest struct {
+	Data                 []byte      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Format               Format_Id   `protobuf:"varint,2,opt,name=format,proto3,enum=yunomu.kif.Format_Id" json:"format,omitempty"`
+	Encoding             Encoding_Id `protobuf:"varint,3,opt,name=encoding,proto3,enum=yunomu.kif.Encoding_Id" json:"encoding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ValidateRequest) Reset()         { *m = ValidateRequest{} }
+func (m *ValidateRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateRequest) ProtoMessage()    {}
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{7}
+}
+
+func (m *ValidateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateRequest.Unmarshal(m, b)
+}
+func (m *ValidateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateRequest.Merge(m, src)
+}
+func (m *ValidateRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateRequest.Size(m)
+}
+func (m *ValidateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateRequest proto.InternalMessageInfo
+
+func (m *ValidateRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ValidateRequest) GetFormat() Format_Id {
+	if m != nil {
+		return m.Format
+	}
+	return Format_KIF
+}
+
+func (m *ValidateRequest) GetEncoding() Encoding_Id {
+	if m != nil {
+		return m.Encoding
+	}
+	return Encoding_SHIFT_JIS
+}
+
+type ValidationError struct {
+	// 0 for errors of the whole game
+	Seq                  int32    `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidationError) Reset()         { *m = ValidationError{} }
+func (m *ValidationError) String() string { return proto.CompactTextString(m) }
+func (*ValidationError) ProtoMessage()    {}
+func (*ValidationError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{8}
+}
+
+func (m *ValidationError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidationError.Unmarshal(m, b)
+}
+func (m *ValidationError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidationError.Marshal(b, m, deterministic)
+}
+func (m *ValidationError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidationError.Merge(m, src)
+}
+func (m *ValidationError) XXX_Size() int {
+	return xxx_messageInfo_ValidationError.Size(m)
+}
+func (m *ValidationError) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidationError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidationError proto.InternalMessageInfo
+
+func (m *ValidationError) GetSeq() int32 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *ValidationError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	Valid                bool               `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors               []*ValidationError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ValidateResponse) Reset()         { *m = ValidateResponse{} }
+func (m *ValidateResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidateResponse) ProtoMessage()    {}
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{9}
+}
+
+func (m *ValidateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateResponse.Unmarshal(m, b)
+}
+func (m *ValidateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateResponse.Marshal(b, m, deterministic)
+}
+func (m *ValidateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateResponse.Merge(m, src)
+}
+func (m *ValidateResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidateResponse.Size(m)
+}
+func (m *ValidateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateResponse proto.InternalMessageInfo
+
+func (m *ValidateResponse) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *ValidateResponse) GetErrors() []*ValidationError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type RenderPositionRequest struct {
+	Data     []byte      `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Format   Format_Id   `protobuf:"varint,2,opt,name=format,proto3,enum=yunomu.kif.Format_Id" json:"format,omitempty"`
+	Encoding Encoding_Id `protobuf:"varint,3,opt,name=encoding,proto3,enum=yunomu.kif.Encoding_Id" json:"encoding,omitempty"`
+	// number of moves of the main line, negative for the last position
+	Ply                  int32          `protobuf:"varint,4,opt,name=ply,proto3" json:"ply,omitempty"`
+	ImageFormat          ImageFormat_Id `protobuf:"varint,5,opt,name=image_format,json=imageFormat,proto3,enum=yunomu.kif.ImageFormat_Id" json:"image_format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RenderPositionRequest) Reset()         { *m = RenderPositionRequest{} }
+func (m *RenderPositionRequest) String() string { return proto.CompactTextString(m) }
+func (*RenderPositionRequest) ProtoMessage()    {}
+func (*RenderPositionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{10}
+}
+
+func (m *RenderPositionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenderPositionRequest.Unmarshal(m, b)
+}
+func (m *RenderPositionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenderPositionRequest.Marshal(b, m, deterministic)
+}
+func (m *RenderPositionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenderPositionRequest.Merge(m, src)
+}
+func (m *RenderPositionRequest) XXX_Size() int {
+	return xxx_messageInfo_RenderPositionRequest.Size(m)
+}
+func (m *RenderPositionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenderPositionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenderPositionRequest proto.InternalMessageInfo
+
+func (m *RenderPositionRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *RenderPositionRequest) GetFormat() Format_Id {
+	if m != nil {
+		return m.Format
+	}
+	return Format_KIF
+}
+
+func (m *RenderPositionRequest) GetEncoding() Encoding_Id {
+	if m != nil {
+		return m.Encoding
+	}
+	return Encoding_SHIFT_JIS
+}
+
+func (m *RenderPositionRequest) GetPly() int32 {
+	if m != nil {
+		return m.Ply
+	}
+	return 0
+}
+
+func (m *RenderPositionRequest) GetImageFormat() ImageFormat_Id {
+	if m != nil {
+		return m.ImageFormat
+	}
+	return ImageFormat_SVG
+}
+
+type RenderPositionResponse struct {
+	Image                []byte   `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ContentType          string   `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Sfen                 string   `protobuf:"bytes,3,opt,name=sfen,proto3" json:"sfen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenderPositionResponse) Reset()         { *m = RenderPositionResponse{} }
+func (m *RenderPositionResponse) String() string { return proto.CompactTextString(m) }
+func (*RenderPositionResponse) ProtoMessage()    {}
+func (*RenderPositionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3c210f526123e307, []int{11}
+}
+
+func (m *RenderPositionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenderPositionResponse.Unmarshal(m, b)
+}
+func (m *RenderPositionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenderPositionResponse.Marshal(b, m, deterministic)
+}
+func (m *RenderPositionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenderPositionResponse.Merge(m, src)
+}
+func (m *RenderPositionResponse) XXX_Size() int {
+	return xxx_messageInfo_RenderPositionResponse.Size(m)
+}
+func (m *RenderPositionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenderPositionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenderPositionResponse proto.InternalMessageInfo
+
+func (m *RenderPositionResponse) GetImage() []byte {
+	if m != nil {
+		return m.Image
+	}
+	return nil
+}
+
+func (m *RenderPositionResponse) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *RenderPositionResponse) GetSfen() string {
+	if m != nil {
+		return m.Sfen
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("yunomu.kif.Format_Id", Format_Id_name, Format_Id_value)
+	proto.RegisterEnum("yunomu.kif.Encoding_Id", Encoding_Id_name, Encoding_Id_value)
+	proto.RegisterEnum("yunomu.kif.ImageFormat_Id", ImageFormat_Id_name, ImageFormat_Id_value)
+	proto.RegisterType((*Format)(nil), "yunomu.kif.Format")
+	proto.RegisterType((*Encoding)(nil), "yunomu.kif.Encoding")
+	proto.RegisterType((*ImageFormat)(nil), "yunomu.kif.ImageFormat")
+	proto.RegisterType((*ParseRequest)(nil), "yunomu.kif.ParseRequest")
+	proto.RegisterType((*ParseResponse)(nil), "yunomu.kif.ParseResponse")
+	proto.RegisterType((*ConvertRequest)(nil), "yunomu.kif.ConvertRequest")
+	proto.RegisterType((*ConvertResponse)(nil), "yunomu.kif.ConvertResponse")
+	proto.RegisterType((*ValidateRequest)(nil), "yunomu.kif.ValidateRequest")
+	proto.RegisterType((*ValidationError)(nil), "yunomu.kif.ValidationError")
+	proto.RegisterType((*ValidateResponse)(nil), "yunomu.kif.ValidateResponse")
+	proto.RegisterType((*RenderPositionRequest)(nil), "yunomu.kif.RenderPositionRequest")
+	proto.RegisterType((*RenderPositionResponse)(nil), "yunomu.kif.RenderPositionResponse")
+}
+
+func init() { proto.RegisterFile("ptypes/service.proto", fileDescriptor_3c210f526123e307) }
+
+var fileDescriptor_3c210f526123e307 = []byte{
+	// 628 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0xdd, 0x4e, 0xdb, 0x4c,
+	0x10, 0xc5, 0x76, 0x12, 0xc2, 0x24, 0x24, 0xab, 0x15, 0x7c, 0x5f, 0x1a, 0x5a, 0x29, 0xec, 0x15,
+	0xbd, 0x68, 0x90, 0x92, 0x9b, 0xaa, 0x2a, 0x52, 0x0b, 0x4a, 0x20, 0xa4, 0x45, 0xc8, 0x49, 0x69,
+	0x55, 0xa9, 0x42, 0x2e, 0x5e, 0xa3, 0x15, 0xc4, 0x6b, 0xbc, 0x0e, 0x52, 0x5e, 0x00, 0xa9, 0x2f,
+	0xd3, 0x67, 0xea, 0xa3, 0x54, 0xfb, 0x63, 0xc7, 0x20, 0x0b, 0xf5, 0x2e, 0x77, 0xb3, 0x3b, 0x67,
+	0xcf, 0x9c, 0x19, 0x1d, 0x8f, 0x61, 0x2b, 0x4a, 0x16, 0x11, 0x15, 0xfb, 0x82, 0xc6, 0xf7, 0xec,
+	0x8a, 0x76, 0xa3, 0x98, 0x27, 0x1c, 0xc3, 0x62, 0x1e, 0xf2, 0xd9, 0xbc, 0x7b, 0xc3, 0x82, 0x36,
+	0x32, 0x88, 0x1b, 0x16, 0xe8, 0x2c, 0x39, 0x80, 0xca, 0x90, 0xc7, 0x33, 0x2f, 0x21, 0x7d, 0xb0,
+	0x47, 0x3e, 0x5e, 0x07, 0x67, 0x3c, 0x1a, 0xa2, 0x35, 0x19, 0x1c, 0x4d, 0x3e, 0x22, 0x4b, 0x06,
+	0xa7, 0xe3, 0x21, 0xb2, 0x71, 0x15, 0x4a, 0x93, 0xe1, 0xe0, 0x0c, 0x39, 0x32, 0x3a, 0x99, 0x7e,
+	0xfe, 0x84, 0x4a, 0xe4, 0x35, 0x54, 0x07, 0xe1, 0x15, 0xf7, 0x59, 0x78, 0x4d, 0x5e, 0x29, 0x82,
+	0x4d, 0xd8, 0x98, 0x9c, 0x8c, 0x86, 0xd3, 0xcb, 0xd3, 0xd1, 0x04, 0xad, 0x49, 0xe8, 0x97, 0xe9,
+	0xf0, 0x2d, 0xb2, 0xc8, 0x3e, 0xd4, 0x46, 0x33, 0xef, 0x9a, 0x9a, 0x72, 0x9d, 0xb4, 0xdc, 0xe4,
+	0xe2, 0x58, 0x97, 0x3b, 0x3f, 0x3b, 0x46, 0x96, 0x7c, 0x30, 0x1d, 0x7c, 0x9b, 0x22, 0x9b, 0x3c,
+	0x58, 0x50, 0x3f, 0xf7, 0x62, 0x41, 0x5d, 0x7a, 0x37, 0xa7, 0x22, 0xc1, 0x18, 0x4a, 0xbe, 0x97,
+	0x78, 0x2d, 0xab, 0x63, 0xed, 0xd5, 0x5d, 0x15, 0xe3, 0x37, 0x50, 0x09, 0x14, 0x61, 0xcb, 0xee,
+	0x58, 0x7b, 0x8d, 0xde, 0x76, 0x77, 0xd9, 0x6e, 0x57, 0x97, 0xea, 0x8e, 0x7c, 0xd7, 0x80, 0x70,
+	0x1f, 0xaa, 0xd4, 0xe8, 0x6d, 0x39, 0xea, 0xc1, 0xff, 0xf9, 0x07, 0x69, 0x2f, 0xf2, 0x49, 0x06,
+	0x24, 0x3d, 0xd8, 0x34, 0x3a, 0x44, 0xc4, 0x43, 0x41, 0xf1, 0x2e, 0x38, 0x37, 0x2c, 0x50, 0x3a,
+	0x6a, 0xbd, 0x66, 0x9e, 0x60, 0xcc, 0x02, 0x57, 0xe6, 0xc8, 0x83, 0x0d, 0x8d, 0x23, 0x1e, 0xde,
+	0xd3, 0x38, 0x59, 0xb1, 0x7c, 0xfc, 0x0e, 0x36, 0xf9, 0x3c, 0x89, 0xe6, 0xc9, 0xa5, 0x29, 0x55,
+	0x7a, 0xae, 0x54, 0x5d, 0x63, 0xf5, 0x05, 0xfe, 0x00, 0x4d, 0xf3, 0x36, 0xab, 0x5b, 0x7e, 0xbe,
+	0x6e, 0x43, 0xe3, 0x33, 0x57, 0x9c, 0x40, 0x33, 0x9b, 0x83, 0x19, 0x5f, 0xd1, 0x20, 0x76, 0xa1,
+	0x7e, 0xc5, 0xc3, 0x84, 0x86, 0xc9, 0xa5, 0xb4, 0xa8, 0x1a, 0xc7, 0x86, 0x5b, 0x33, 0x77, 0xd3,
+	0x45, 0x44, 0xc9, 0x2f, 0x0b, 0x9a, 0x17, 0xde, 0x2d, 0xf3, 0xbd, 0x64, 0xe5, 0x96, 0x38, 0xc8,
+	0xa4, 0x30, 0x1e, 0x0e, 0xe2, 0x98, 0xc7, 0x18, 0x81, 0x23, 0xe8, 0x9d, 0x52, 0x52, 0x76, 0x65,
+	0x88, 0x5b, 0xb0, 0x3e, 0xa3, 0x42, 0x78, 0xd7, 0x69, 0x3b, 0xe9, 0x91, 0xfc, 0x00, 0xb4, 0xec,
+	0xc4, 0x4c, 0x65, 0x0b, 0xca, 0xf7, 0xf2, 0x4e, 0x31, 0x54, 0x5d, 0x7d, 0xc0, 0x7d, 0xa8, 0x50,
+	0x49, 0x2f, 0x5a, 0x76, 0xc7, 0xd9, 0xab, 0xf5, 0x76, 0xf2, 0xda, 0x9e, 0x48, 0x70, 0x0d, 0x94,
+	0xfc, 0xb1, 0x60, 0xdb, 0xa5, 0xa1, 0x4f, 0xe3, 0x73, 0x2e, 0x98, 0xcc, 0xaf, 0xda, 0x83, 0x08,
+	0x9c, 0xe8, 0x76, 0xa1, 0x9c, 0x57, 0x76, 0x65, 0x88, 0x0f, 0xa0, 0xce, 0xe4, 0x3a, 0x48, 0x4d,
+	0xa9, 0x6d, 0xd5, 0xce, 0x53, 0xe5, 0xd6, 0x85, 0x64, 0xab, 0xb1, 0xe5, 0x99, 0x50, 0xf8, 0xef,
+	0x69, 0x87, 0xcb, 0x39, 0x2a, 0xa0, 0xe9, 0x51, 0x1f, 0xfe, 0xc1, 0x5f, 0x72, 0x36, 0x22, 0xa0,
+	0xa1, 0x6a, 0x6a, 0xc3, 0x55, 0x71, 0xef, 0xb7, 0x0d, 0x30, 0x66, 0xc1, 0x44, 0x6f, 0x54, 0xfc,
+	0x1e, 0xca, 0x6a, 0x13, 0xe0, 0x56, 0x5e, 0x67, 0x7e, 0x49, 0xb5, 0x5f, 0x14, 0x64, 0x8c, 0xb2,
+	0x43, 0x58, 0x37, 0x9f, 0x02, 0x7e, 0xd4, 0xe7, 0xe3, 0x3d, 0xd1, 0xde, 0x29, 0xcc, 0x19, 0x8e,
+	0x01, 0x54, 0x53, 0xe7, 0xe0, 0x22, 0x2f, 0x64, 0x3a, 0x5e, 0x16, 0x27, 0x0d, 0xcd, 0x57, 0x68,
+	0x3c, 0x1e, 0x1f, 0xde, 0xcd, 0xe3, 0x0b, 0xcd, 0xd3, 0x26, 0xcf, 0x41, 0x34, 0xf1, 0x61, 0xf5,
+	0x7b, 0x45, 0xff, 0x63, 0x7e, 0x56, 0xd4, 0x0f, 0xa6, 0xff, 0x77, 0x00, 0x09, 0x17, 0x8a, 0x11,
+	0x96, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// KifServiceClient is the client API for KifService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KifServiceClient interface {
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	RenderPosition(ctx context.Context, in *RenderPositionRequest, opts ...grpc.CallOption) (*RenderPositionResponse, error)
+}
+
+type kifServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewKifServiceClient(cc *grpc.ClientConn) KifServiceClient {
+	return &kifServiceClient{cc}
+}
+
+func (c *kifServiceClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, "/yunomu.kif.KifService/Parse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kifServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, "/yunomu.kif.KifService/Convert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kifServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, "/yunomu.kif.KifService/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kifServiceClient) RenderPosition(ctx context.Context, in *RenderPositionRequest, opts ...grpc.CallOption) (*RenderPositionResponse, error) {
+	out := new(RenderPositionResponse)
+	err := c.cc.Invoke(ctx, "/yunomu.kif.KifService/RenderPosition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KifServiceServer is the server API for KifService service.
+type KifServiceServer interface {
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	RenderPosition(context.Context, *RenderPositionRequest) (*RenderPositionResponse, error)
+}
+
+// UnimplementedKifServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedKifServiceServer struct {
+}
+
+func (*UnimplementedKifServiceServer) Parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+func (*UnimplementedKifServiceServer) Convert(ctx context.Context, req *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (*UnimplementedKifServiceServer) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (*UnimplementedKifServiceServer) RenderPosition(ctx context.Context, req *RenderPositionRequest) (*RenderPositionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderPosition not implemented")
+}
+
+func RegisterKifServiceServer(s *grpc.Server, srv KifServiceServer) {
+	s.RegisterService(&_KifService_serviceDesc, srv)
+}
+
+func _KifService_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KifServiceServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yunomu.kif.KifService/Parse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KifServiceServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KifService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KifServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yunomu.kif.KifService/Convert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KifServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KifService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KifServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yunomu.kif.KifService/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KifServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KifService_RenderPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KifServiceServer).RenderPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yunomu.kif.KifService/RenderPosition",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KifServiceServer).RenderPosition(ctx, req.(*RenderPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _KifService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yunomu.kif.KifService",
+	HandlerType: (*KifServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _KifService_Parse_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _KifService_Convert_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _KifService_Validate_Handler,
+		},
+		{
+			MethodName: "RenderPosition",
+			Handler:    _KifService_RenderPosition_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ptypes/service.proto",
+}
@@ -0,0 +1,98 @@
+package rating
+
+import (
+	"math"
+)
+
+const (
+	defaultEloK       = 32
+	defaultEloInitial = 1500
+)
+
+// Elo rates the players by the Elo rating system, updating the ratings after
+// every game.
+type Elo struct {
+	k         float64
+	initial   float64
+	handicaps map[string]float64
+	first     float64
+}
+
+type EloOption func(*Elo)
+
+// EloK sets the K-factor, the largest change by a game.
+func EloK(k float64) EloOption {
+	return func(e *Elo) {
+		e.k = k
+	}
+}
+
+// EloInitial sets the rating of new players.
+func EloInitial(r float64) EloOption {
+	return func(e *Elo) {
+		e.initial = r
+	}
+}
+
+// EloHandicaps sets the advantages of 下手 by 手合割 (default: DefaultHandicaps).
+func EloHandicaps(m map[string]float64) EloOption {
+	return func(e *Elo) {
+		e.handicaps = m
+	}
+}
+
+// EloFirstMove sets the advantage of Sente in even games.
+func EloFirstMove(r float64) EloOption {
+	return func(e *Elo) {
+		e.first = r
+	}
+}
+
+func NewElo(ops ...EloOption) *Elo {
+	e := &Elo{
+		k:         defaultEloK,
+		initial:   defaultEloInitial,
+		handicaps: DefaultHandicaps,
+	}
+	for _, f := range ops {
+		f(e)
+	}
+	return e
+}
+
+// expected returns the expected score of the player rated r against o.
+func expected(r, o float64) float64 {
+	return 1 / (1 + math.Pow(10, (o-r)/400))
+}
+
+func (e *Elo) Rate(games []*Game) []*Player {
+	players := make(map[string]*Player)
+	player := func(name string) *Player {
+		p, ok := players[name]
+		if !ok {
+			p = &Player{Name: name, Rating: e.initial}
+			players[name] = p
+		}
+		return p
+	}
+
+	for _, g := range finished(games) {
+		sente, gote := player(g.Sente), player(g.Gote)
+		ss, gs, _ := score(g.Outcome)
+		adv := advantage(g, e.handicaps, e.first)
+
+		es := expected(sente.Rating+adv, gote.Rating)
+		sente.Rating += e.k * (ss - es)
+		gote.Rating += e.k * (gs - (1 - es))
+
+		for _, v := range []struct {
+			p *Player
+			s float64
+		}{{sente, ss}, {gote, gs}} {
+			v.p.count(v.s)
+			v.p.History = append(v.p.History, &Entry{Date: g.Date, Rating: v.p.Rating})
+		}
+	}
+
+	return sortPlayers(players)
+}
@@ -0,0 +1,216 @@
+package rating
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+const evenHandicap = "平手"
+
+// DefaultHandicaps are the rough advantages of 下手 in rating points by 手合割.
+var DefaultHandicaps = map[string]float64{
+	"香落ち":   100,
+	"右香落ち":  100,
+	"角落ち":   200,
+	"飛車落ち":  250,
+	"飛香落ち":  300,
+	"二枚落ち":  400,
+	"三枚落ち":  450,
+	"四枚落ち":  500,
+	"五枚落ち":  550,
+	"左五枚落ち": 550,
+	"六枚落ち":  600,
+	"左七枚落ち": 650,
+	"右七枚落ち": 650,
+	"八枚落ち":  700,
+	"十枚落ち":  800,
+}
+
+// Aliases maps the spellings of player names to their canonical names.
+type Aliases map[string]string
+
+// ReadAliases reads lines of a canonical name followed by its other spellings,
+// separated by tabs. Empty lines and lines starting with # are ignored.
+func ReadAliases(r io.Reader) (Aliases, error) {
+	ret := make(Aliases)
+	s := bufio.NewScanner(r)
+	var line int
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		names := strings.Split(text, "\t")
+		canonical := strings.TrimSpace(names[0])
+		for _, name := range names[1:] {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if c, ok := ret[name]; ok && c != canonical {
+				return nil, fmt.Errorf("line=%d: %q is an alias of %q and %q", line, name, c, canonical)
+			}
+			ret[name] = canonical
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Resolve returns the canonical name of the player.
+func (a Aliases) Resolve(name string) string {
+	name = strings.TrimSpace(name)
+	if c, ok := a[name]; ok {
+		return c
+	}
+	return name
+}
+
+// Game is a rated game. Sente is 下手 and Gote is 上手 in handicap games.
+type Game struct {
+	ID       string
+	Date     time.Time
+	Sente    string
+	Gote     string
+	Handicap string
+	Outcome  kif.Outcome
+}
+
+func header(k *ptypes.Kif, names ...string) string {
+	for _, name := range names {
+		for _, h := range k.GetHeaders() {
+			if h.Name == name && strings.TrimSpace(h.Value) != "" {
+				return h.Value
+			}
+		}
+	}
+	return ""
+}
+
+// NewGame returns the players, the date and the result of the record. aliases
+// may be nil.
+func NewGame(id string, k *ptypes.Kif, aliases Aliases) (*Game, error) {
+	date, err := kif.StartTime(k)
+	if err != nil {
+		return nil, err
+	}
+	o, err := kif.GetOutcome(k)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Game{
+		ID:       id,
+		Date:     date,
+		Sente:    aliases.Resolve(header(k, "先手", "下手")),
+		Gote:     aliases.Resolve(header(k, "後手", "上手")),
+		Handicap: header(k, "手合割"),
+		Outcome:  o,
+	}
+	if g.Handicap == "" {
+		g.Handicap = evenHandicap
+	}
+	if g.Sente == "" || g.Gote == "" {
+		return nil, fmt.Errorf("no players")
+	}
+	if g.Sente == g.Gote {
+		return nil, fmt.Errorf("same players: %v", g.Sente)
+	}
+	return g, nil
+}
+
+// Entry is a rating of a player after a game, or after a rating period.
+type Entry struct {
+	Date   time.Time `json:"date"`
+	Rating float64   `json:"rating"`
+	RD     float64   `json:"rd,omitempty"`
+}
+
+type Player struct {
+	Name       string   `json:"name"`
+	Rating     float64  `json:"rating"`
+	RD         float64  `json:"rd,omitempty"`
+	Volatility float64  `json:"volatility,omitempty"`
+	Games      int      `json:"games"`
+	Wins       int      `json:"wins"`
+	Losses     int      `json:"losses"`
+	Draws      int      `json:"draws"`
+	History    []*Entry `json:"history"`
+}
+
+// Rater computes the ratings of the players from the games in the
+// chronological order.
+type Rater interface {
+	Rate(games []*Game) []*Player
+}
+
+// score returns the scores of Sente and Gote, and false for unfinished games.
+func score(o kif.Outcome) (float64, float64, bool) {
+	switch o {
+	case kif.Outcome_SENTE_WIN:
+		return 1, 0, true
+	case kif.Outcome_GOTE_WIN:
+		return 0, 1, true
+	case kif.Outcome_DRAW:
+		return 0.5, 0.5, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func (p *Player) count(s float64) {
+	p.Games++
+	switch s {
+	case 1:
+		p.Wins++
+	case 0:
+		p.Losses++
+	default:
+		p.Draws++
+	}
+}
+
+// advantage returns the advantage of Sente in rating points.
+func advantage(g *Game, handicaps map[string]float64, first float64) float64 {
+	if g.Handicap == evenHandicap {
+		return first
+	}
+	return handicaps[g.Handicap]
+}
+
+// finished returns the finished games sorted by date. Games on the same time
+// keep their order.
+func finished(games []*Game) []*Game {
+	var ret []*Game
+	for _, g := range games {
+		if _, _, ok := score(g.Outcome); ok {
+			ret = append(ret, g)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Date.Before(ret[j].Date) })
+	return ret
+}
+
+func sortPlayers(players map[string]*Player) []*Player {
+	ret := make([]*Player, 0, len(players))
+	for _, p := range players {
+		ret = append(ret, p)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Rating != ret[j].Rating {
+			return ret[i].Rating > ret[j].Rating
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
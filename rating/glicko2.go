@@ -0,0 +1,233 @@
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	glickoScale = 173.7178
+
+	defaultGlickoRating     = 1500
+	defaultGlickoRD         = 350
+	defaultGlickoVolatility = 0.06
+	defaultGlickoTau        = 0.5
+	defaultGlickoPeriod     = 24 * time.Hour
+
+	glickoEpsilon = 0.000001
+)
+
+// Glicko2 rates the players by the Glicko-2 rating system. The games are
+// grouped into rating periods from the midnight of the first game, and the
+// deviations of the rated players grow in the periods they do not play, up
+// to the initial deviation.
+type Glicko2 struct {
+	rating     float64
+	rd         float64
+	volatility float64
+	tau        float64
+	period     time.Duration
+	handicaps  map[string]float64
+	first      float64
+}
+
+type Glicko2Option func(*Glicko2)
+
+// Glicko2Initial sets the rating, the rating deviation and the volatility of
+// new players.
+func Glicko2Initial(rating, rd, volatility float64) Glicko2Option {
+	return func(g *Glicko2) {
+		g.rating, g.rd, g.volatility = rating, rd, volatility
+	}
+}
+
+// Glicko2Tau sets the constraint on the change of the volatility.
+func Glicko2Tau(tau float64) Glicko2Option {
+	return func(g *Glicko2) {
+		g.tau = tau
+	}
+}
+
+// Glicko2Period sets the length of the rating periods.
+func Glicko2Period(d time.Duration) Glicko2Option {
+	return func(g *Glicko2) {
+		g.period = d
+	}
+}
+
+// Glicko2Handicaps sets the advantages of 下手 by 手合割 (default: DefaultHandicaps).
+func Glicko2Handicaps(m map[string]float64) Glicko2Option {
+	return func(g *Glicko2) {
+		g.handicaps = m
+	}
+}
+
+// Glicko2FirstMove sets the advantage of Sente in even games.
+func Glicko2FirstMove(r float64) Glicko2Option {
+	return func(g *Glicko2) {
+		g.first = r
+	}
+}
+
+func NewGlicko2(ops ...Glicko2Option) *Glicko2 {
+	g := &Glicko2{
+		rating:     defaultGlickoRating,
+		rd:         defaultGlickoRD,
+		volatility: defaultGlickoVolatility,
+		tau:        defaultGlickoTau,
+		period:     defaultGlickoPeriod,
+		handicaps:  DefaultHandicaps,
+	}
+	for _, f := range ops {
+		f(g)
+	}
+	if g.period <= 0 {
+		g.period = defaultGlickoPeriod
+	}
+	return g
+}
+
+// glickoResult is a game of a player in a rating period on the Glicko-2
+// scale. The advantage of the player is added to mu.
+type glickoResult struct {
+	mu, opMu, opPhi, score float64
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glickoE(mu, opMu, opPhi float64) float64 {
+	return 1 / (1 + math.Exp(-glickoG(opPhi)*(mu-opMu)))
+}
+
+// newVolatility returns the new volatility by the Illinois algorithm.
+func (g *Glicko2) newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau2 := g.tau * g.tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/tau2
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*g.tau) < 0 {
+			k++
+		}
+		B = a - k*g.tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// update returns the new rating, deviation and volatility of the player from
+// the results in a rating period after idle periods without games.
+func (g *Glicko2) update(p *Player, idle int, results []*glickoResult) (float64, float64, float64) {
+	mu := (p.Rating - g.rating) / glickoScale
+	sigma := p.Volatility
+	maxPhi := g.rd / glickoScale
+	phi := math.Min(math.Sqrt(math.Pow(p.RD/glickoScale, 2)+float64(idle)*sigma*sigma), maxPhi)
+	if len(results) == 0 {
+		return p.Rating, math.Min(math.Sqrt(phi*phi+sigma*sigma), maxPhi) * glickoScale, sigma
+	}
+
+	var vInv, sum float64
+	for _, r := range results {
+		gPhi := glickoG(r.opPhi)
+		e := glickoE(mu+r.mu, r.opMu, r.opPhi)
+		vInv += gPhi * gPhi * e * (1 - e)
+		sum += gPhi * (r.score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma = g.newVolatility(phi, sigma, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * sum
+
+	return mu*glickoScale + g.rating, phi * glickoScale, sigma
+}
+
+func (g *Glicko2) Rate(games []*Game) []*Player {
+	players := make(map[string]*Player)
+	player := func(name string) *Player {
+		p, ok := players[name]
+		if !ok {
+			p = &Player{Name: name, Rating: g.rating, RD: g.rd, Volatility: g.volatility}
+			players[name] = p
+		}
+		return p
+	}
+
+	games = finished(games)
+	if len(games) == 0 {
+		return sortPlayers(players)
+	}
+	first := games[0].Date
+	base := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	periodOf := func(t time.Time) int64 {
+		return int64(t.Sub(base) / g.period)
+	}
+
+	last := periodOf(first) - 1
+	for len(games) != 0 {
+		period := periodOf(games[0].Date)
+		var n int
+		for n < len(games) && periodOf(games[n].Date) == period {
+			n++
+		}
+
+		results := make(map[*Player][]*glickoResult)
+		for _, game := range games[:n] {
+			sente, gote := player(game.Sente), player(game.Gote)
+			ss, gs, _ := score(game.Outcome)
+			adv := advantage(game, g.handicaps, g.first) / glickoScale
+			muS := (sente.Rating - g.rating) / glickoScale
+			muG := (gote.Rating - g.rating) / glickoScale
+			results[sente] = append(results[sente], &glickoResult{mu: adv, opMu: muG, opPhi: gote.RD / glickoScale, score: ss})
+			results[gote] = append(results[gote], &glickoResult{mu: -adv, opMu: muS, opPhi: sente.RD / glickoScale, score: gs})
+			sente.count(ss)
+			gote.count(gs)
+		}
+
+		type rated struct {
+			rating, rd, volatility float64
+		}
+		idle := int(period - last - 1)
+		next := make(map[*Player]rated)
+		for _, p := range players {
+			r, rd, vol := g.update(p, idle, results[p])
+			next[p] = rated{r, rd, vol}
+		}
+		date := games[n-1].Date
+		for p, r := range next {
+			p.Rating, p.RD, p.Volatility = r.rating, r.rd, r.volatility
+			if _, ok := results[p]; ok {
+				p.History = append(p.History, &Entry{Date: date, Rating: p.Rating, RD: p.RD})
+			}
+		}
+
+		last = period
+		games = games[n:]
+	}
+
+	return sortPlayers(players)
+}
@@ -0,0 +1,150 @@
+package rating
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func near(a, b, eps float64) bool {
+	return math.Abs(a-b) < eps
+}
+
+func day(d int) time.Time {
+	return time.Date(2020, 1, d, 10, 0, 0, 0, time.UTC)
+}
+
+func TestReadAliases(t *testing.T) {
+	a, err := ReadAliases(strings.NewReader(`# comment
+山田太郎	山田 太郎	やまだ
+
+Suzuki	suzuki
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"山田 太郎":  "山田太郎",
+		" やまだ ":  "山田太郎",
+		"suzuki": "Suzuki",
+		"佐藤":     "佐藤",
+	} {
+		if v := a.Resolve(name); v != expected {
+			t.Errorf("%v: expected=%v actual=%v", name, expected, v)
+		}
+	}
+
+	if _, err := ReadAliases(strings.NewReader("A\tx\nB\tx\n")); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestNewGame(t *testing.T) {
+	k := &ptypes.Kif{
+		Headers: []*ptypes.Header{
+			{Name: "開始日時", Value: "2020/01/02 10:00:00"},
+			{Name: "手合割", Value: "角落ち"},
+			{Name: "下手", Value: "やまだ"},
+			{Name: "上手", Value: "Suzuki"},
+		},
+		Steps: []*ptypes.Step{{Seq: 1, FinishedStatus: ptypes.FinishedStatus_SURRENDER}},
+	}
+	g, err := NewGame("a", k, Aliases{"やまだ": "山田太郎"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 上手 moves first and resigns
+	if g.Sente != "山田太郎" || g.Gote != "Suzuki" || g.Handicap != "角落ち" || g.Outcome != kif.Outcome_SENTE_WIN {
+		t.Errorf("game: %+v", g)
+	}
+
+	k.Headers = k.Headers[:3]
+	if _, err := NewGame("a", k, nil); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestElo(t *testing.T) {
+	games := []*Game{
+		// out of order
+		{Date: day(2), Sente: "B", Gote: "C", Handicap: "角落ち", Outcome: kif.Outcome_SENTE_WIN},
+		{Date: day(1), Sente: "A", Gote: "B", Handicap: "平手", Outcome: kif.Outcome_SENTE_WIN},
+		{Date: day(3), Sente: "A", Gote: "C", Handicap: "平手", Outcome: kif.Outcome_UNFINISHED},
+	}
+	ps := NewElo(EloHandicaps(map[string]float64{"角落ち": 200})).Rate(games)
+	if len(ps) != 3 {
+		t.Fatalf("players: %v", ps)
+	}
+
+	a, b, c := ps[0], ps[1], ps[2]
+	if a.Name != "A" || a.Rating != 1516 || a.Games != 1 || a.Wins != 1 {
+		t.Errorf("A: %+v", a)
+	}
+	// B 1484+200 against C 1500
+	e := 1 / (1 + math.Pow(10, -184.0/400))
+	if b.Name != "B" || !near(b.Rating, 1484+32*(1-e), 1e-9) || len(b.History) != 2 || b.History[0].Rating != 1484 {
+		t.Errorf("B: %+v", b)
+	}
+	if c.Name != "C" || !near(c.Rating, 1500-32*(1-e), 1e-9) || c.Losses != 1 {
+		t.Errorf("C: %+v", c)
+	}
+}
+
+func TestGlicko2_update(t *testing.T) {
+	// the example of Glickman, "Example of the Glicko-2 system"
+	g := NewGlicko2()
+	p := &Player{Rating: 1500, RD: 200, Volatility: 0.06}
+	var results []*glickoResult
+	for _, r := range []struct {
+		rating, rd, score float64
+	}{
+		{1400, 30, 1},
+		{1550, 100, 0},
+		{1700, 300, 0},
+	} {
+		results = append(results, &glickoResult{
+			opMu:  (r.rating - 1500) / glickoScale,
+			opPhi: r.rd / glickoScale,
+			score: r.score,
+		})
+	}
+
+	r, rd, vol := g.update(p, 0, results)
+	if !near(r, 1464.06, 0.01) || !near(rd, 151.52, 0.01) || !near(vol, 0.05999, 0.00001) {
+		t.Errorf("rating=%v rd=%v volatility=%v", r, rd, vol)
+	}
+
+	_, rd, _ = g.update(p, 0, nil)
+	if !near(rd, math.Sqrt(200*200+math.Pow(0.06*glickoScale, 2)), 1e-9) {
+		t.Errorf("rd without games: %v", rd)
+	}
+}
+
+func TestGlicko2(t *testing.T) {
+	games := []*Game{
+		{Date: day(1), Sente: "A", Gote: "B", Handicap: "平手", Outcome: kif.Outcome_SENTE_WIN},
+		{Date: day(1).Add(time.Hour), Sente: "B", Gote: "A", Handicap: "平手", Outcome: kif.Outcome_GOTE_WIN},
+		{Date: day(5), Sente: "C", Gote: "B", Handicap: "平手", Outcome: kif.Outcome_DRAW},
+	}
+	ps := NewGlicko2().Rate(games)
+	if len(ps) != 3 || ps[0].Name != "A" || ps[2].Name != "B" {
+		t.Fatalf("players: %+v", ps)
+	}
+
+	a := ps[0]
+	if a.Games != 2 || a.Wins != 2 || len(a.History) != 1 || a.RD >= 350 {
+		t.Errorf("A: %+v", a)
+	}
+	b := ps[2]
+	if len(b.History) != 2 || b.Draws != 1 || b.History[0].RD <= 0 {
+		t.Errorf("B: %+v", b)
+	}
+	// A did not play and became less certain
+	if a.RD <= a.History[0].RD {
+		t.Errorf("RD of A: %v", a.RD)
+	}
+}
@@ -0,0 +1,173 @@
+package render
+
+// A 5x7 bitmap font for the letters needed by the raster renderer,
+// so that no font files are required.
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+var glyphs = map[rune][glyphHeight]string{
+	'K': {
+		"#...#",
+		"#..#.",
+		"#.#..",
+		"##...",
+		"#.#..",
+		"#..#.",
+		"#...#",
+	},
+	'R': {
+		"####.",
+		"#...#",
+		"#...#",
+		"####.",
+		"#.#..",
+		"#..#.",
+		"#...#",
+	},
+	'B': {
+		"####.",
+		"#...#",
+		"#...#",
+		"####.",
+		"#...#",
+		"#...#",
+		"####.",
+	},
+	'G': {
+		".###.",
+		"#...#",
+		"#....",
+		"#.###",
+		"#...#",
+		"#...#",
+		".###.",
+	},
+	'S': {
+		".####",
+		"#....",
+		"#....",
+		".###.",
+		"....#",
+		"....#",
+		"####.",
+	},
+	'N': {
+		"#...#",
+		"##..#",
+		"#.#.#",
+		"#..##",
+		"#...#",
+		"#...#",
+		"#...#",
+	},
+	'L': {
+		"#....",
+		"#....",
+		"#....",
+		"#....",
+		"#....",
+		"#....",
+		"#####",
+	},
+	'P': {
+		"####.",
+		"#...#",
+		"#...#",
+		"####.",
+		"#....",
+		"#....",
+		"#....",
+	},
+	'0': {
+		".###.",
+		"#...#",
+		"#..##",
+		"#.#.#",
+		"##..#",
+		"#...#",
+		".###.",
+	},
+	'1': {
+		"..#..",
+		".##..",
+		"..#..",
+		"..#..",
+		"..#..",
+		"..#..",
+		".###.",
+	},
+	'2': {
+		".###.",
+		"#...#",
+		"....#",
+		"...#.",
+		"..#..",
+		".#...",
+		"#####",
+	},
+	'3': {
+		"####.",
+		"....#",
+		"....#",
+		".###.",
+		"....#",
+		"....#",
+		"####.",
+	},
+	'4': {
+		"...#.",
+		"..##.",
+		".#.#.",
+		"#..#.",
+		"#####",
+		"...#.",
+		"...#.",
+	},
+	'5': {
+		"#####",
+		"#....",
+		"####.",
+		"....#",
+		"....#",
+		"#...#",
+		".###.",
+	},
+	'6': {
+		"..##.",
+		".#...",
+		"#....",
+		"####.",
+		"#...#",
+		"#...#",
+		".###.",
+	},
+	'7': {
+		"#####",
+		"....#",
+		"...#.",
+		"..#..",
+		".#...",
+		".#...",
+		".#...",
+	},
+	'8': {
+		".###.",
+		"#...#",
+		"#...#",
+		".###.",
+		"#...#",
+		"#...#",
+		".###.",
+	},
+	'9': {
+		".###.",
+		"#...#",
+		"#...#",
+		".####",
+		"....#",
+		"...#.",
+		".##..",
+	},
+}
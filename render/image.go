@@ -0,0 +1,400 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+const (
+	colorBackground uint8 = iota
+	colorBoard
+	colorLine
+	colorPiece
+	colorText
+	colorPromoted
+	colorLastSrc
+	colorLastDst
+)
+
+var palette = color.Palette{
+	colorBackground: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	colorBoard:      color.RGBA{0xf3, 0xd9, 0xa4, 0xff},
+	colorLine:       color.RGBA{0x00, 0x00, 0x00, 0xff},
+	colorPiece:      color.RGBA{0xfb, 0xf0, 0xd8, 0xff},
+	colorText:       color.RGBA{0x20, 0x20, 0x20, 0xff},
+	colorPromoted:   color.RGBA{0xc0, 0x30, 0x30, 0xff},
+	colorLastSrc:    color.RGBA{0xf8, 0xec, 0xc8, 0xff},
+	colorLastDst:    color.RGBA{0xe8, 0xb0, 0x5c, 0xff},
+}
+
+type ImageRenderer struct {
+	squareSize int
+	delay      time.Duration
+	finalDelay time.Duration
+	highlight  bool
+}
+
+type ImageOption func(*ImageRenderer)
+
+func ImageSquareSize(size int) ImageOption {
+	return func(r *ImageRenderer) {
+		r.squareSize = size
+	}
+}
+
+// ImageDelay sets the display time of each frame of animations.
+func ImageDelay(d time.Duration) ImageOption {
+	return func(r *ImageRenderer) {
+		r.delay = d
+	}
+}
+
+// ImageFinalDelay sets the display time of the last frame of animations.
+func ImageFinalDelay(d time.Duration) ImageOption {
+	return func(r *ImageRenderer) {
+		r.finalDelay = d
+	}
+}
+
+func ImageHighlightLastMove(highlight bool) ImageOption {
+	return func(r *ImageRenderer) {
+		r.highlight = highlight
+	}
+}
+
+func NewImageRenderer(ops ...ImageOption) *ImageRenderer {
+	r := &ImageRenderer{
+		squareSize: 32,
+		delay:      time.Second,
+		finalDelay: 5 * time.Second,
+		highlight:  true,
+	}
+	for _, f := range ops {
+		f(r)
+	}
+	return r
+}
+
+type canvas struct {
+	img *image.Paletted
+}
+
+func (c *canvas) fillRect(x, y, w, h int, col uint8) {
+	for j := y; j < y+h; j++ {
+		for i := x; i < x+w; i++ {
+			c.img.SetColorIndex(i, j, col)
+		}
+	}
+}
+
+func (c *canvas) line(x1, y1, x2, y2 int, col uint8) {
+	dx, dy := x2-x1, y2-y1
+	n := dx
+	if n < 0 {
+		n = -n
+	}
+	if dy > n {
+		n = dy
+	} else if -dy > n {
+		n = -dy
+	}
+	if n == 0 {
+		c.img.SetColorIndex(x1, y1, col)
+		return
+	}
+	for i := 0; i <= n; i++ {
+		c.img.SetColorIndex(x1+dx*i/n, y1+dy*i/n, col)
+	}
+}
+
+type point struct {
+	x, y float64
+}
+
+func inPolygon(ps []point, x, y float64) bool {
+	in := false
+	for i, j := 0, len(ps)-1; i < len(ps); j, i = i, i+1 {
+		a, b := ps[i], ps[j]
+		if (a.y > y) != (b.y > y) && x < (b.x-a.x)*(y-a.y)/(b.y-a.y)+a.x {
+			in = !in
+		}
+	}
+	return in
+}
+
+func (c *canvas) polygon(ps []point, fill, stroke uint8) {
+	minX, minY, maxX, maxY := ps[0].x, ps[0].y, ps[0].x, ps[0].y
+	for _, p := range ps {
+		if p.x < minX {
+			minX = p.x
+		}
+		if p.x > maxX {
+			maxX = p.x
+		}
+		if p.y < minY {
+			minY = p.y
+		}
+		if p.y > maxY {
+			maxY = p.y
+		}
+	}
+	for y := int(minY); y <= int(maxY); y++ {
+		for x := int(minX); x <= int(maxX); x++ {
+			if inPolygon(ps, float64(x)+0.5, float64(y)+0.5) {
+				c.img.SetColorIndex(x, y, fill)
+			}
+		}
+	}
+	for i, j := 0, len(ps)-1; i < len(ps); j, i = i, i+1 {
+		c.line(int(ps[j].x), int(ps[j].y), int(ps[i].x), int(ps[i].y), stroke)
+	}
+}
+
+// glyph draws the character centered on (cx, cy).
+func (c *canvas) glyph(cx, cy, scale int, rotate bool, r rune, col uint8) {
+	g, ok := glyphs[r]
+	if !ok {
+		return
+	}
+	x0 := cx - glyphWidth*scale/2
+	y0 := cy - glyphHeight*scale/2
+	for j, row := range g {
+		for i := 0; i < glyphWidth; i++ {
+			if row[i] != '#' {
+				continue
+			}
+			gi, gj := i, j
+			if rotate {
+				gi, gj = glyphWidth-1-i, glyphHeight-1-j
+			}
+			c.fillRect(x0+gi*scale, y0+gj*scale, scale, scale, col)
+		}
+	}
+}
+
+func (c *canvas) text(cx, cy, scale int, rotate bool, s string, col uint8) {
+	rs := []rune(s)
+	advance := (glyphWidth + 1) * scale
+	x := cx - (advance*len(rs)-scale)/2 + glyphWidth*scale/2
+	if rotate {
+		for i := len(rs) - 1; i >= 0; i-- {
+			c.glyph(x, cy, scale, true, rs[i], col)
+			x += advance
+		}
+		return
+	}
+	for _, r := range rs {
+		c.glyph(x, cy, scale, false, r, col)
+		x += advance
+	}
+}
+
+var pieceShape = []point{
+	{0.5, 0.08},
+	{0.8, 0.22},
+	{0.88, 0.92},
+	{0.12, 0.92},
+	{0.2, 0.22},
+}
+
+type imageLayout struct {
+	s      int
+	scale  int
+	boardX int
+	boardY int
+	senteX int
+	handW  int
+	width  int
+	height int
+}
+
+func newImageLayout(s int) *imageLayout {
+	l := &imageLayout{
+		s:     s,
+		scale: s / 10,
+		handW: s * 3 / 2,
+	}
+	if l.scale < 1 {
+		l.scale = 1
+	}
+	pad := s / 2
+	l.boardX = pad + l.handW + pad
+	l.boardY = pad + s/2
+	l.senteX = l.boardX + 9*s + s/2 + pad
+	l.width = l.senteX + l.handW + pad
+	l.height = l.boardY + 9*s + pad
+	return l
+}
+
+func (l *imageLayout) squareOrigin(x, y int32) (int, int) {
+	return l.boardX + (9-int(x))*l.s, l.boardY + (int(y)-1)*l.s
+}
+
+func (r *ImageRenderer) drawPiece(c *canvas, l *imageLayout, x, y int32, sq board.Square) {
+	ox, oy := l.squareOrigin(x, y)
+	s := float64(l.s)
+	ps := make([]point, len(pieceShape))
+	for i, p := range pieceShape {
+		py := p.y
+		if sq.Side == board.Gote {
+			py = 1 - py
+		}
+		ps[i] = point{float64(ox) + p.x*s, float64(oy) + py*s}
+	}
+	c.polygon(ps, colorPiece, colorLine)
+
+	col := colorText
+	if board.IsPromoted(sq.Piece) {
+		col = colorPromoted
+	}
+	cy := oy + l.s/2 + l.s/20
+	if sq.Side == board.Gote {
+		cy = oy + l.s/2 - l.s/20
+	}
+	letter := board.Letter(board.Sente, board.Demote(sq.Piece))
+	c.glyph(ox+l.s/2, cy, l.scale, sq.Side == board.Gote, rune(letter[0]), col)
+}
+
+func (r *ImageRenderer) drawHand(c *canvas, l *imageLayout, b *board.Board, side board.Side) {
+	rotate := side == board.Gote
+	small := l.scale * 2 / 3
+	if small < 1 {
+		small = 1
+	}
+
+	i := 0
+	for _, p := range board.HandPieces {
+		n := b.Hand(side, p)
+		if n == 0 {
+			continue
+		}
+		s := board.Letter(board.Sente, p)
+		var count string
+		if n > 1 {
+			count = strconv.Itoa(n)
+		}
+
+		x := l.senteX
+		y := l.boardY + l.s/2 + i*l.s
+		if rotate {
+			x = l.boardX - l.s/2 - l.handW
+			y = l.boardY + 9*l.s - l.s/2 - i*l.s
+		}
+		lx, nx := x+l.handW/3, x+l.handW*3/4
+		if rotate {
+			lx, nx = x+l.handW*2/3, x+l.handW/4
+		}
+		c.glyph(lx, y, l.scale, rotate, rune(s[0]), colorText)
+		if count != "" {
+			c.text(nx, y, small, rotate, count, colorText)
+		}
+		i++
+	}
+}
+
+// Render draws the position to a paletted image.
+func (r *ImageRenderer) Render(b *board.Board) *image.Paletted {
+	l := newImageLayout(r.squareSize)
+	c := &canvas{
+		img: image.NewPaletted(image.Rect(0, 0, l.width, l.height), palette),
+	}
+	s := l.s
+
+	c.fillRect(l.boardX, l.boardY, 9*s, 9*s, colorBoard)
+
+	if last := b.Last; r.highlight && last != nil {
+		if last.Src != nil && last.Src.X != 0 {
+			x, y := l.squareOrigin(last.Src.X, last.Src.Y)
+			c.fillRect(x, y, s, s, colorLastSrc)
+		}
+		x, y := l.squareOrigin(last.Dst.X, last.Dst.Y)
+		c.fillRect(x, y, s, s, colorLastDst)
+	}
+
+	for i := 0; i <= 9; i++ {
+		c.line(l.boardX+i*s, l.boardY, l.boardX+i*s, l.boardY+9*s, colorLine)
+		c.line(l.boardX, l.boardY+i*s, l.boardX+9*s, l.boardY+i*s, colorLine)
+	}
+
+	small := l.scale * 2 / 3
+	if small < 1 {
+		small = 1
+	}
+	for i := int32(1); i <= 9; i++ {
+		x, _ := l.squareOrigin(i, 1)
+		c.glyph(x+s/2, l.boardY-s/4, small, false, rune('0'+i), colorText)
+		_, y := l.squareOrigin(1, i)
+		c.glyph(l.boardX+9*s+s/4, y+s/2, small, false, rune('0'+i), colorText)
+	}
+
+	for y := int32(1); y <= 9; y++ {
+		for x := int32(1); x <= 9; x++ {
+			if sq := b.At(x, y); !sq.Empty() {
+				r.drawPiece(c, l, x, y, sq)
+			}
+		}
+	}
+
+	r.drawHand(c, l, b, board.Sente)
+	r.drawHand(c, l, b, board.Gote)
+
+	return c.img
+}
+
+func (r *ImageRenderer) WritePNG(w io.Writer, b *board.Board) error {
+	return png.Encode(w, r.Render(b))
+}
+
+// Frames returns images of the initial position and the position after each move.
+func (r *ImageRenderer) Frames(k *ptypes.Kif) ([]*image.Paletted, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*image.Paletted{r.Render(b)}
+	for _, step := range k.GetSteps() {
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		ret = append(ret, r.Render(b))
+	}
+
+	return ret, nil
+}
+
+func centiseconds(d time.Duration) int {
+	return int(d / (10 * time.Millisecond))
+}
+
+// WriteGIF writes the game as an animated GIF with one frame per move.
+func (r *ImageRenderer) WriteGIF(w io.Writer, k *ptypes.Kif) error {
+	frames, err := r.Frames(k)
+	if err != nil {
+		return err
+	}
+
+	delays := make([]int, len(frames))
+	for i := range delays {
+		delays[i] = centiseconds(r.delay)
+	}
+	delays[len(delays)-1] = centiseconds(r.finalDelay)
+
+	return gif.EncodeAll(w, &gif.GIF{
+		Image: frames,
+		Delay: delays,
+	})
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestImageRenderer_WriteGIF(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+			{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}},
+			{Seq: 3, FinishedStatus: ptypes.FinishedStatus_SUSPEND},
+		},
+	}
+
+	var buf bytes.Buffer
+	r := NewImageRenderer(ImageDelay(500*time.Millisecond), ImageFinalDelay(2*time.Second))
+	if err := r.WriteGIF(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if l := len(g.Image); l != 3 {
+		t.Fatalf("frames: expected=3 actual=%v", l)
+	}
+	if d := g.Delay; d[0] != 50 || d[2] != 200 {
+		t.Errorf("unexpected delays: %v", d)
+	}
+}
+
+func TestImageRenderer_Render(t *testing.T) {
+	b := board.New()
+	step := &ptypes.Step{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}}
+	if err := b.Apply(step); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img := NewImageRenderer().Render(b)
+	l := newImageLayout(32)
+
+	x, y := l.squareOrigin(7, 7)
+	if c := img.ColorIndexAt(x+2, y+2); c != colorLastSrc {
+		t.Errorf("source square: expected=%v actual=%v", colorLastSrc, c)
+	}
+	x, y = l.squareOrigin(7, 6)
+	if c := img.ColorIndexAt(x+2, y+2); c != colorLastDst {
+		t.Errorf("destination square: expected=%v actual=%v", colorLastDst, c)
+	}
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type SVGRenderer struct {
+	squareSize int
+	fontFamily string
+	highlight  bool
+	arrow      bool
+}
+
+type SVGOption func(*SVGRenderer)
+
+func SVGSquareSize(size int) SVGOption {
+	return func(r *SVGRenderer) {
+		r.squareSize = size
+	}
+}
+
+func SVGFontFamily(family string) SVGOption {
+	return func(r *SVGRenderer) {
+		r.fontFamily = family
+	}
+}
+
+// SVGHighlightLastMove fills the source and destination squares of the last move.
+func SVGHighlightLastMove(highlight bool) SVGOption {
+	return func(r *SVGRenderer) {
+		r.highlight = highlight
+	}
+}
+
+// SVGArrowLastMove draws an arrow from the source to the destination of the last move.
+func SVGArrowLastMove(arrow bool) SVGOption {
+	return func(r *SVGRenderer) {
+		r.arrow = arrow
+	}
+}
+
+func NewSVGRenderer(ops ...SVGOption) *SVGRenderer {
+	r := &SVGRenderer{
+		squareSize: 40,
+		fontFamily: "serif",
+		highlight:  true,
+	}
+	for _, f := range ops {
+		f(r)
+	}
+	return r
+}
+
+type svgLayout struct {
+	s      int
+	pad    int
+	boardX int
+	boardY int
+	senteX int
+	width  int
+	height int
+}
+
+func newSVGLayout(s int) *svgLayout {
+	l := &svgLayout{
+		s:   s,
+		pad: s / 2,
+	}
+	l.boardX = l.pad + s + l.pad
+	l.boardY = l.pad + s/2
+	l.senteX = l.boardX + 9*s + s/2 + l.pad
+	l.width = l.senteX + s + l.pad
+	l.height = l.boardY + 9*s + l.pad
+	return l
+}
+
+// squareOrigin returns the top left corner of the square.
+func (l *svgLayout) squareOrigin(x, y int32) (int, int) {
+	return l.boardX + (9-int(x))*l.s, l.boardY + (int(y)-1)*l.s
+}
+
+func (l *svgLayout) squareCenter(x, y int32) (int, int) {
+	ox, oy := l.squareOrigin(x, y)
+	return ox + l.s/2, oy + l.s/2
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+type svgWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *svgWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteByte('\n')
+}
+
+func (w *svgWriter) text(x, y, size int, rotate bool, s string) {
+	var transform string
+	if rotate {
+		transform = fmt.Sprintf(` transform="rotate(180 %d %d)"`, x, y)
+	}
+	w.printf(`<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central"%s>%s</text>`,
+		x, y, size, transform, escape(s))
+}
+
+func svgHandGlyphs(b *board.Board, side board.Side) []string {
+	mark := "☗"
+	if side == board.Gote {
+		mark = "☖"
+	}
+	ret := []string{mark}
+	for _, p := range board.HandPieces {
+		n := b.Hand(side, p)
+		if n == 0 {
+			continue
+		}
+		ret = append(ret, board.ShortName(p))
+		if n > 1 {
+			ret = append(ret, kanjiNum(n))
+		}
+	}
+	return ret
+}
+
+func (r *SVGRenderer) Render(w io.Writer, b *board.Board) error {
+	l := newSVGLayout(r.squareSize)
+	s := l.s
+	out := &svgWriter{}
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s">`,
+		l.width, l.height, l.width, l.height, escape(r.fontFamily))
+	out.printf(`<rect x="0" y="0" width="%d" height="%d" fill="#ffffff"/>`, l.width, l.height)
+	out.printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#f3d9a4"/>`, l.boardX, l.boardY, 9*s, 9*s)
+
+	last := b.Last
+	if r.highlight && last != nil {
+		if last.Src != nil && last.Src.X != 0 {
+			x, y := l.squareOrigin(last.Src.X, last.Src.Y)
+			out.printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#f8ecc8"/>`, x, y, s, s)
+		}
+		x, y := l.squareOrigin(last.Dst.X, last.Dst.Y)
+		out.printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#e8b05c"/>`, x, y, s, s)
+	}
+
+	for i := 0; i <= 9; i++ {
+		width := 1
+		if i == 0 || i == 9 {
+			width = 2
+		}
+		out.printf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000000" stroke-width="%d"/>`,
+			l.boardX+i*s, l.boardY, l.boardX+i*s, l.boardY+9*s, width)
+		out.printf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000000" stroke-width="%d"/>`,
+			l.boardX, l.boardY+i*s, l.boardX+9*s, l.boardY+i*s, width)
+	}
+	for _, p := range []int{3, 6} {
+		for _, q := range []int{3, 6} {
+			out.printf(`<circle cx="%d" cy="%d" r="%d" fill="#000000"/>`, l.boardX+p*s, l.boardY+q*s, s/16+1)
+		}
+	}
+
+	coordSize := s * 2 / 5
+	for i := int32(1); i <= 9; i++ {
+		x, _ := l.squareCenter(i, 1)
+		out.text(x, l.boardY-s/4, coordSize, false, string(bodFiles[i]))
+		_, y := l.squareCenter(1, i)
+		out.text(l.boardX+9*s+s/4, y, coordSize, false, string(bodRanks[i]))
+	}
+
+	pieceSize := s * 4 / 5
+	for y := int32(1); y <= 9; y++ {
+		for x := int32(1); x <= 9; x++ {
+			sq := b.At(x, y)
+			if sq.Empty() {
+				continue
+			}
+			cx, cy := l.squareCenter(x, y)
+			out.text(cx, cy, pieceSize, sq.Side == board.Gote, board.ShortName(sq.Piece))
+		}
+	}
+
+	handSize := s * 7 / 10
+	for i, g := range svgHandGlyphs(b, board.Sente) {
+		out.text(l.senteX+s/2, l.boardY+s/2+i*s*3/5, handSize, false, g)
+	}
+	for i, g := range svgHandGlyphs(b, board.Gote) {
+		out.text(l.pad+s/2, l.boardY+9*s-s/2-i*s*3/5, handSize, true, g)
+	}
+
+	if r.arrow && last != nil && last.Src != nil && last.Src.X != 0 {
+		out.printf(`<defs><marker id="arrowhead" markerWidth="4" markerHeight="4" refX="2" refY="2" orient="auto"><path d="M0,0 L4,2 L0,4 z" fill="#c03030"/></marker></defs>`)
+		x1, y1 := l.squareCenter(last.Src.X, last.Src.Y)
+		x2, y2 := l.squareCenter(last.Dst.X, last.Dst.Y)
+		out.printf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#c03030" stroke-width="%d" stroke-opacity="0.7" marker-end="url(#arrowhead)"/>`,
+			x1, y1, x2, y2, s/8+1)
+	}
+
+	out.printf(`</svg>`)
+
+	_, err := w.Write(out.buf.Bytes())
+	return err
+}
+
+// RenderPly renders the position after ply moves of the game.
+func (r *SVGRenderer) RenderPly(w io.Writer, k *ptypes.Kif, ply int32) error {
+	b, err := board.AtPly(k, ply)
+	if err != nil {
+		return err
+	}
+	return r.Render(w, b)
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestSVGRenderer_RenderPly(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+		},
+	}
+
+	var buf bytes.Buffer
+	r := NewSVGRenderer(SVGArrowLastMove(true), SVGFontFamily(`"Noto Serif JP"`))
+	if err := r.RenderPly(&buf, k, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	var rotated, arrows int
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, a := range se.Attr {
+			switch {
+			case a.Name.Local == "transform" && strings.HasPrefix(a.Value, "rotate(180"):
+				rotated++
+			case a.Name.Local == "marker-end":
+				arrows++
+			}
+		}
+	}
+
+	// 20 gote pieces and the ☖ mark of gote's hand
+	if rotated != 21 {
+		t.Errorf("rotated texts: expected=21 actual=%v", rotated)
+	}
+	if arrows != 1 {
+		t.Errorf("arrows: expected=1 actual=%v", arrows)
+	}
+}
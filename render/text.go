@@ -0,0 +1,211 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type TextStyle int
+
+const (
+	TextStyle_BOD TextStyle = iota
+	TextStyle_WESTERN
+)
+
+const (
+	ansiHighlight = "\x1b[7m"
+	ansiReset     = "\x1b[0m"
+)
+
+type TextRenderer struct {
+	style TextStyle
+	color bool
+}
+
+type TextOption func(*TextRenderer)
+
+func SetTextStyle(style TextStyle) TextOption {
+	return func(r *TextRenderer) {
+		r.style = style
+	}
+}
+
+// SetColor enables ANSI escape sequences to highlight the last move.
+func SetColor(color bool) TextOption {
+	return func(r *TextRenderer) {
+		r.color = color
+	}
+}
+
+func NewTextRenderer(ops ...TextOption) *TextRenderer {
+	r := &TextRenderer{
+		style: TextStyle_BOD,
+	}
+	for _, f := range ops {
+		f(r)
+	}
+	return r
+}
+
+func isLastDst(b *board.Board, x, y int32) bool {
+	if b.Last == nil || b.Last.Dst == nil {
+		return false
+	}
+	return b.Last.Dst.X == x && b.Last.Dst.Y == y
+}
+
+func (r *TextRenderer) highlight(s string) string {
+	if !r.color {
+		return s
+	}
+	return ansiHighlight + s + ansiReset
+}
+
+var kanjiNums = []string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+
+func kanjiNum(n int) string {
+	if n <= 10 {
+		return kanjiNums[n]
+	}
+	return kanjiNums[10] + kanjiNums[n-10]
+}
+
+func bodHand(b *board.Board, side board.Side) string {
+	var ss []string
+	for _, p := range board.HandPieces {
+		n := b.Hand(side, p)
+		if n == 0 {
+			continue
+		}
+		s := board.ShortName(p)
+		if n > 1 {
+			s += kanjiNum(n)
+		}
+		ss = append(ss, s)
+	}
+	if len(ss) == 0 {
+		return "なし"
+	}
+	return strings.Join(ss, "　") + "　"
+}
+
+var (
+	bodFiles = []rune(" １２３４５６７８９")
+	bodRanks = []rune(" 一二三四五六七八九")
+)
+
+func (r *TextRenderer) renderBOD(w io.Writer, b *board.Board) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "後手の持駒：%s\n", bodHand(b, board.Gote))
+	sb.WriteString("  ９ ８ ７ ６ ５ ４ ３ ２ １\n")
+	sb.WriteString("+---------------------------+\n")
+	for y := int32(1); y <= 9; y++ {
+		sb.WriteString("|")
+		for x := int32(9); x >= 1; x-- {
+			sq := b.At(x, y)
+			var cell string
+			switch {
+			case sq.Empty():
+				cell = " ・"
+			case sq.Side == board.Gote:
+				cell = "v" + board.ShortName(sq.Piece)
+			default:
+				cell = " " + board.ShortName(sq.Piece)
+			}
+			if isLastDst(b, x, y) {
+				cell = r.highlight(cell)
+			}
+			sb.WriteString(cell)
+		}
+		fmt.Fprintf(&sb, "|%c\n", bodRanks[y])
+	}
+	sb.WriteString("+---------------------------+\n")
+	fmt.Fprintf(&sb, "先手の持駒：%s\n", bodHand(b, board.Sente))
+	if b.Last != nil {
+		fmt.Fprintf(&sb, "手数＝%d  %s  まで\n", b.Ply, kif.PrintMove(b.Last))
+	}
+	fmt.Fprintf(&sb, "%s番\n", b.Turn)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var westernSides = []string{"Sente", "Gote"}
+
+func westernHand(b *board.Board, side board.Side) string {
+	var ss []string
+	for _, p := range board.HandPieces {
+		n := b.Hand(side, p)
+		if n == 0 {
+			continue
+		}
+		s := board.Letter(board.Sente, p)
+		if n > 1 {
+			s += fmt.Sprintf("x%d", n)
+		}
+		ss = append(ss, s)
+	}
+	if len(ss) == 0 {
+		return "-"
+	}
+	return strings.Join(ss, " ")
+}
+
+func (r *TextRenderer) renderWestern(w io.Writer, b *board.Board) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Gote hand: %s\n", westernHand(b, board.Gote))
+	sb.WriteString("     9  8  7  6  5  4  3  2  1\n")
+	sb.WriteString("  +---------------------------+\n")
+	for y := int32(1); y <= 9; y++ {
+		fmt.Fprintf(&sb, "%c |", 'a'+y-1)
+		for x := int32(9); x >= 1; x-- {
+			sq := b.At(x, y)
+			cell := " ."
+			if !sq.Empty() {
+				cell = board.Letter(sq.Side, sq.Piece)
+			}
+			cell = fmt.Sprintf("%3s", cell)
+			if isLastDst(b, x, y) {
+				cell = r.highlight(cell)
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("|\n")
+	}
+	sb.WriteString("  +---------------------------+\n")
+	fmt.Fprintf(&sb, "Sente hand: %s\n", westernHand(b, board.Sente))
+	if b.Last != nil {
+		fmt.Fprintf(&sb, "Move %d: %s\n", b.Ply, kif.StepToMove(b.Last))
+	}
+	fmt.Fprintf(&sb, "%s to move\n", westernSides[b.Turn])
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *TextRenderer) Render(w io.Writer, b *board.Board) error {
+	switch r.style {
+	case TextStyle_BOD:
+		return r.renderBOD(w, b)
+	case TextStyle_WESTERN:
+		return r.renderWestern(w, b)
+	default:
+		return fmt.Errorf("unknown text style: %v", r.style)
+	}
+}
+
+// RenderPly renders the position after ply moves of the game.
+func (r *TextRenderer) RenderPly(w io.Writer, k *ptypes.Kif, ply int32) error {
+	b, err := board.AtPly(k, ply)
+	if err != nil {
+		return err
+	}
+	return r.Render(w, b)
+}
@@ -0,0 +1,43 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestTextRenderer_RenderPly(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+			{Seq: 2, FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewTextRenderer().RenderPly(&buf, k, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"| ・ ・ 歩 ・ ・ ・ ・ ・ ・|六",
+		"|v香v桂v銀v金v玉v金v銀v桂v香|一",
+		"手数＝1  ▲７六歩(77)  まで",
+		"後手番",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%q not found in:\n%s", s, out)
+		}
+	}
+
+	buf.Reset()
+	r := NewTextRenderer(SetTextStyle(TextStyle_WESTERN), SetColor(true))
+	if err := r.RenderPly(&buf, k, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := ansiHighlight + "  P" + ansiReset; !strings.Contains(buf.String(), s) {
+		t.Errorf("highlighted last move not found in:\n%s", buf.String())
+	}
+}
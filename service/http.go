@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+type handler struct {
+	s        ptypes.KifServiceServer
+	maxBytes int64
+	mux      *http.ServeMux
+}
+
+type HandlerOption func(*handler)
+
+// HandlerMaxBytes limits the size of request bodies. The default is 1MiB.
+func HandlerMaxBytes(n int64) HandlerOption {
+	return func(h *handler) {
+		h.maxBytes = n
+	}
+}
+
+// NewHandler returns the JSON/HTTP interface of the service with the endpoints
+// POST /v1/parse, /v1/convert, /v1/validate and /v1/render.
+//
+// A request with Content-Type application/json is the request message in the
+// protobuf JSON mapping. Any other body is taken as the data of the game and the
+// other fields are given as query parameters, e.g.
+// /v1/convert?encoding=UTF8&output_format=JKF. Convert and render then respond
+// with the converted data or the image itself instead of JSON.
+func NewHandler(s ptypes.KifServiceServer, ops ...HandlerOption) http.Handler {
+	h := &handler{
+		s:        s,
+		maxBytes: 1 << 20,
+		mux:      http.NewServeMux(),
+	}
+	for _, f := range ops {
+		f(h)
+	}
+
+	h.mux.HandleFunc("/v1/parse", post(h.parse))
+	h.mux.HandleFunc("/v1/convert", post(h.convert))
+	h.mux.HandleFunc("/v1/validate", post(h.validate))
+	h.mux.HandleFunc("/v1/render", post(h.render))
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func post(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		f(w, r)
+	}
+}
+
+var httpStatus = map[codes.Code]int{
+	codes.InvalidArgument:  http.StatusBadRequest,
+	codes.NotFound:         http.StatusNotFound,
+	codes.DeadlineExceeded: http.StatusGatewayTimeout,
+	codes.Canceled:         499,
+	// request bodies over the limit
+	codes.ResourceExhausted: http.StatusRequestEntityTooLarge,
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code, ok := httpStatus[status.Code(err)]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": status.Convert(err).Message()})
+}
+
+func writeMessage(w http.ResponseWriter, m proto.Message) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{OrigName: true}).Marshal(&buf, m); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+func writeRaw(w http.ResponseWriter, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
+
+// decode reads the request into req and reports whether the body was the raw data.
+func (h *handler) decode(r *http.Request, req proto.Message, setData func([]byte)) (bool, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, h.maxBytes+1))
+	if err != nil {
+		return false, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if int64(len(body)) > h.maxBytes {
+		return false, status.Errorf(codes.ResourceExhausted, "request body too large: limit=%d", h.maxBytes)
+	}
+
+	if t, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); t == "application/json" {
+		if err := jsonpb.Unmarshal(bytes.NewReader(body), req); err != nil {
+			return false, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return false, nil
+	}
+
+	// jsonpb accepts enum names and quoted numbers, so the query is read as a JSON object.
+	fields := map[string]string{}
+	for name, values := range r.URL.Query() {
+		fields[name] = values[len(values)-1]
+	}
+	js, err := json.Marshal(fields)
+	if err != nil {
+		return false, err
+	}
+	if err := jsonpb.Unmarshal(bytes.NewReader(js), req); err != nil {
+		return false, status.Error(codes.InvalidArgument, err.Error())
+	}
+	setData(body)
+	return true, nil
+}
+
+func (h *handler) parse(w http.ResponseWriter, r *http.Request) {
+	req := &ptypes.ParseRequest{}
+	if _, err := h.decode(r, req, func(data []byte) { req.Data = data }); err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.s.Parse(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, res)
+}
+
+func (h *handler) convert(w http.ResponseWriter, r *http.Request) {
+	req := &ptypes.ConvertRequest{}
+	raw, err := h.decode(r, req, func(data []byte) { req.Data = data })
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.s.Convert(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if raw {
+		writeRaw(w, res.ContentType, res.Data)
+		return
+	}
+	writeMessage(w, res)
+}
+
+func (h *handler) validate(w http.ResponseWriter, r *http.Request) {
+	req := &ptypes.ValidateRequest{}
+	if _, err := h.decode(r, req, func(data []byte) { req.Data = data }); err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.s.Validate(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, res)
+}
+
+func (h *handler) render(w http.ResponseWriter, r *http.Request) {
+	req := &ptypes.RenderPositionRequest{}
+	raw, err := h.decode(r, req, func(data []byte) { req.Data = data })
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := h.s.RenderPosition(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if raw {
+		w.Header().Set("X-Sfen", res.Sfen)
+		writeRaw(w, res.ContentType, res.Image)
+		return
+	}
+	writeMessage(w, res)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	ts := httptest.NewServer(NewHandler(NewServer()))
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/v1/convert?encoding=UTF8&output_format=JKF", "text/plain", strings.NewReader(testKIF))
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected response: %v %v\n%s", res.Status, res.Header, body)
+	}
+	if !strings.Contains(string(body), `"preset": "HIRATE"`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	req := `{"data":"` + base64.StdEncoding.EncodeToString([]byte(testKIF)) + `","encoding":"UTF8","ply":-1,"image_format":"TEXT"}`
+	res, err = http.Post(ts.URL+"/v1/render", "application/json", strings.NewReader(req))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var rr struct {
+		Image       []byte `json:"image"`
+		ContentType string `json:"content_type"`
+		Sfen        string `json:"sfen"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rr); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	res.Body.Close()
+	if rr.Sfen == "" || len(rr.Image) == 0 || rr.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected response: %+v", rr)
+	}
+
+	res, err = http.Post(ts.URL+"/v1/parse?format=NOPE", "text/plain", strings.NewReader(testKIF))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+
+	small := httptest.NewServer(NewHandler(NewServer(), HandlerMaxBytes(16)))
+	defer small.Close()
+	res, err = http.Post(small.URL+"/v1/parse?encoding=UTF8", "text/plain", strings.NewReader(testKIF))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+
+	res, err = http.Get(ts.URL + "/v1/parse")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+}
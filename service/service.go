@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/render"
+)
+
+// Server implements ptypes.KifServiceServer.
+type Server struct {
+	svg   *render.SVGRenderer
+	image *render.ImageRenderer
+	text  *render.TextRenderer
+}
+
+var _ ptypes.KifServiceServer = (*Server)(nil)
+
+type ServerOption func(*Server)
+
+// ServerSVGRenderer sets the renderer of the SVG images.
+func ServerSVGRenderer(r *render.SVGRenderer) ServerOption {
+	return func(s *Server) {
+		s.svg = r
+	}
+}
+
+// ServerImageRenderer sets the renderer of the PNG images.
+func ServerImageRenderer(r *render.ImageRenderer) ServerOption {
+	return func(s *Server) {
+		s.image = r
+	}
+}
+
+// ServerTextRenderer sets the renderer of the text diagrams.
+func ServerTextRenderer(r *render.TextRenderer) ServerOption {
+	return func(s *Server) {
+		s.text = r
+	}
+}
+
+func NewServer(ops ...ServerOption) *Server {
+	s := &Server{
+		svg:   render.NewSVGRenderer(),
+		image: render.NewImageRenderer(),
+		text:  render.NewTextRenderer(),
+	}
+	for _, f := range ops {
+		f(s)
+	}
+	return s
+}
+
+var inputFormats = map[ptypes.Format_Id]kif.InputFormat{
+	ptypes.Format_CSA:  kif.InputFormat_CSA,
+	ptypes.Format_JKF:  kif.InputFormat_JKF,
+	ptypes.Format_SFEN: kif.InputFormat_SFEN,
+}
+
+// parse reads the data in the format. The encoding is used for KIF, and
+// detected for the other formats.
+func parse(data []byte, format ptypes.Format_Id, enc ptypes.Encoding_Id) (*ptypes.Kif, error) {
+	var k *ptypes.Kif
+	var err error
+	if format == ptypes.Format_KIF {
+		var ops []kif.ParseOption
+		if enc == ptypes.Encoding_UTF8 {
+			ops = append(ops, kif.ParseEncodingUTF8())
+		}
+		k, err = kif.NewParser(ops...).Parse(bytes.NewReader(data))
+	} else {
+		f, ok := inputFormats[format]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported input format: %v", format)
+		}
+		k, err = kif.ReadAny(bytes.NewReader(data), kif.ReadFormat(f))
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return k, nil
+}
+
+var formats = map[ptypes.Format_Id]kif.Format{
+	ptypes.Format_KIF:  kif.Format_KIF,
+	ptypes.Format_CSA:  kif.Format_CSA,
+	ptypes.Format_JKF:  kif.Format_JKF,
+	ptypes.Format_SFEN: kif.Format_SFEN,
+	ptypes.Format_HTML: kif.Format_HTML,
+}
+
+func contentType(format ptypes.Format_Id, enc ptypes.Encoding_Id) string {
+	switch format {
+	case ptypes.Format_JKF:
+		return "application/json"
+	case ptypes.Format_HTML:
+		return "text/html; charset=utf-8"
+	case ptypes.Format_KIF, ptypes.Format_CSA:
+		if enc == ptypes.Encoding_SHIFT_JIS {
+			return "text/plain; charset=Shift_JIS"
+		}
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func (s *Server) Parse(ctx context.Context, req *ptypes.ParseRequest) (*ptypes.ParseResponse, error) {
+	k, err := parse(req.Data, req.Format, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &ptypes.ParseResponse{Kif: k}, nil
+}
+
+func (s *Server) Convert(ctx context.Context, req *ptypes.ConvertRequest) (*ptypes.ConvertResponse, error) {
+	k, err := parse(req.Data, req.Format, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	format, ok := formats[req.OutputFormat]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown output format: %v", req.OutputFormat)
+	}
+	ops := []kif.WriterOption{kif.SetFormat(format)}
+	if req.OutputEncoding == ptypes.Encoding_UTF8 {
+		ops = append(ops, kif.WriteEncodingUTF8())
+	}
+	var buf bytes.Buffer
+	if err := kif.NewWriter(ops...).Write(&buf, k); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &ptypes.ConvertResponse{
+		Data:        buf.Bytes(),
+		ContentType: contentType(req.OutputFormat, req.OutputEncoding),
+	}, nil
+}
+
+// Validate reports unreadable input as a validation error rather than an RPC error.
+func (s *Server) Validate(ctx context.Context, req *ptypes.ValidateRequest) (*ptypes.ValidateResponse, error) {
+	k, err := parse(req.Data, req.Format, req.Encoding)
+	if err != nil {
+		if status.Code(err) != codes.InvalidArgument {
+			return nil, err
+		}
+		return &ptypes.ValidateResponse{
+			Errors: []*ptypes.ValidationError{{Message: status.Convert(err).Message()}},
+		}, nil
+	}
+
+	res := &ptypes.ValidateResponse{Valid: true}
+	for _, e := range kif.Validate(k) {
+		res.Valid = false
+		res.Errors = append(res.Errors, &ptypes.ValidationError{Seq: e.Seq, Message: e.Message})
+	}
+	return res, nil
+}
+
+func (s *Server) RenderPosition(ctx context.Context, req *ptypes.RenderPositionRequest) (*ptypes.RenderPositionResponse, error) {
+	k, err := parse(req.Data, req.Format, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	b, err := board.AtPly(k, req.Ply)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &ptypes.RenderPositionResponse{Sfen: b.SFEN()}
+	var buf bytes.Buffer
+	switch req.ImageFormat {
+	case ptypes.ImageFormat_SVG:
+		err = s.svg.Render(&buf, b)
+		res.ContentType = "image/svg+xml"
+	case ptypes.ImageFormat_PNG:
+		err = s.image.WritePNG(&buf, b)
+		res.ContentType = "image/png"
+	case ptypes.ImageFormat_TEXT:
+		err = s.text.Render(&buf, b)
+		res.ContentType = "text/plain; charset=utf-8"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown image format: %v", req.ImageFormat)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res.Image = buf.Bytes()
+
+	return res, nil
+}
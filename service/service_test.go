@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+const testKIF = `手合割：平手
+先手：A
+後手：B
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:01/00:00:01)
+   2 ３四歩(33)   ( 0:02/00:00:02)
+   3 ２二角成(88) ( 0:03/00:00:04)
+   4 同　銀(31)   ( 0:04/00:00:06)
+   5 投了         ( 0:05/00:00:09)
+`
+
+func dial(t *testing.T) (ptypes.KifServiceClient, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer()
+	ptypes.RegisterKifServiceServer(s, NewServer())
+	go s.Serve(l)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	return ptypes.NewKifServiceClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestServer(t *testing.T) {
+	c, stop := dial(t)
+	defer stop()
+	ctx := context.Background()
+
+	pres, err := c.Parse(ctx, &ptypes.ParseRequest{
+		Data:     []byte(testKIF),
+		Encoding: ptypes.Encoding_UTF8,
+	})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if l := len(pres.Kif.Steps); l != 5 {
+		t.Errorf("steps: %v", l)
+	}
+
+	cres, err := c.Convert(ctx, &ptypes.ConvertRequest{
+		Data:         []byte(testKIF),
+		Encoding:     ptypes.Encoding_UTF8,
+		OutputFormat: ptypes.Format_JKF,
+	})
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if cres.ContentType != "application/json" || !strings.Contains(string(cres.Data), `"special": "TORYO"`) {
+		t.Errorf("unexpected conversion: %v\n%s", cres.ContentType, cres.Data)
+	}
+
+	// JKF back to KIF in UTF-8
+	cres, err = c.Convert(ctx, &ptypes.ConvertRequest{
+		Data:           cres.Data,
+		Format:         ptypes.Format_JKF,
+		OutputEncoding: ptypes.Encoding_UTF8,
+	})
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if !strings.Contains(string(cres.Data), "２二銀(31)") {
+		t.Errorf("unexpected conversion:\n%s", cres.Data)
+	}
+
+	rres, err := c.RenderPosition(ctx, &ptypes.RenderPositionRequest{
+		Data:        []byte(testKIF),
+		Encoding:    ptypes.Encoding_UTF8,
+		Ply:         2,
+		ImageFormat: ptypes.ImageFormat_PNG,
+	})
+	if err != nil {
+		t.Fatalf("RenderPosition: %v", err)
+	}
+	if exp := "lnsgkgsnl/1r5b1/pppppp1pp/6p2/9/2P6/PP1PPPPPP/1B5R1/LNSGKGSNL b - 3"; rres.Sfen != exp {
+		t.Errorf("sfen: expected=%v actual=%v", exp, rres.Sfen)
+	}
+	if rres.ContentType != "image/png" || !strings.HasPrefix(string(rres.Image), "\x89PNG") {
+		t.Errorf("unexpected image: %v", rres.ContentType)
+	}
+
+	for _, req := range []*ptypes.ParseRequest{
+		{Data: []byte("startpos moves 7g7f 3c3d"), Format: ptypes.Format_SFEN},
+		{Data: []byte("V2.2\nPI\n+\n+7776FU\n-3334FU\n"), Format: ptypes.Format_CSA},
+	} {
+		pres, err := c.Parse(ctx, req)
+		if err != nil {
+			t.Fatalf("Parse %v: %v", req.Format, err)
+		}
+		if l := len(pres.Kif.Steps); l != 2 {
+			t.Errorf("%v steps: %v", req.Format, l)
+		}
+	}
+
+	for _, req := range []*ptypes.ParseRequest{
+		{Data: []byte("x"), Format: ptypes.Format_SFEN},
+		{Data: []byte(testKIF), Format: ptypes.Format_HTML},
+	} {
+		_, err = c.Parse(ctx, req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: unexpected error: %v", req.Format, err)
+		}
+	}
+}
+
+func TestServer_Validate(t *testing.T) {
+	s := NewServer()
+	ctx := context.Background()
+
+	res, err := s.Validate(ctx, &ptypes.ValidateRequest{
+		Data:     []byte(testKIF),
+		Encoding: ptypes.Encoding_UTF8,
+	})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !res.Valid || len(res.Errors) != 0 {
+		t.Errorf("unexpected errors: %v", res.Errors)
+	}
+
+	illegal := strings.Replace(testKIF, "３四歩(33)", "３五歩(33)", 1)
+	res, err = s.Validate(ctx, &ptypes.ValidateRequest{
+		Data:     []byte(illegal),
+		Encoding: ptypes.Encoding_UTF8,
+	})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if res.Valid || len(res.Errors) == 0 || res.Errors[0].Seq != 2 {
+		t.Errorf("unexpected result: %v", res)
+	}
+
+	res, err = s.Validate(ctx, &ptypes.ValidateRequest{
+		Data:   []byte("{"),
+		Format: ptypes.Format_JKF,
+	})
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if res.Valid || len(res.Errors) != 1 {
+		t.Errorf("unexpected result: %v", res)
+	}
+}
@@ -3,7 +3,12 @@ package kif
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
 
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
 	"github.com/yunomu/kif/ptypes"
 )
 
@@ -77,3 +82,69 @@ func writeSFEN(w io.Writer, steps []*ptypes.Step) error {
 
 	return nil
 }
+
+// ParseSFEN reads a game in the USI position notation: "[position] startpos
+// [moves ...]" or "[position] sfen <sfen> [moves ...]". A bare SFEN is also
+// accepted. Only the initial positions of 手合割 are supported.
+func ParseSFEN(in io.Reader) (*ptypes.Kif, error) {
+	bs, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(bs))
+	if len(fields) != 0 && fields[0] == "position" {
+		fields = fields[1:]
+	}
+
+	var sfen string
+	switch {
+	case len(fields) == 0:
+		return nil, fmt.Errorf("empty sfen")
+	case fields[0] == "startpos":
+		sfen = board.StartPos
+		fields = fields[1:]
+	default:
+		if fields[0] == "sfen" {
+			fields = fields[1:]
+		}
+		n := len(fields)
+		for i, f := range fields {
+			if f == "moves" {
+				n = i
+				break
+			}
+		}
+		sfen = strings.Join(fields[:n], " ")
+		fields = fields[n:]
+	}
+
+	b, err := board.FromSFEN(sfen)
+	if err != nil {
+		return nil, err
+	}
+	handicap, ok := board.HandicapName(b)
+	if !ok {
+		return nil, fmt.Errorf("unsupported initial position: %q", sfen)
+	}
+	k := &ptypes.Kif{
+		Headers: []*ptypes.Header{{Name: "手合割", Value: handicap}},
+	}
+
+	if len(fields) != 0 {
+		if fields[0] != "moves" {
+			return nil, fmt.Errorf("unexpected %q", fields[0])
+		}
+		for _, m := range fields[1:] {
+			step, err := b.StepFromUSI(m)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.Apply(step); err != nil {
+				return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+			}
+			k.Steps = append(k.Steps, step)
+		}
+	}
+
+	return k, nil
+}
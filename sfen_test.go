@@ -1,6 +1,8 @@
 package kif
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/yunomu/kif/ptypes"
@@ -23,3 +25,29 @@ func TestSFENPos(t *testing.T) {
 		t.Errorf("expected=1a actual=%v", s)
 	}
 }
+
+func TestParseSFEN(t *testing.T) {
+	k, err := ParseSFEN(strings.NewReader("position sfen lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1 moves 7g7f 3c3d 8h2b+"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(k.Steps) != 3 || k.Steps[2].Modifier != ptypes.Modifier_PROMOTE {
+		t.Errorf("unexpected steps: %v", k.Steps)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(SetFormat(Format_SFEN)).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := "position startpos moves 7g7f 3c3d 8h2b+"; buf.String() != exp {
+		t.Errorf("expected=%v actual=%v", exp, buf.String())
+	}
+
+	k, err = ParseSFEN(strings.NewReader("lnsgkgsn1/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL w - 1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h := k.Headers[0]; h.Value != "香落ち" {
+		t.Errorf("unexpected header: %v", h)
+	}
+}
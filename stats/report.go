@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Tables are the names of the tables of a report.
+var Tables = []string{
+	"results",
+	"handicaps",
+	"lengths",
+	"finishes",
+	"thinking",
+	"openings",
+	"players",
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', 3, 64)
+}
+
+func resultsRow(r *Results) []string {
+	return []string{itoa(r.Games), itoa(r.SenteWins), itoa(r.GoteWins), itoa(r.Draws), itoa(r.Unfinished)}
+}
+
+var resultsHeader = []string{"games", "sente_wins", "gote_wins", "draws", "unfinished"}
+
+// table returns the header and the rows of the table.
+func (r *Report) table(name string) ([]string, [][]string, error) {
+	var rows [][]string
+	switch name {
+	case "results":
+		return resultsHeader, [][]string{resultsRow(&r.Results)}, nil
+	case "handicaps":
+		for _, h := range r.Handicaps {
+			rows = append(rows, append([]string{h.Handicap}, resultsRow(&h.Results)...))
+		}
+		return append([]string{"handicap"}, resultsHeader...), rows, nil
+	case "lengths":
+		for _, l := range r.Lengths {
+			rows = append(rows, []string{itoa(l.Min), itoa(l.Max), itoa(l.Games)})
+		}
+		return []string{"min", "max", "games"}, rows, nil
+	case "finishes":
+		for _, f := range r.Finishes {
+			rows = append(rows, []string{f.Status, itoa(f.Games)})
+		}
+		return []string{"status", "games"}, rows, nil
+	case "thinking":
+		for _, t := range r.Thinking {
+			rows = append(rows, []string{strconv.Itoa(int(t.Seq)), itoa(t.Moves), ftoa(t.Average)})
+		}
+		return []string{"seq", "moves", "average_sec"}, rows, nil
+	case "openings":
+		for _, op := range r.Openings {
+			rows = append(rows, append([]string{op.Opening}, resultsRow(&op.Results)...))
+		}
+		return append([]string{"opening"}, resultsHeader...), rows, nil
+	case "players":
+		for _, p := range r.Players {
+			rows = append(rows, []string{p.Name, itoa(p.Games), itoa(p.Wins), itoa(p.Losses), itoa(p.Draws), ftoa(p.WinRate)})
+		}
+		return []string{"name", "games", "wins", "losses", "draws", "win_rate"}, rows, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown table: %v", name)
+	}
+}
+
+// WriteCSV writes the named table with a header line.
+func (r *Report) WriteCSV(w io.Writer, name string) error {
+	header, rows, err := r.table(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteText writes the named tables aligned for humans, or all the tables if
+// no name is given.
+func (r *Report) WriteText(w io.Writer, names ...string) error {
+	if len(names) == 0 {
+		names = Tables
+	}
+	for i, name := range names {
+		header, rows, err := r.table(name)
+		if err != nil {
+			return err
+		}
+		if i != 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "[%s]\n", name); err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,346 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/strategy"
+)
+
+const (
+	defaultLengthBucket = 10
+	defaultMaxSeq       = 200
+
+	evenHandicap = "平手"
+)
+
+// Results counts the games and their results.
+type Results struct {
+	Games      int `json:"games"`
+	SenteWins  int `json:"senteWins"`
+	GoteWins   int `json:"goteWins"`
+	Draws      int `json:"draws"`
+	Unfinished int `json:"unfinished"`
+}
+
+func (r *Results) add(o kif.Outcome) {
+	r.Games++
+	switch o {
+	case kif.Outcome_SENTE_WIN:
+		r.SenteWins++
+	case kif.Outcome_GOTE_WIN:
+		r.GoteWins++
+	case kif.Outcome_DRAW:
+		r.Draws++
+	default:
+		r.Unfinished++
+	}
+}
+
+type HandicapResults struct {
+	Handicap string `json:"handicap"`
+	Results
+}
+
+// LengthCount is the number of the games with Min to Max moves.
+type LengthCount struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Games int `json:"games"`
+}
+
+type FinishCount struct {
+	// Status is the name of the finishing step, or NOT_FINISHED.
+	Status string `json:"status"`
+	Games  int    `json:"games"`
+}
+
+// ThinkingTime is the average thinking time of the move Seq in the games
+// with times.
+type ThinkingTime struct {
+	Seq     int32   `json:"seq"`
+	Moves   int     `json:"moves"`
+	Average float64 `json:"average"`
+}
+
+type OpeningCount struct {
+	Opening string `json:"opening"`
+	Results
+}
+
+type PlayerResults struct {
+	Name   string `json:"name"`
+	Games  int    `json:"games"`
+	Wins   int    `json:"wins"`
+	Losses int    `json:"losses"`
+	Draws  int    `json:"draws"`
+	// WinRate is the score in the finished games, counting a draw as half
+	// a win, or 0.5 if no game is finished.
+	WinRate float64 `json:"winRate"`
+}
+
+type Report struct {
+	Results   Results            `json:"results"`
+	Handicaps []*HandicapResults `json:"handicaps"`
+	Lengths   []*LengthCount     `json:"lengths"`
+	Finishes  []*FinishCount     `json:"finishes"`
+	Thinking  []*ThinkingTime    `json:"thinking"`
+	Openings  []*OpeningCount    `json:"openings"`
+	Players   []*PlayerResults   `json:"players"`
+}
+
+type thinking struct {
+	moves int
+	sec   float64
+}
+
+// Collector aggregates games into a Report.
+type Collector struct {
+	lengthBucket int
+	maxSeq       int32
+
+	results   Results
+	handicaps map[string]*HandicapResults
+	lengths   map[int]int
+	finishes  map[string]int
+	thinking  map[int32]*thinking
+	openings  map[string]*OpeningCount
+	players   map[string]*PlayerResults
+}
+
+type CollectorOption func(*Collector)
+
+// CollectorLengthBucket sets the width of the game length distribution in moves.
+func CollectorLengthBucket(n int) CollectorOption {
+	return func(c *Collector) {
+		c.lengthBucket = n
+	}
+}
+
+// CollectorMaxSeq sets the last move number of the thinking times.
+func CollectorMaxSeq(n int) CollectorOption {
+	return func(c *Collector) {
+		c.maxSeq = int32(n)
+	}
+}
+
+func NewCollector(ops ...CollectorOption) *Collector {
+	c := &Collector{
+		lengthBucket: defaultLengthBucket,
+		maxSeq:       defaultMaxSeq,
+		handicaps:    make(map[string]*HandicapResults),
+		lengths:      make(map[int]int),
+		finishes:     make(map[string]int),
+		thinking:     make(map[int32]*thinking),
+		openings:     make(map[string]*OpeningCount),
+		players:      make(map[string]*PlayerResults),
+	}
+	for _, f := range ops {
+		f(c)
+	}
+	if c.lengthBucket < 1 {
+		c.lengthBucket = 1
+	}
+	return c
+}
+
+// header returns the first non-empty value of the names.
+func header(k *ptypes.Kif, names ...string) string {
+	for _, name := range names {
+		for _, h := range k.GetHeaders() {
+			if h.Name == name && strings.TrimSpace(h.Value) != "" {
+				return h.Value
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Collector) player(name string) *PlayerResults {
+	p, ok := c.players[name]
+	if !ok {
+		p = &PlayerResults{Name: name}
+		c.players[name] = p
+	}
+	return p
+}
+
+func (c *Collector) addPlayer(name string, won, lost bool, o kif.Outcome) {
+	if name == "" {
+		return
+	}
+	p := c.player(name)
+	p.Games++
+	switch {
+	case won:
+		p.Wins++
+	case lost:
+		p.Losses++
+	case o == kif.Outcome_DRAW:
+		p.Draws++
+	}
+}
+
+// Add adds the main line of the game. The opening is the 戦型 header, or the
+// classified opening if it is missing. Games which cannot be classified are
+// not added.
+func (c *Collector) Add(k *ptypes.Kif) error {
+	o, err := kif.GetOutcome(k)
+	if err != nil {
+		return err
+	}
+	opening := header(k, "戦型")
+	if opening == "" {
+		class, err := strategy.Classify(k)
+		if err != nil {
+			return err
+		}
+		opening = class.Opening
+	}
+
+	c.results.add(o)
+
+	handicap := header(k, "手合割")
+	if handicap == "" {
+		handicap = evenHandicap
+	}
+	h, ok := c.handicaps[handicap]
+	if !ok {
+		h = &HandicapResults{Handicap: handicap}
+		c.handicaps[handicap] = h
+	}
+	h.add(o)
+
+	if opening != "" {
+		op, ok := c.openings[opening]
+		if !ok {
+			op = &OpeningCount{Opening: opening}
+			c.openings[opening] = op
+		}
+		op.add(o)
+	}
+
+	var moves []*ptypes.Step
+	status := ptypes.FinishedStatus_NOT_FINISHED
+	timed := false
+	for _, s := range k.GetSteps() {
+		if s.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			status = s.FinishedStatus
+			break
+		}
+		moves = append(moves, s)
+		if kif.ThinkingDuration(s) != 0 {
+			timed = true
+		}
+	}
+	c.finishes[status.String()]++
+	c.lengths[len(moves)/c.lengthBucket]++
+
+	if timed {
+		for _, s := range moves {
+			if s.Seq > c.maxSeq {
+				break
+			}
+			t, ok := c.thinking[s.Seq]
+			if !ok {
+				t = &thinking{}
+				c.thinking[s.Seq] = t
+			}
+			t.moves++
+			t.sec += kif.ThinkingDuration(s).Seconds()
+		}
+	}
+
+	c.addPlayer(header(k, "先手", "下手"), o == kif.Outcome_SENTE_WIN, o == kif.Outcome_GOTE_WIN, o)
+	c.addPlayer(header(k, "後手", "上手"), o == kif.Outcome_GOTE_WIN, o == kif.Outcome_SENTE_WIN, o)
+
+	return nil
+}
+
+// Report returns the statistics of the games added. The counts are in the
+// descending order of the games, and the distributions in the ascending order.
+func (c *Collector) Report() *Report {
+	r := &Report{
+		Results:   c.results,
+		Handicaps: []*HandicapResults{},
+		Lengths:   []*LengthCount{},
+		Finishes:  []*FinishCount{},
+		Thinking:  []*ThinkingTime{},
+		Openings:  []*OpeningCount{},
+		Players:   []*PlayerResults{},
+	}
+
+	for _, h := range c.handicaps {
+		v := *h
+		r.Handicaps = append(r.Handicaps, &v)
+	}
+	sort.Slice(r.Handicaps, func(i, j int) bool {
+		a, b := r.Handicaps[i], r.Handicaps[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Handicap < b.Handicap
+	})
+
+	maxBucket := -1
+	for b := range c.lengths {
+		if b > maxBucket {
+			maxBucket = b
+		}
+	}
+	for b := 0; b <= maxBucket; b++ {
+		r.Lengths = append(r.Lengths, &LengthCount{
+			Min:   b * c.lengthBucket,
+			Max:   (b+1)*c.lengthBucket - 1,
+			Games: c.lengths[b],
+		})
+	}
+
+	for status, n := range c.finishes {
+		r.Finishes = append(r.Finishes, &FinishCount{Status: status, Games: n})
+	}
+	sort.Slice(r.Finishes, func(i, j int) bool {
+		a, b := r.Finishes[i], r.Finishes[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Status < b.Status
+	})
+
+	for seq, t := range c.thinking {
+		r.Thinking = append(r.Thinking, &ThinkingTime{Seq: seq, Moves: t.moves, Average: t.sec / float64(t.moves)})
+	}
+	sort.Slice(r.Thinking, func(i, j int) bool { return r.Thinking[i].Seq < r.Thinking[j].Seq })
+
+	for _, op := range c.openings {
+		v := *op
+		r.Openings = append(r.Openings, &v)
+	}
+	sort.Slice(r.Openings, func(i, j int) bool {
+		a, b := r.Openings[i], r.Openings[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Opening < b.Opening
+	})
+
+	for _, p := range c.players {
+		v := *p
+		v.WinRate = 0.5
+		if n := v.Wins + v.Losses + v.Draws; n != 0 {
+			v.WinRate = (float64(v.Wins) + float64(v.Draws)/2) / float64(n)
+		}
+		r.Players = append(r.Players, &v)
+	}
+	sort.Slice(r.Players, func(i, j int) bool {
+		a, b := r.Players[i], r.Players[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Name < b.Name
+	})
+
+	return r
+}
@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(CollectorLengthBucket(2))
+
+	var games []*ptypes.Kif
+	for _, g := range []struct {
+		sente, gote, sfen string
+		status            ptypes.FinishedStatus_Id
+		thinking          []int32
+	}{
+		// 後手 resigns after 3 moves
+		{"A", "B", "startpos moves 7g7f 3c3d 2g2f", ptypes.FinishedStatus_SURRENDER, []int32{10, 20}},
+		// 先手 resigns
+		{"B", "A", "startpos moves 7g7f 3c3d 2g2f 4c4d", ptypes.FinishedStatus_SURRENDER, []int32{30}},
+		{"A", "C", "startpos moves 7g7f", ptypes.FinishedStatus_NOT_FINISHED, nil},
+		{"A", "C", "startpos moves 7g7f", ptypes.FinishedStatus_NOT_FINISHED, nil},
+	} {
+		k, err := kif.ParseSFEN(strings.NewReader(g.sfen))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", g.sfen, err)
+		}
+		k.Headers = append(k.Headers,
+			&ptypes.Header{Name: "先手", Value: g.sente},
+			&ptypes.Header{Name: "後手", Value: g.gote},
+		)
+		if g.status != ptypes.FinishedStatus_NOT_FINISHED {
+			k.Steps = append(k.Steps, &ptypes.Step{Seq: int32(len(k.Steps)) + 1, FinishedStatus: g.status})
+		}
+		for i, sec := range g.thinking {
+			k.Steps[i].ThinkingSec = sec
+		}
+		games = append(games, k)
+	}
+	illegal := games[3]
+	illegal.Steps[0].Src = &ptypes.Pos{X: 5, Y: 5}
+
+	for _, k := range games[:3] {
+		if err := c.Add(k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := c.Add(illegal); err == nil {
+		t.Errorf("expected error")
+	}
+
+	r := c.Report()
+	if v := r.Results; v.Games != 3 || v.SenteWins != 1 || v.GoteWins != 1 || v.Unfinished != 1 {
+		t.Errorf("results: %+v", v)
+	}
+	if len(r.Handicaps) != 1 || r.Handicaps[0].Handicap != "平手" || r.Handicaps[0].Games != 3 {
+		t.Errorf("handicaps: %+v", r.Handicaps)
+	}
+	if len(r.Lengths) != 3 || r.Lengths[0].Games != 1 || r.Lengths[1].Games != 1 || r.Lengths[2].Games != 1 {
+		t.Errorf("lengths: %+v", r.Lengths)
+	}
+	if len(r.Finishes) != 2 || r.Finishes[0].Status != "SURRENDER" || r.Finishes[0].Games != 2 {
+		t.Errorf("finishes: %+v", r.Finishes)
+	}
+	if th := r.Thinking; len(th) != 4 || th[0].Moves != 2 || th[0].Average != 20 || th[1].Average != 10 {
+		t.Errorf("thinking: %+v", th)
+	}
+
+	if len(r.Players) != 3 {
+		t.Fatalf("players: %+v", r.Players)
+	}
+	if p := r.Players[0]; p.Name != "A" || p.Games != 3 || p.Wins != 2 || p.Losses != 0 || p.WinRate != 1 {
+		t.Errorf("player A: %+v", p)
+	}
+	if p := r.Players[1]; p.Name != "B" || p.Wins != 0 || p.Losses != 2 || p.WinRate != 0 {
+		t.Errorf("player B: %+v", p)
+	}
+}
+
+func TestCollector_headers(t *testing.T) {
+	c := NewCollector()
+
+	// 四間飛車 by the moves
+	a, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f 3c3d 2h6h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a.Headers = append(a.Headers, &ptypes.Header{Name: "戦型", Value: "矢倉"})
+	if err := c.Add(a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h, err := kif.ParseSFEN(strings.NewReader("sfen lnsgkgsnl/1r7/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL w - 1 moves 3c3d"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Headers = append(h.Headers,
+		&ptypes.Header{Name: "下手", Value: "A"},
+		&ptypes.Header{Name: "上手", Value: "B"},
+	)
+	h.Steps = append(h.Steps, &ptypes.Step{Seq: 2, FinishedStatus: ptypes.FinishedStatus_SURRENDER})
+	if err := c.Add(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := c.Report()
+	if len(r.Openings) != 1 || r.Openings[0].Opening != "矢倉" {
+		t.Errorf("openings: %+v", r.Openings)
+	}
+	if len(r.Players) != 2 {
+		t.Fatalf("players: %+v", r.Players)
+	}
+	// 下手 resigns
+	if p := r.Players[0]; p.Name != "A" || p.Losses != 1 {
+		t.Errorf("player A: %+v", p)
+	}
+	if p := r.Players[1]; p.Name != "B" || p.Wins != 1 {
+		t.Errorf("player B: %+v", p)
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	k, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k.Headers = append(k.Headers,
+		&ptypes.Header{Name: "先手", Value: "A"},
+		&ptypes.Header{Name: "後手", Value: "B"},
+	)
+	k.Steps = append(k.Steps, &ptypes.Step{Seq: 2, FinishedStatus: ptypes.FinishedStatus_SURRENDER})
+
+	c := NewCollector()
+	if err := c.Add(k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := c.Report()
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf, "players"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `name,games,wins,losses,draws,win_rate
+A,1,1,0,0,1.000
+B,1,0,1,0,0.000
+`
+	if s := buf.String(); s != expected {
+		t.Errorf("expected=%v\nactual=%v", expected, s)
+	}
+
+	if err := r.WriteCSV(&buf, "unknown"); err == nil {
+		t.Errorf("expected error")
+	}
+
+	buf.Reset()
+	if err := r.WriteText(&buf, "results"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = `[results]
+games  sente_wins  gote_wins  draws  unfinished
+1      1           0          0      0
+`
+	if s := buf.String(); s != expected {
+		t.Errorf("expected=%v\nactual=%v", expected, s)
+	}
+
+	buf.Reset()
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"senteWins": 1`) || !strings.Contains(buf.String(), `"openings": []`) {
+		t.Errorf("json: %v", buf.String())
+	}
+}
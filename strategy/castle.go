@@ -0,0 +1,76 @@
+package strategy
+
+import (
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type placement struct {
+	x, y  int32
+	piece ptypes.Piece_Id
+}
+
+type castlePattern struct {
+	name   string
+	pieces []placement
+}
+
+// castles are the castles seen from Sente, the more developed first.
+var castles = []*castlePattern{
+	{"居飛車穴熊", []placement{{9, 9, ptypes.Piece_GYOKU}, {9, 8, ptypes.Piece_KYOU}, {8, 8, ptypes.Piece_GIN}}},
+	{"振り飛車穴熊", []placement{{1, 9, ptypes.Piece_GYOKU}, {1, 8, ptypes.Piece_KYOU}, {2, 8, ptypes.Piece_GIN}}},
+	{"銀冠", []placement{{2, 8, ptypes.Piece_GYOKU}, {2, 7, ptypes.Piece_GIN}, {3, 8, ptypes.Piece_KIN}}},
+	{"高美濃囲い", []placement{{2, 8, ptypes.Piece_GYOKU}, {3, 8, ptypes.Piece_GIN}, {4, 9, ptypes.Piece_KIN}, {4, 7, ptypes.Piece_KIN}}},
+	{"美濃囲い", []placement{{2, 8, ptypes.Piece_GYOKU}, {3, 8, ptypes.Piece_GIN}, {4, 9, ptypes.Piece_KIN}}},
+	{"金矢倉", []placement{{8, 8, ptypes.Piece_GYOKU}, {7, 7, ptypes.Piece_GIN}, {7, 8, ptypes.Piece_KIN}, {6, 7, ptypes.Piece_KIN}}},
+	{"銀矢倉", []placement{{8, 8, ptypes.Piece_GYOKU}, {7, 7, ptypes.Piece_GIN}, {7, 8, ptypes.Piece_KIN}, {6, 7, ptypes.Piece_GIN}}},
+	{"左美濃", []placement{{8, 8, ptypes.Piece_GYOKU}, {7, 8, ptypes.Piece_GIN}, {6, 9, ptypes.Piece_KIN}}},
+	{"雁木囲い", []placement{{6, 7, ptypes.Piece_GIN}, {5, 7, ptypes.Piece_GIN}, {7, 8, ptypes.Piece_KIN}}},
+	{"舟囲い", []placement{{7, 8, ptypes.Piece_GYOKU}, {6, 9, ptypes.Piece_KIN}, {5, 8, ptypes.Piece_KIN}}},
+}
+
+var yaguraCastles = map[string]bool{
+	"金矢倉": true,
+	"銀矢倉": true,
+}
+
+// relative returns the square seen from the side: Gote's squares are rotated
+// so that both sides start from the 9th rank.
+func relative(side board.Side, x, y int32) (int32, int32) {
+	if side == board.Gote {
+		return 10 - x, 10 - y
+	}
+	return x, y
+}
+
+// relBoard is the board seen from a side.
+type relBoard struct {
+	b    *board.Board
+	side board.Side
+}
+
+func (b relBoard) at(x, y int32) board.Square {
+	x, y = relative(b.side, x, y)
+	return b.b.At(x, y)
+}
+
+func (b relBoard) has(x, y int32, p ptypes.Piece_Id) bool {
+	sq := b.at(x, y)
+	return sq.Piece == p && sq.Side == b.side
+}
+
+// Castle returns the name of the castle (囲い) of the side in the position,
+// or "" if it is not one of the known castles.
+func Castle(b *board.Board, side board.Side) string {
+	rb := relBoard{b: b, side: side}
+next:
+	for _, c := range castles {
+		for _, p := range c.pieces {
+			if !rb.has(p.x, p.y, p.piece) {
+				continue next
+			}
+		}
+		return c.name
+	}
+	return ""
+}
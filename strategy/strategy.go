@@ -0,0 +1,212 @@
+package strategy
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+// HeaderName is the header of the opening.
+const HeaderName = "戦型"
+
+const (
+	Aiburibisha      = "相振り飛車"
+	Nakabisha        = "中飛車"
+	GokigenNakabisha = "ゴキゲン中飛車"
+	Shikenbisha      = "四間飛車"
+	Sankenbisha      = "三間飛車"
+	Mukaibisha       = "向かい飛車"
+	Yokofudori       = "横歩取り"
+	Kakugawari       = "角換わり"
+	Aigakari         = "相掛かり"
+	Yagura           = "矢倉"
+)
+
+const (
+	defaultMaxPly     = 60
+	defaultOpeningPly = 30
+)
+
+// rangingNames are the ranging rooks by the file seen from the side. The
+// central rook is ゴキゲン中飛車 if the bishop diagonal is open.
+var rangingNames = map[int32]string{
+	5: Nakabisha,
+	6: Shikenbisha,
+	7: Sankenbisha,
+	8: Mukaibisha,
+}
+
+type Result struct {
+	// Opening is the opening family, or "" if it is not classified.
+	Opening string
+	// Rooks are the ranging rooks (振り飛車) of Sente and Gote, or "" for
+	// static rooks (居飛車).
+	Rooks [2]string
+	// Castles are the last castles of Sente and Gote found, or "" if none.
+	Castles [2]string
+}
+
+type classifier struct {
+	maxPly     int32
+	openingPly int32
+}
+
+type ClassifyOption func(*classifier)
+
+// ClassifyMaxPly sets the number of moves to look for castles and ranging
+// rooks in. The default is 60.
+func ClassifyMaxPly(n int) ClassifyOption {
+	return func(c *classifier) {
+		c.maxPly = int32(n)
+	}
+}
+
+// ClassifyOpeningPly sets the number of moves to look for the bishop exchange
+// of 角換わり and the rook pawns of 相掛かり in. The default is 30.
+func ClassifyOpeningPly(n int) ClassifyOption {
+	return func(c *classifier) {
+		c.openingPly = int32(n)
+	}
+}
+
+// bishopOpen reports whether the side has not closed the diagonal of its bishop
+// (角道) with its own pawns.
+func bishopOpen(rb relBoard) bool {
+	return !rb.has(7, 7, ptypes.Piece_FU) && !rb.has(6, 6, ptypes.Piece_FU)
+}
+
+// Classify classifies the main line of the game by the piece placements in its
+// first moves. The opening is classified only for even games (平手).
+func Classify(k *ptypes.Kif, ops ...ClassifyOption) (*Result, error) {
+	c := &classifier{
+		maxPly:     defaultMaxPly,
+		openingPly: defaultOpeningPly,
+	}
+	for _, f := range ops {
+		f(c)
+	}
+
+	b, err := board.FromKif(k)
+	if err != nil {
+		return nil, err
+	}
+	even := b.Key() == board.New().Key()
+
+	ret := &Result{}
+	var (
+		pawnAdvanced [2]bool
+		aigakari     bool
+		yokofudori   bool
+		bishopTrade  bool
+	)
+	for _, step := range k.GetSteps() {
+		if b.Ply >= c.maxPly || step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+
+		side := b.Turn
+		dst := step.Dst
+		if dst == nil && b.Last != nil {
+			dst = b.Last.Dst
+		}
+		var captured board.Square
+		if dst != nil {
+			captured = b.At(dst.X, dst.Y)
+		}
+
+		if err := b.Apply(step); err != nil {
+			return nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		last := b.Last
+		rb := relBoard{b: b, side: side}
+
+		if last.Piece == ptypes.Piece_HISHA && last.Src != nil && last.Modifier != ptypes.Modifier_PROMOTE {
+			x, y := relative(side, last.Dst.X, last.Dst.Y)
+			if name, ok := rangingNames[x]; ok && y >= 6 {
+				if name == Nakabisha && bishopOpen(rb) {
+					name = GokigenNakabisha
+				}
+				ret.Rooks[side] = name
+			} else if x == 2 && y >= 6 {
+				// back to the file of 居飛車
+				ret.Rooks[side] = ""
+			}
+			if x == 3 && y == 4 && captured.Piece == ptypes.Piece_FU && captured.Side != side {
+				yokofudori = true
+			}
+		}
+
+		if b.Ply <= c.openingPly {
+			if b.Hand(board.Sente, ptypes.Piece_KAKU) > 0 && b.Hand(board.Gote, ptypes.Piece_KAKU) > 0 {
+				bishopTrade = true
+			}
+			if !pawnAdvanced[side] && last.Piece == ptypes.Piece_FU {
+				if x, y := relative(side, last.Dst.X, last.Dst.Y); x == 2 && y == 5 {
+					pawnAdvanced[side] = true
+					if pawnAdvanced[side.Opponent()] {
+						// the rook pawns met before both of the bishop diagonals opened
+						aigakari = !bishopOpen(relBoard{b: b, side: board.Sente}) || !bishopOpen(relBoard{b: b, side: board.Gote})
+					}
+				}
+			}
+		}
+
+		for _, s := range []board.Side{board.Sente, board.Gote} {
+			if castle := Castle(b, s); castle != "" {
+				ret.Castles[s] = castle
+			}
+		}
+	}
+
+	if !even {
+		return ret, nil
+	}
+
+	sente, gote := ret.Rooks[board.Sente], ret.Rooks[board.Gote]
+	switch {
+	case sente != "" && gote != "":
+		ret.Opening = Aiburibisha
+	case sente != "" || gote != "":
+		ret.Opening = sente + gote
+	case yokofudori:
+		ret.Opening = Yokofudori
+	case bishopTrade:
+		ret.Opening = Kakugawari
+	case aigakari:
+		ret.Opening = Aigakari
+	case yaguraCastles[ret.Castles[board.Sente]] || yaguraCastles[ret.Castles[board.Gote]]:
+		ret.Opening = Yagura
+	}
+	return ret, nil
+}
+
+// Fill sets the 戦型 header to the opening of the game if the header is
+// missing or empty. It returns the opening classified, or "" if the header
+// was not set.
+func Fill(k *ptypes.Kif, ops ...ClassifyOption) (string, error) {
+	var header *ptypes.Header
+	for _, h := range k.Headers {
+		if h.Name == HeaderName {
+			if h.Value != "" {
+				return "", nil
+			}
+			header = h
+		}
+	}
+
+	r, err := Classify(k, ops...)
+	if err != nil {
+		return "", err
+	}
+	if r.Opening == "" {
+		return "", nil
+	}
+
+	if header == nil {
+		header = &ptypes.Header{Name: HeaderName}
+		k.Headers = append(k.Headers, header)
+	}
+	header.Value = r.Opening
+	return r.Opening, nil
+}
@@ -0,0 +1,104 @@
+package strategy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/strategy"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    string
+		expected string
+	}{
+		{"gote shikenbisha", "7g7f 3c3d 2g2f 4c4d 2f2e 2b3c 3i4h 8b4b", strategy.Shikenbisha},
+		{"sente sankenbisha", "7g7f 3c3d 2h7h", strategy.Sankenbisha},
+		{"gokigen", "7g7f 3c3d 2g2f 5c5d 2f2e 8b5b", strategy.GokigenNakabisha},
+		{"closed nakabisha", "5g5f 3c3d 2h5h", strategy.Nakabisha},
+		{"mukaibisha", "7g7f 3c3d 6g6f 2b3c 2g2f 8b2b", strategy.Mukaibisha},
+		{"aiburibisha", "7g7f 3c3d 2h7h 8b3b", strategy.Aiburibisha},
+		{"kakugawari", "7g7f 8c8d 2g2f 3c3d 8h2b+ 3a2b", strategy.Kakugawari},
+		{"yokofudori", "7g7f 3c3d 2g2f 8c8d 2f2e 8d8e 2e2d 2c2d 2h2d 8e8f 8g8f 8b8f 2d3d", strategy.Yokofudori},
+		{"aigakari", "2g2f 8c8d 2f2e 8d8e", strategy.Aigakari},
+		{"yagura", "7g7f 1c1d 6g6f 9c9d 6i7h 1d1e 7i6h 9d9e 6h7g 3a3b 4i5h 7a7b " +
+			"5h6g 3b3a 8h7i 7b7a 7i6h 6a5b 5i6i 5b6a 6i7i 4a4b 7i8h 4b4a", strategy.Yagura},
+		{"unknown", "7g7f 3c3d", ""},
+	}
+	for _, test := range tests {
+		k, err := kif.ParseSFEN(strings.NewReader("startpos moves " + test.moves))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.name, err)
+		}
+		r, err := strategy.Classify(k)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+		if r.Opening != test.expected {
+			t.Errorf("%v: expected=%v actual=%v", test.name, test.expected, r.Opening)
+		}
+	}
+}
+
+func TestClassify_Handicap(t *testing.T) {
+	k := &ptypes.Kif{Headers: []*ptypes.Header{{Name: "手合割", Value: "角落ち"}}}
+	r, err := strategy.Classify(k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Opening != "" {
+		t.Errorf("handicap games must not be classified: %v", r.Opening)
+	}
+}
+
+func TestCastle(t *testing.T) {
+	tests := []struct {
+		sfen   string
+		side   board.Side
+		castle string
+	}{
+		{"4k4/9/9/9/9/9/9/4G1SK1/5G3 b - 1", board.Sente, "美濃囲い"},
+		{"4k4/9/9/9/9/9/5G3/4G1SK1/9 b - 1", board.Sente, ""},
+		{"4k4/9/9/9/9/9/5G1S1/6GK1/9 b - 1", board.Sente, "銀冠"},
+		{"3g5/1ks1g4/9/9/9/9/9/9/4K4 b - 1", board.Gote, "美濃囲い"},
+		{"4k4/9/9/9/9/9/9/LS7/KN7 b - 1", board.Sente, "居飛車穴熊"},
+		{"4k4/9/9/9/9/9/2SG5/1KG6/9 b - 1", board.Sente, "金矢倉"},
+		{"4k4/9/9/9/9/9/9/9/4K4 b - 1", board.Sente, ""},
+	}
+	for _, test := range tests {
+		b, err := board.FromSFEN(test.sfen)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.sfen, err)
+		}
+		if c := strategy.Castle(b, test.side); c != test.castle {
+			t.Errorf("%v: expected=%v actual=%v", test.sfen, test.castle, c)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	k, err := kif.ParseSFEN(strings.NewReader("startpos moves 7g7f 3c3d 2h7h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o, err := strategy.Fill(k); err != nil || o != strategy.Sankenbisha {
+		t.Fatalf("expected=%v actual=%v err=%v", strategy.Sankenbisha, o, err)
+	}
+	// after 手合割
+	if len(k.Headers) != 2 || k.Headers[1].Name != strategy.HeaderName || k.Headers[1].Value != strategy.Sankenbisha {
+		t.Errorf("headers: %v", k.Headers)
+	}
+
+	k.Headers[1].Value = "四間飛車"
+	if o, err := strategy.Fill(k); err != nil || o != "" {
+		t.Errorf("an existing header must be kept: actual=%v err=%v", o, err)
+	}
+	if k.Headers[1].Value != "四間飛車" {
+		t.Errorf("header overwritten: %v", k.Headers[1].Value)
+	}
+}
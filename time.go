@@ -0,0 +1,86 @@
+package kif
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func toDuration(sec, nanos int32) time.Duration {
+	return time.Duration(sec)*time.Second + time.Duration(nanos)
+}
+
+func fromDuration(d time.Duration) (int32, int32) {
+	return int32(d / time.Second), int32(d % time.Second)
+}
+
+func ThinkingDuration(s *ptypes.Step) time.Duration {
+	return toDuration(s.GetThinkingSec(), s.GetThinkingNanos())
+}
+
+func SetThinkingDuration(s *ptypes.Step, d time.Duration) {
+	s.ThinkingSec, s.ThinkingNanos = fromDuration(d)
+}
+
+func ElapsedDuration(s *ptypes.Step) time.Duration {
+	return toDuration(s.GetElapsedSec(), s.GetElapsedNanos())
+}
+
+func SetElapsedDuration(s *ptypes.Step, d time.Duration) {
+	s.ElapsedSec, s.ElapsedNanos = fromDuration(d)
+}
+
+// elapsedClock holds the total thinking time of each side, indexed by Seq parity.
+type elapsedClock [2]time.Duration
+
+func (c *elapsedClock) add(s *ptypes.Step) time.Duration {
+	i := s.Seq % 2
+	c[i] += ThinkingDuration(s)
+	return c[i]
+}
+
+func walkElapsed(steps []*ptypes.Step, c elapsedClock, f func(*ptypes.Step, time.Duration)) {
+	for _, s := range steps {
+		before := c
+		f(s, c.add(s))
+		for _, v := range s.Variations {
+			walkElapsed(v.Steps, before, f)
+		}
+	}
+}
+
+// RecomputeElapsed sets the elapsed time of each step to the total
+// thinking time of the side that moved, including variations.
+func RecomputeElapsed(k *ptypes.Kif) {
+	walkElapsed(k.Steps, elapsedClock{}, func(s *ptypes.Step, d time.Duration) {
+		SetElapsedDuration(s, d)
+	})
+}
+
+type TimeInconsistency struct {
+	Seq      int32
+	Recorded time.Duration
+	Computed time.Duration
+}
+
+func (t *TimeInconsistency) String() string {
+	return fmt.Sprintf("seq=%v elapsed recorded=%v computed=%v", t.Seq, t.Recorded, t.Computed)
+}
+
+// CheckTimes reports the steps whose elapsed time does not match the
+// total thinking time of the side, compared in whole seconds.
+func CheckTimes(k *ptypes.Kif) []*TimeInconsistency {
+	var ret []*TimeInconsistency
+	walkElapsed(k.Steps, elapsedClock{}, func(s *ptypes.Step, d time.Duration) {
+		recorded := ElapsedDuration(s)
+		if recorded/time.Second != d/time.Second || s.ThinkingSec < 0 {
+			ret = append(ret, &TimeInconsistency{
+				Seq:      s.Seq,
+				Recorded: recorded,
+				Computed: d,
+			})
+		}
+	})
+	return ret
+}
@@ -0,0 +1,40 @@
+package kif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+func TestRecomputeElapsed(t *testing.T) {
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, ThinkingSec: 10, ElapsedSec: 10},
+			{Seq: 2, ThinkingSec: 20, ElapsedSec: 20},
+			{Seq: 3, ThinkingSec: 30, ThinkingNanos: 500000000, ElapsedSec: 99},
+			{Seq: 4, ThinkingSec: 1, ElapsedSec: 21, Variations: []*ptypes.Variation{
+				{Steps: []*ptypes.Step{{Seq: 4, ThinkingSec: 5}}},
+			}},
+		},
+	}
+
+	ts := CheckTimes(k)
+	if len(ts) != 2 || ts[0].Seq != 3 || ts[1].Seq != 4 {
+		t.Fatalf("unexpected inconsistencies: %v", ts)
+	}
+	if e := 40*time.Second + 500*time.Millisecond; ts[0].Computed != e {
+		t.Errorf("computed: expected=%v actual=%v", e, ts[0].Computed)
+	}
+
+	RecomputeElapsed(k)
+	if ts := CheckTimes(k); len(ts) != 0 {
+		t.Fatalf("unexpected inconsistencies: %v", ts)
+	}
+	if s := k.Steps[2]; s.ElapsedSec != 40 || s.ElapsedNanos != 500000000 {
+		t.Errorf("seq=3: unexpected elapsed: %v %v", s.ElapsedSec, s.ElapsedNanos)
+	}
+	if s := k.Steps[3].Variations[0].Steps[0]; s.ElapsedSec != 25 {
+		t.Errorf("variation: expected=25 actual=%v", s.ElapsedSec)
+	}
+}
@@ -0,0 +1,401 @@
+package usi
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/yunomu/kif"
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+var ErrTerminated = errors.New("engine terminated")
+
+type OptionSpec struct {
+	Name    string
+	Type    string
+	Default string
+	Min     string
+	Max     string
+	Vars    []string
+}
+
+func parseOptionSpec(line string) *OptionSpec {
+	tokens := strings.Fields(line)
+	o := &OptionSpec{}
+	for i := 1; i+1 < len(tokens); i += 2 {
+		switch v := tokens[i+1]; tokens[i] {
+		case "name":
+			o.Name = v
+		case "type":
+			o.Type = v
+		case "default":
+			o.Default = v
+		case "min":
+			o.Min = v
+		case "max":
+			o.Max = v
+		case "var":
+			o.Vars = append(o.Vars, v)
+		}
+	}
+	return o
+}
+
+type Engine struct {
+	Name    string
+	Author  string
+	Options []*OptionSpec
+
+	args     []string
+	dir      string
+	options  [][2]string
+	logger   func(send bool, line string)
+	stopWait time.Duration
+
+	cmd   *exec.Cmd
+	in    io.WriteCloser
+	lines chan string
+}
+
+type EngineOption func(*Engine)
+
+func EngineArgs(args ...string) EngineOption {
+	return func(e *Engine) {
+		e.args = args
+	}
+}
+
+func EngineDir(dir string) EngineOption {
+	return func(e *Engine) {
+		e.dir = dir
+	}
+}
+
+// EngineSetOption sends setoption to the engine during the handshake.
+func EngineSetOption(name, value string) EngineOption {
+	return func(e *Engine) {
+		e.options = append(e.options, [2]string{name, value})
+	}
+}
+
+// EngineLogger sets a function called with every line sent to and received from the engine.
+func EngineLogger(f func(send bool, line string)) EngineOption {
+	return func(e *Engine) {
+		e.logger = f
+	}
+}
+
+// Start launches the engine and performs the usi and isready handshake.
+func Start(ctx context.Context, path string, ops ...EngineOption) (*Engine, error) {
+	e := &Engine{
+		logger:   func(bool, string) {},
+		stopWait: 5 * time.Second,
+		lines:    make(chan string, 64),
+	}
+	for _, f := range ops {
+		f(e)
+	}
+
+	e.cmd = exec.Command(path, e.args...)
+	e.cmd.Dir = e.dir
+	in, err := e.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	e.in = in
+	out, err := e.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := e.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(e.lines)
+		s := bufio.NewScanner(out)
+		for s.Scan() {
+			line := strings.TrimSpace(s.Text())
+			e.logger(false, line)
+			e.lines <- line
+		}
+	}()
+
+	if err := e.handshake(ctx); err != nil {
+		e.kill()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *Engine) send(format string, args ...interface{}) error {
+	line := fmt.Sprintf(format, args...)
+	e.logger(true, line)
+	_, err := io.WriteString(e.in, line+"\n")
+	return err
+}
+
+// readLine returns the next line from the engine.
+func (e *Engine) readLine(ctx context.Context) (string, error) {
+	select {
+	case line, ok := <-e.lines:
+		if !ok {
+			return "", ErrTerminated
+		}
+		return line, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (e *Engine) handshake(ctx context.Context) error {
+	if err := e.send("usi"); err != nil {
+		return err
+	}
+	for {
+		line, err := e.readLine(ctx)
+		if err != nil {
+			return errors.Wrap(err, "usi")
+		}
+		switch {
+		case line == "usiok":
+			for _, o := range e.options {
+				if err := e.send("setoption name %s value %s", o[0], o[1]); err != nil {
+					return err
+				}
+			}
+			return e.IsReady(ctx)
+		case strings.HasPrefix(line, "id name "):
+			e.Name = strings.TrimPrefix(line, "id name ")
+		case strings.HasPrefix(line, "id author "):
+			e.Author = strings.TrimPrefix(line, "id author ")
+		case strings.HasPrefix(line, "option "):
+			e.Options = append(e.Options, parseOptionSpec(line))
+		}
+	}
+}
+
+func (e *Engine) IsReady(ctx context.Context) error {
+	if err := e.send("isready"); err != nil {
+		return err
+	}
+	for {
+		line, err := e.readLine(ctx)
+		if err != nil {
+			return errors.Wrap(err, "isready")
+		}
+		if line == "readyok" {
+			return nil
+		}
+	}
+}
+
+func (e *Engine) NewGame() error {
+	return e.send("usinewgame")
+}
+
+// SetPositionSFEN sets the position from an SFEN string (or "startpos") and USI moves.
+func (e *Engine) SetPositionSFEN(sfen string, moves ...string) error {
+	pos := "startpos"
+	if sfen != "startpos" && sfen != board.StartPos {
+		pos = "sfen " + sfen
+	}
+	if len(moves) == 0 {
+		return e.send("position %s", pos)
+	}
+	return e.send("position %s moves %s", pos, strings.Join(moves, " "))
+}
+
+// SetPosition sets the position after ply moves of the main line.
+// A negative ply means the end of the game.
+func (e *Engine) SetPosition(k *ptypes.Kif, ply int32) error {
+	sfen, moves, err := Position(k, ply)
+	if err != nil {
+		return err
+	}
+	return e.SetPositionSFEN(sfen, moves...)
+}
+
+// Position returns the initial position and the USI moves to reach the
+// position after ply moves of the main line.
+func Position(k *ptypes.Kif, ply int32) (string, []string, error) {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return "", nil, err
+	}
+	sfen := b.SFEN()
+
+	var moves []string
+	for _, step := range k.Steps {
+		if ply >= 0 && b.Ply >= ply {
+			break
+		}
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			break
+		}
+		if err := b.Apply(step); err != nil {
+			return "", nil, errors.Wrapf(err, "seq=%v", step.Seq)
+		}
+		moves = append(moves, kif.StepToMove(b.Last))
+	}
+
+	return sfen, moves, nil
+}
+
+type GoParams struct {
+	Depth    int
+	Nodes    int64
+	MoveTime time.Duration
+	Infinite bool
+
+	BTime   time.Duration
+	WTime   time.Duration
+	Byoyomi time.Duration
+	BInc    time.Duration
+	WInc    time.Duration
+}
+
+func millis(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Millisecond), 10)
+}
+
+func (p *GoParams) command() string {
+	ss := []string{"go"}
+	if p.BTime != 0 || p.WTime != 0 || p.Byoyomi != 0 || p.BInc != 0 || p.WInc != 0 {
+		ss = append(ss, "btime", millis(p.BTime), "wtime", millis(p.WTime))
+		if p.BInc != 0 || p.WInc != 0 {
+			ss = append(ss, "binc", millis(p.BInc), "winc", millis(p.WInc))
+		} else {
+			ss = append(ss, "byoyomi", millis(p.Byoyomi))
+		}
+	}
+	if p.Depth != 0 {
+		ss = append(ss, "depth", strconv.Itoa(p.Depth))
+	}
+	if p.Nodes != 0 {
+		ss = append(ss, "nodes", strconv.FormatInt(p.Nodes, 10))
+	}
+	if p.MoveTime != 0 {
+		ss = append(ss, "movetime", millis(p.MoveTime))
+	}
+	if p.Infinite {
+		ss = append(ss, "infinite")
+	}
+	return strings.Join(ss, " ")
+}
+
+type Result struct {
+	BestMove string
+	Ponder   string
+	// Info is the last info with a score of the first principal variation.
+	Info *Info
+}
+
+const (
+	MoveResign = "resign"
+	MoveWin    = "win"
+)
+
+// Go starts searching and waits for bestmove. When ctx is done,
+// stop is sent and the result found so far is returned. If the engine does
+// not return bestmove in time after stop, the partial result is returned
+// with the error of ctx, and the engine should be quit.
+func (e *Engine) Go(ctx context.Context, params *GoParams, onInfo func(*Info)) (*Result, error) {
+	if err := e.send(params.command()); err != nil {
+		return nil, err
+	}
+
+	ret := &Result{}
+	done := ctx.Done()
+	var stopped <-chan time.Time
+	for {
+		var line string
+		var ok bool
+		select {
+		case line, ok = <-e.lines:
+			if !ok {
+				return nil, ErrTerminated
+			}
+		case <-done:
+			if err := e.send("stop"); err != nil {
+				return nil, err
+			}
+			done = nil
+			stopped = time.After(e.stopWait)
+			continue
+		case <-stopped:
+			return ret, ctx.Err()
+		}
+
+		switch {
+		case strings.HasPrefix(line, "info "):
+			info, err := ParseInfo(line)
+			if err != nil {
+				continue
+			}
+			if onInfo != nil {
+				onInfo(info)
+			}
+			if info.Score != nil && info.MultiPV <= 1 {
+				ret.Info = info
+			}
+		case strings.HasPrefix(line, "bestmove"):
+			tokens := strings.Fields(line)
+			if len(tokens) >= 2 {
+				ret.BestMove = tokens[1]
+			}
+			if len(tokens) >= 4 && tokens[2] == "ponder" {
+				ret.Ponder = tokens[3]
+			}
+			return ret, nil
+		}
+	}
+}
+
+// GameOver tells the engine the result: "win", "lose" or "draw".
+func (e *Engine) GameOver(result string) error {
+	return e.send("gameover %s", result)
+}
+
+func (e *Engine) kill() {
+	e.cmd.Process.Kill()
+	go func() {
+		for range e.lines {
+		}
+	}()
+	e.cmd.Wait()
+}
+
+// Quit sends quit and waits for the engine to exit, killing it if it does not.
+func (e *Engine) Quit() error {
+	if err := e.send("quit"); err != nil {
+		e.kill()
+		return err
+	}
+	e.in.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		for range e.lines {
+		}
+		done <- e.cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(e.stopWait):
+		e.cmd.Process.Kill()
+		return <-done
+	}
+}
@@ -0,0 +1,180 @@
+package usi
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yunomu/kif/ptypes"
+)
+
+const fakeEngineEnv = "KIF_USI_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEngineEnv) != "" {
+		fakeEngine()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeEngine is a minimal USI engine run by the test binary itself.
+func fakeEngine() {
+	var moves int
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		tokens := strings.Fields(s.Text())
+		if len(tokens) == 0 {
+			continue
+		}
+		switch tokens[0] {
+		case "usi":
+			fmt.Println("id name FakeEngine 1.0")
+			fmt.Println("id author kif")
+			fmt.Println("option name USI_Hash type spin default 256 min 1 max 1024")
+			fmt.Println("usiok")
+		case "isready":
+			fmt.Println("readyok")
+		case "position":
+			moves = 0
+			for i, t := range tokens {
+				if t == "moves" {
+					moves = len(tokens) - i - 1
+				}
+			}
+		case "go":
+			fmt.Printf("info string moves %d\n", moves)
+			fmt.Println("info depth 1 seldepth 1 score cp 10 nodes 100 pv 7g7f 3c3d")
+			if len(tokens) == 3 && tokens[1] == "depth" && tokens[2] == "99" {
+				// hangs ignoring stop
+				for s.Scan() && s.Text() != "quit" {
+				}
+				return
+			}
+			if tokens[len(tokens)-1] == "infinite" {
+				for s.Scan() && s.Text() != "stop" {
+				}
+			}
+			fmt.Println("info depth 2 seldepth 3 time 5 score cp -20 upperbound nodes 1000 nps 200000 pv 2g2f 8c8d")
+			fmt.Println("bestmove 2g2f ponder 8c8d")
+		case "quit":
+			return
+		}
+	}
+}
+
+func startFake(t *testing.T) *Engine {
+	os.Setenv(fakeEngineEnv, "1")
+	defer os.Unsetenv(fakeEngineEnv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	e, err := Start(ctx, os.Args[0], EngineSetOption("USI_Hash", "16"))
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	return e
+}
+
+func TestEngine(t *testing.T) {
+	e := startFake(t)
+	defer e.Quit()
+
+	if e.Name != "FakeEngine 1.0" || e.Author != "kif" {
+		t.Errorf("unexpected id: name=%v author=%v", e.Name, e.Author)
+	}
+	if len(e.Options) != 1 || e.Options[0].Name != "USI_Hash" || e.Options[0].Max != "1024" {
+		t.Errorf("unexpected options: %v", e.Options)
+	}
+
+	if err := e.NewGame(); err != nil {
+		t.Fatalf("usinewgame: %v", err)
+	}
+	k := &ptypes.Kif{
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+			{Seq: 2, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}},
+		},
+	}
+	if err := e.SetPosition(k, -1); err != nil {
+		t.Fatalf("position: %v", err)
+	}
+
+	var infos []*Info
+	r, err := e.Go(context.Background(), &GoParams{Depth: 2}, func(i *Info) {
+		infos = append(infos, i)
+	})
+	if err != nil {
+		t.Fatalf("go: %v", err)
+	}
+	if r.BestMove != "2g2f" || r.Ponder != "8c8d" {
+		t.Errorf("unexpected bestmove: %+v", r)
+	}
+	if len(infos) != 3 || infos[0].String != "moves 2" {
+		t.Errorf("unexpected infos: %v", infos)
+	}
+	if i := r.Info; i == nil || i.Depth != 2 || i.Score.Cp != -20 || !i.Score.Upperbound || len(i.PV) != 2 {
+		t.Errorf("unexpected info: %+v", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if r, err := e.Go(ctx, &GoParams{Infinite: true}, nil); err != nil || r.BestMove != "2g2f" {
+		t.Errorf("go infinite: %v %v", r, err)
+	}
+}
+
+func TestEngine_GoHung(t *testing.T) {
+	e := startFake(t)
+	defer e.Quit()
+	e.stopWait = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	r, err := e.Go(ctx, &GoParams{Depth: 99}, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded: %v", err)
+	}
+	if r == nil || r.BestMove != "" || r.Info == nil || r.Info.Depth != 1 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	i, err := ParseInfo("info depth 12 seldepth 20 score mate -5 pv 8h2b+ 3a2b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if i.Depth != 12 || !i.Score.IsMate || i.Score.Mate != -5 || i.PV[0] != "8h2b+" {
+		t.Errorf("unexpected info: %+v", i)
+	}
+
+	if _, err := ParseInfo("info depth x"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestPosition(t *testing.T) {
+	k := &ptypes.Kif{
+		Headers: []*ptypes.Header{{Name: "手合割", Value: "角落ち"}},
+		Steps: []*ptypes.Step{
+			{Seq: 1, Dst: &ptypes.Pos{X: 3, Y: 4}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 3, Y: 3}},
+			{Seq: 2, Dst: &ptypes.Pos{X: 7, Y: 6}, Piece: ptypes.Piece_FU, Src: &ptypes.Pos{X: 7, Y: 7}},
+			{Seq: 3, FinishedStatus: ptypes.FinishedStatus_SURRENDER},
+		},
+	}
+	sfen, moves, err := Position(k, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := "lnsgkgsnl/1r7/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL w - 1"; sfen != e {
+		t.Errorf("expected=%v actual=%v", e, sfen)
+	}
+	if strings.Join(moves, " ") != "3c3d 7g7f" {
+		t.Errorf("unexpected moves: %v", moves)
+	}
+}
@@ -0,0 +1,140 @@
+package usi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Score struct {
+	Cp int
+	// Mate is the number of plies to mate, negative when being mated.
+	// A mate of unknown distance ("mate +" or "mate -") is 1 or -1.
+	Mate   int
+	IsMate bool
+
+	Lowerbound bool
+	Upperbound bool
+}
+
+func (s *Score) String() string {
+	if s.IsMate {
+		return fmt.Sprintf("mate %d", s.Mate)
+	}
+	return fmt.Sprintf("cp %d", s.Cp)
+}
+
+type Info struct {
+	Depth    int
+	SelDepth int
+	Time     time.Duration
+	Nodes    int64
+	NPS      int64
+	HashFull int
+	MultiPV  int
+	Score    *Score
+	CurrMove string
+	PV       []string
+	String   string
+}
+
+func parseInt(tokens []string, i int) (int64, error) {
+	if i >= len(tokens) {
+		return 0, fmt.Errorf("missing value")
+	}
+	return strconv.ParseInt(tokens[i], 10, 64)
+}
+
+// ParseInfo parses an info line sent by the engine.
+func ParseInfo(line string) (*Info, error) {
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 || tokens[0] != "info" {
+		return nil, fmt.Errorf("not an info line: %q", line)
+	}
+
+	info := &Info{}
+	for i := 1; i < len(tokens); i++ {
+		var n int64
+		var err error
+		switch key := tokens[i]; key {
+		case "depth", "seldepth", "time", "nodes", "nps", "hashfull", "multipv":
+			i++
+			n, err = parseInt(tokens, i)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v: %q", key, line)
+			}
+			switch key {
+			case "depth":
+				info.Depth = int(n)
+			case "seldepth":
+				info.SelDepth = int(n)
+			case "time":
+				info.Time = time.Duration(n) * time.Millisecond
+			case "nodes":
+				info.Nodes = n
+			case "nps":
+				info.NPS = n
+			case "hashfull":
+				info.HashFull = int(n)
+			case "multipv":
+				info.MultiPV = int(n)
+			}
+		case "currmove":
+			i++
+			if i < len(tokens) {
+				info.CurrMove = tokens[i]
+			}
+		case "score":
+			i++
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("invalid score: %q", line)
+			}
+			s := &Score{}
+			switch tokens[i] {
+			case "cp":
+				n, err = parseInt(tokens, i+1)
+				if err != nil {
+					return nil, fmt.Errorf("invalid score: %q", line)
+				}
+				s.Cp = int(n)
+			case "mate":
+				s.IsMate = true
+				switch v := tokens[i+1]; v {
+				case "+":
+					s.Mate = 1
+				case "-":
+					s.Mate = -1
+				default:
+					n, err = strconv.ParseInt(v, 10, 64)
+					if err != nil {
+						return nil, fmt.Errorf("invalid score: %q", line)
+					}
+					s.Mate = int(n)
+				}
+			default:
+				return nil, fmt.Errorf("invalid score: %q", line)
+			}
+			i++
+			if i+1 < len(tokens) {
+				switch tokens[i+1] {
+				case "lowerbound":
+					s.Lowerbound = true
+					i++
+				case "upperbound":
+					s.Upperbound = true
+					i++
+				}
+			}
+			info.Score = s
+		case "pv":
+			info.PV = append([]string{}, tokens[i+1:]...)
+			i = len(tokens)
+		case "string":
+			info.String = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		}
+	}
+
+	return info, nil
+}
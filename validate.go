@@ -0,0 +1,67 @@
+package kif
+
+import (
+	"fmt"
+
+	"github.com/yunomu/kif/board"
+	"github.com/yunomu/kif/ptypes"
+)
+
+type ValidationError struct {
+	// Seq is the move number of the error, or 0 for the game.
+	Seq     int32
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("seq=%d: %s", e.Seq, e.Message)
+}
+
+func validateSteps(b *board.Board, steps []*ptypes.Step) []*ValidationError {
+	var ret []*ValidationError
+	for i, step := range steps {
+		if seq := b.Ply + 1; step.Seq != seq {
+			ret = append(ret, &ValidationError{
+				Seq:     step.Seq,
+				Message: fmt.Sprintf("move number is %d, expected %d", step.Seq, seq),
+			})
+		}
+
+		for _, v := range step.Variations {
+			ret = append(ret, validateSteps(b.Clone(), v.Steps)...)
+		}
+
+		if step.FinishedStatus != ptypes.FinishedStatus_NOT_FINISHED {
+			if i != len(steps)-1 {
+				ret = append(ret, &ValidationError{
+					Seq:     step.Seq,
+					Message: "moves after the end of the game",
+				})
+			}
+			break
+		}
+
+		if err := b.Validate(step); err != nil {
+			return append(ret, &ValidationError{Seq: step.Seq, Message: err.Error()})
+		}
+		if err := b.Apply(step); err != nil {
+			return append(ret, &ValidationError{Seq: step.Seq, Message: err.Error()})
+		}
+	}
+	return ret
+}
+
+// Validate checks that all moves including variations are legal and numbered
+// in order, and that the recorded elapsed times match the thinking times.
+func Validate(k *ptypes.Kif) []*ValidationError {
+	b, err := board.FromKif(k)
+	if err != nil {
+		return []*ValidationError{{Message: err.Error()}}
+	}
+
+	ret := validateSteps(b, k.Steps)
+	for _, t := range CheckTimes(k) {
+		ret = append(ret, &ValidationError{Seq: t.Seq, Message: t.String()})
+	}
+	return ret
+}
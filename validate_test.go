@@ -0,0 +1,27 @@
+package kif
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(variationKIF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs := Validate(k); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	// 同　飛(72) from an empty square in a nested variation, and a skipped move number
+	k.Steps[2].Variations[0].Steps[1].Variations[0].Steps[0].Src.X = 7
+	k.Steps[3].Seq = 5
+	errs := Validate(k)
+	if len(errs) != 2 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if errs[0].Seq != 4 || errs[1].Seq != 5 {
+		t.Errorf("unexpected errors: %v %v", errs[0], errs[1])
+	}
+}
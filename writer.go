@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/yunomu/kif/ptypes"
+	"github.com/yunomu/kif/strategy"
+	"golang.org/x/text/encoding/japanese"
 	"golang.org/x/text/transform"
 )
 
@@ -13,6 +17,9 @@ type Format int
 const (
 	Format_KIF Format = iota
 	Format_SFEN
+	Format_HTML
+	Format_CSA
+	Format_JKF
 )
 
 type Writer struct {
@@ -20,6 +27,7 @@ type Writer struct {
 
 	delimiter           string
 	encodingTransformer func(io.Writer) io.Writer
+	fillStrategy        bool
 }
 
 type WriterOption func(*Writer)
@@ -31,9 +39,11 @@ func SetDelimiter(delimiter string) WriterOption {
 }
 
 var sjisWriter = func(wr io.Writer) io.Writer {
-	return transform.NewWriter(wr, sjisDecoder)
+	return transform.NewWriter(wr, japanese.ShiftJIS.NewEncoder())
 }
 
+// WriteEncodingSJIS encodes the output in Shift_JIS, which NewParser reads by
+// default.
 func WriteEncodingSJIS() WriterOption {
 	return func(w *Writer) {
 		w.encodingTransformer = sjisWriter
@@ -48,6 +58,15 @@ func WriteEncodingUTF8() WriterOption {
 	}
 }
 
+// WriteFillStrategy sets the 戦型 header to the classified opening if it is
+// missing or empty. The header is set on a copy of the game, and Write fails
+// for games which cannot be classified.
+func WriteFillStrategy() WriterOption {
+	return func(w *Writer) {
+		w.fillStrategy = true
+	}
+}
+
 func SetFormat(format Format) WriterOption {
 	return func(w *Writer) {
 		w.format = format
@@ -56,6 +75,12 @@ func SetFormat(format Format) WriterOption {
 			w.delimiter = "\n"
 		case Format_SFEN:
 			w.delimiter = " "
+		case Format_HTML:
+			w.delimiter = "\n"
+		case Format_CSA:
+			w.delimiter = "\n"
+		case Format_JKF:
+			w.delimiter = "\n"
 		default:
 			panic(fmt.Sprintf("unknown format: %v", format))
 		}
@@ -76,12 +101,17 @@ func NewWriter(ops ...WriterOption) *Writer {
 }
 
 func stepToLine(step *ptypes.Step) string {
+	var branch string
+	if len(step.Variations) != 0 {
+		branch = "+"
+	}
 	return fmt.Sprintf(
-		"%4d %-12s (%s/%s)",
+		"%4d %-12s (%s/%s)%s",
 		step.Seq,
 		PrintMove(step),
 		PrintThinking(step.ThinkingSec),
 		PrintElapsed(step.ElapsedSec),
+		branch,
 	)
 }
 
@@ -116,7 +146,15 @@ func (w *Writer) writeKIF(out io.Writer, kif *ptypes.Kif) error {
 		return err
 	}
 
-	for _, step := range kif.Steps {
+	if err := writeKIFSteps(p, kif.Steps); err != nil {
+		return err
+	}
+
+	return writeKIFVariations(p, kif.Steps)
+}
+
+func writeKIFSteps(p *linePrinter, steps []*ptypes.Step) error {
+	for _, step := range steps {
 		if err := p.Print(stepToLine(step)); err != nil {
 			return err
 		}
@@ -130,7 +168,36 @@ func (w *Writer) writeKIF(out io.Writer, kif *ptypes.Kif) error {
 	return nil
 }
 
+// writeKIFVariations writes the variations from the end of the line
+// so that each branch can be found by its move number.
+func writeKIFVariations(p *linePrinter, steps []*ptypes.Step) error {
+	for i := len(steps) - 1; i >= 0; i-- {
+		for _, v := range steps[i].Variations {
+			if err := p.Print(""); err != nil {
+				return err
+			}
+			if err := p.Print(fmt.Sprintf("%s%d手", variationPrefix, steps[i].Seq)); err != nil {
+				return err
+			}
+			if err := writeKIFSteps(p, v.Steps); err != nil {
+				return err
+			}
+			if err := writeKIFVariations(p, v.Steps); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (w *Writer) Write(out io.Writer, kif *ptypes.Kif) error {
+	if w.fillStrategy {
+		kif = proto.Clone(kif).(*ptypes.Kif)
+		if _, err := strategy.Fill(kif); err != nil {
+			return err
+		}
+	}
 	Normalize(kif)
 
 	switch w.format {
@@ -138,6 +205,14 @@ func (w *Writer) Write(out io.Writer, kif *ptypes.Kif) error {
 		return w.writeKIF(out, kif)
 	case Format_SFEN:
 		return writeSFEN(out, kif.Steps)
+	case Format_HTML:
+		// HTML is always written in UTF-8.
+		return writeHTML(out, kif)
+	case Format_CSA:
+		return w.writeCSA(out, kif)
+	case Format_JKF:
+		// JKF is JSON, always written in UTF-8.
+		return writeJKF(out, kif)
 	default:
 		return fmt.Errorf("unknown format: %v", w.format)
 	}
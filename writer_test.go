@@ -0,0 +1,76 @@
+package kif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yunomu/kif/ptypes"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestWriter_SJIS(t *testing.T) {
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(`先手：宮尾美也
+後手：B
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:10/00:00:10)
+*初手
+   2 投了   ( 0:00/00:00:00)
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(WriteEncodingSJIS()).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name, err := japanese.ShiftJIS.NewEncoder().String("宮尾美也")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(name)) {
+		t.Errorf("not encoded in Shift_JIS: %q", buf.String())
+	}
+
+	k2, err := NewParser().Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if h := k2.Headers; len(h) != 2 || h[0].Value != "宮尾美也" {
+		t.Errorf("headers: %v", h)
+	}
+	if len(k2.Steps) != 2 || len(k2.Steps[0].Notes) != 1 || k2.Steps[0].Notes[0] != "初手" {
+		t.Errorf("steps: %v", k2.Steps)
+	}
+}
+
+func TestWriter_FillStrategy(t *testing.T) {
+	in := `手合割：平手
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:00/00:00:00)
+   2 ３四歩(33)   ( 0:00/00:00:00)
+   3 ７八飛(28)   ( 0:00/00:00:00)
+`
+	k, err := NewParser(ParseEncodingUTF8()).Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(WriteEncodingUTF8(), WriteFillStrategy()).Write(&buf, k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "手合割：平手\n戦型：三間飛車\n") {
+		t.Errorf("unexpected headers:\n%s", out)
+	}
+	if len(k.Headers) != 1 {
+		t.Errorf("the game is modified: %v", k.Headers)
+	}
+
+	k.Steps[2].Src = &ptypes.Pos{X: 5, Y: 5}
+	if err := NewWriter(WriteEncodingUTF8(), WriteFillStrategy()).Write(&buf, k); err == nil {
+		t.Errorf("expected error")
+	}
+}